@@ -3,7 +3,11 @@
 
 package main
 
-import "github.com/urfave/cli/v2"
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
 
 var (
 	tmpDirFlag = &cli.StringFlag{
@@ -19,4 +23,68 @@ var (
 		Usage:   "Directory containing DB files for persistent state",
 		Value:   "./.state/",
 	}
+	requirePlanFlag = &cli.BoolFlag{
+		Name:    "require-plan",
+		EnvVars: []string{"REQUIRE_PLAN"},
+		Usage:   "Require a plan_id from lifecycle-resources-plan for resource create, update and delete",
+	}
+	planTTLFlag = &cli.DurationFlag{
+		Name:    "plan-ttl",
+		EnvVars: []string{"PLAN_TTL"},
+		Usage:   "How long a plan can be applied after it was made",
+		Value:   15 * time.Minute,
+	}
+	planSigningKeyFlag = &cli.StringFlag{
+		Name:    "plan-signing-key",
+		EnvVars: []string{"PLAN_SIGNING_KEY"},
+		Usage:   "Key for signing plan IDs, random per process if empty (plans do not survive restarts)",
+	}
+	operationTimeoutFlag = &cli.DurationFlag{
+		Name:    "operation-timeout",
+		EnvVars: []string{"OPERATION_TIMEOUT"},
+		Usage:   "Deadline for the provider calls of a resource create, update or delete, 0 means none",
+	}
+	providerIdleTimeoutFlag = &cli.DurationFlag{
+		Name:    "provider-idle-timeout",
+		EnvVars: []string{"PROVIDER_IDLE_TIMEOUT"},
+		Usage:   "Stop provider plugins unused for this long, 0 keeps them running until shutdown",
+		Value:   10 * time.Minute,
+	}
+	maxRunningProvidersFlag = &cli.IntFlag{
+		Name:    "max-running-providers",
+		EnvVars: []string{"MAX_RUNNING_PROVIDERS"},
+		Usage:   "Stop the least recently used provider plugins above this many, 0 means no limit",
+		Value:   8,
+	}
+	providerStopTimeoutFlag = &cli.DurationFlag{
+		Name:    "provider-stop-timeout",
+		EnvVars: []string{"PROVIDER_STOP_TIMEOUT"},
+		Usage:   "How long a cancelled or shut down apply waits for the provider to stop and return the partial state to save",
+		Value:   20 * time.Second,
+	}
+	providerInstallationConfigFlag = &cli.StringFlag{
+		Name:    "provider-installation-config",
+		EnvVars: []string{"PROVIDER_INSTALLATION_CONFIG"},
+		Usage:   "JSON file listing provider installation methods, like the provider_installation block of the OpenTofu CLI configuration",
+	}
+	providerFilesystemMirrorFlag = &cli.StringSliceFlag{
+		Name:    "provider-filesystem-mirror",
+		EnvVars: []string{"PROVIDER_FILESYSTEM_MIRROR"},
+		Usage:   "Directory to install providers from before the registry, laid out like an OpenTofu filesystem mirror",
+	}
+	providerNetworkMirrorFlag = &cli.StringFlag{
+		Name:    "provider-network-mirror",
+		EnvVars: []string{"PROVIDER_NETWORK_MIRROR"},
+		Usage:   "URL of an OpenTofu provider network mirror to install providers from before the registry",
+	}
+	providerMirrorsOnlyFlag = &cli.BoolFlag{
+		Name:    "provider-mirrors-only",
+		EnvVars: []string{"PROVIDER_MIRRORS_ONLY"},
+		Usage:   "Only install providers from the configured mirrors, never from the registry",
+	}
+	providerDevOverrideFlag = &cli.StringSliceFlag{
+		Name:    "provider-dev-override",
+		EnvVars: []string{"PROVIDER_DEV_OVERRIDE"},
+		Usage:   "Use a locally built provider binary, or the directory containing it, instead of downloading the provider, as namespace/type=path",
+	}
 )
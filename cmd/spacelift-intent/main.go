@@ -5,17 +5,20 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/spacelift-io/spacelift-intent/storage"
+	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 func main() {
@@ -23,11 +26,30 @@ func main() {
 		Name:        "spacelift-intent-standalone",
 		Usage:       "Spacelift Intent MCP Server",
 		Description: "Infrastructure management server",
-		Flags:       []cli.Flag{tmpDirFlag, dbDirFlag},
+		Flags: []cli.Flag{
+			tmpDirFlag, dbDirFlag, requirePlanFlag, planTTLFlag, planSigningKeyFlag, operationTimeoutFlag, providerIdleTimeoutFlag, maxRunningProvidersFlag, providerStopTimeoutFlag,
+			providerInstallationConfigFlag, providerFilesystemMirrorFlag, providerNetworkMirrorFlag, providerMirrorsOnlyFlag, providerDevOverrideFlag,
+		},
 		Action: func(c *cli.Context) error {
 			tmpDir := c.String(tmpDirFlag.Name)
 			dbDir := c.String(dbDirFlag.Name)
 
+			installation := InstallationConfig{
+				ConfigFile:        c.String(providerInstallationConfigFlag.Name),
+				FilesystemMirrors: c.StringSlice(providerFilesystemMirrorFlag.Name),
+				NetworkMirror:     c.String(providerNetworkMirrorFlag.Name),
+				MirrorsOnly:       c.Bool(providerMirrorsOnlyFlag.Name),
+			}
+			if installation.ConfigFile != "" && (len(installation.FilesystemMirrors) > 0 || installation.NetworkMirror != "" || installation.MirrorsOnly) {
+				return fmt.Errorf("--%s cannot be combined with the provider mirror flags", providerInstallationConfigFlag.Name)
+			}
+
+			devOverrides, err := parseDevOverrides(c.StringSlice(providerDevOverrideFlag.Name))
+			if err != nil {
+				return err
+			}
+			installation.DevOverrides = devOverrides
+
 			dbPath := filepath.Join(dbDir, "state.db")
 			stateStorage, err := storage.NewSQLiteStorage(dbPath)
 			if err != nil {
@@ -39,11 +61,32 @@ func main() {
 				return fmt.Errorf("failed to run migrations: %w", err)
 			}
 
+			planSigningKey := []byte(c.String(planSigningKeyFlag.Name))
+			if len(planSigningKey) == 0 {
+				planSigningKey = make([]byte, 32)
+				if _, err := rand.Read(planSigningKey); err != nil {
+					return fmt.Errorf("failed to generate plan signing key: %w", err)
+				}
+			}
+
 			// Create standalone server
 			config := &Config{
 				TmpDir:  tmpDir,
 				DBDir:   dbDir,
 				Storage: stateStorage,
+				ApplyPolicy: types.ApplyPolicy{
+					Required:   c.Bool(requirePlanFlag.Name),
+					TTL:        c.Duration(planTTLFlag.Name),
+					SigningKey: planSigningKey,
+
+					OperationTimeout: c.Duration(operationTimeoutFlag.Name),
+				},
+				PluginPolicy: types.PluginPolicy{
+					IdleTimeout: c.Duration(providerIdleTimeoutFlag.Name),
+					MaxRunning:  c.Int(maxRunningProvidersFlag.Name),
+					StopTimeout: c.Duration(providerStopTimeoutFlag.Name),
+				},
+				Installation: installation,
 			}
 
 			server, err := newServer(config)
@@ -70,7 +113,8 @@ func main() {
 				stop()
 			}
 
-			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			// The signal cancelled ctx, shutting down gets its own deadline
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
 			defer cancel()
 
 			server.stop(ctx)
@@ -85,3 +129,16 @@ func main() {
 		log.Fatalf("Failed to run application: %v", err)
 	}
 }
+
+// parseDevOverrides parses development overrides given as namespace/type=path
+func parseDevOverrides(values []string) (types.DevOverrides, error) {
+	overrides := make(types.DevOverrides, len(values))
+	for _, value := range values {
+		provider, path, ok := strings.Cut(value, "=")
+		if !ok || strings.Count(provider, "/") != 1 || path == "" {
+			return nil, fmt.Errorf("invalid --%s %q, expected namespace/type=path", providerDevOverrideFlag.Name, value)
+		}
+		overrides[provider] = path
+	}
+	return overrides, nil
+}
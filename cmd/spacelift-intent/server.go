@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"maps"
+	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -25,13 +27,26 @@ type Server struct {
 	storage         types.Storage
 	providerManager types.ProviderManager
 	config          *Config
+	calls           sync.WaitGroup // tool calls in flight, waited for before storage is closed
 }
 
 // Config holds configuration for standalone server
 type Config struct {
-	TmpDir  string
-	DBDir   string
-	Storage types.Storage
+	TmpDir       string
+	DBDir        string
+	Storage      types.Storage
+	ApplyPolicy  types.ApplyPolicy
+	PluginPolicy types.PluginPolicy
+	Installation InstallationConfig
+}
+
+// InstallationConfig holds where providers are installed from, the registry if nothing is set
+type InstallationConfig struct {
+	ConfigFile        string             // Provider installation config file, exclusive with the other settings
+	FilesystemMirrors []string           // Filesystem mirror directories, tried first
+	NetworkMirror     string             // Network mirror URL, tried after the filesystem mirrors
+	MirrorsOnly       bool               // Never fall back to the registry
+	DevOverrides      types.DevOverrides // Locally built providers, merged over those of the config file
 }
 
 // newServer creates a new standalone server instance
@@ -46,9 +61,12 @@ func newServer(config *Config) (*Server, error) {
 	}
 
 	// Create services
-	registryClient := registry.NewOpenTofuClient()
-	providerManager := provider.NewOpenTofuAdapter(config.TmpDir, registryClient)
-	toolHandlers := tools.New(registryClient, providerManager, config.Storage)
+	registryClient, devOverrides, err := newRegistryClient(config.Installation)
+	if err != nil {
+		return nil, err
+	}
+	providerManager := provider.NewOpenTofuAdapter(config.TmpDir, registryClient, config.PluginPolicy, devOverrides)
+	toolHandlers := tools.New(registryClient, providerManager, config.Storage, config.ApplyPolicy)
 
 	// Create server
 	s := &Server{
@@ -66,12 +84,54 @@ func newServer(config *Config) (*Server, error) {
 	})
 
 	for _, tool := range toolHandlers.Tools() {
-		s.mcp.AddTool(&tool.Tool, tool.Handler)
+		s.mcp.AddTool(&tool.Tool, s.trackCall(tool.Handler))
 	}
 
 	return s, nil
 }
 
+// newRegistryClient creates the client providers are installed from, trying the configured mirrors
+// before the registry, and returns the development overrides used instead of installing providers
+func newRegistryClient(config InstallationConfig) (types.RegistryClient, types.DevOverrides, error) {
+	direct := registry.NewOpenTofuClient()
+
+	if config.ConfigFile != "" {
+		methods, devOverrides, err := registry.LoadInstallationConfig(config.ConfigFile, direct)
+		if err != nil {
+			return nil, nil, err
+		}
+		if devOverrides == nil {
+			devOverrides = make(types.DevOverrides)
+		}
+		maps.Copy(devOverrides, config.DevOverrides)
+		return registry.NewInstaller(methods), devOverrides, nil
+	}
+
+	if len(config.FilesystemMirrors) == 0 && config.NetworkMirror == "" {
+		if config.MirrorsOnly {
+			return nil, nil, fmt.Errorf("no provider mirrors configured to install providers from")
+		}
+		return direct, config.DevOverrides, nil
+	}
+
+	var methods []registry.InstallationMethod
+	for _, dir := range config.FilesystemMirrors {
+		methods = append(methods, registry.InstallationMethod{Source: registry.NewFilesystemMirror(dir)})
+	}
+	if config.NetworkMirror != "" {
+		mirror, err := registry.NewNetworkMirror(config.NetworkMirror)
+		if err != nil {
+			return nil, nil, err
+		}
+		methods = append(methods, registry.InstallationMethod{Source: mirror})
+	}
+	if !config.MirrorsOnly {
+		methods = append(methods, registry.InstallationMethod{Source: direct})
+	}
+
+	return registry.NewInstaller(methods), config.DevOverrides, nil
+}
+
 // start starts the server with the given configuration
 func (s *Server) start(ctx context.Context) error {
 	log.Printf("Starting standalone server in stdio mode")
@@ -84,9 +144,12 @@ func (s *Server) start(ctx context.Context) error {
 func (s *Server) stop(ctx context.Context) {
 	log.Println("Stopping standalone server")
 
+	// Providers still applying changes are asked to stop, the tool calls then save the partial state
+	// they return before storage is closed
 	if s.providerManager != nil {
 		s.providerManager.Cleanup(ctx)
 	}
+	s.waitForCalls(ctx)
 
 	if s.storage != nil {
 		if err := s.storage.Close(); err != nil {
@@ -97,6 +160,31 @@ func (s *Server) stop(ctx context.Context) {
 	log.Println("Standalone server stopped")
 }
 
+// trackCall counts a tool call as in flight until its handler returns
+func (s *Server) trackCall(handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.calls.Add(1)
+		defer s.calls.Done()
+
+		return handler(ctx, req)
+	}
+}
+
+// waitForCalls waits for the tool calls in flight to return, at most until ctx is done
+func (s *Server) waitForCalls(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.calls.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("Tool calls still running at shutdown: %v", ctx.Err())
+	}
+}
+
 // AddTool registers an MCP tool handler
 func (s *Server) AddTool(tool *mcp.Tool, handler mcp.ToolHandler) {
 	s.mcp.AddTool(tool, handler)
@@ -0,0 +1,49 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// convertDiagnostics converts the diagnostics of a provider client response, only the schema and
+// function requests still go through it. The provider client does not expose attribute paths, those
+// requests are not about the attributes of a configuration anyway.
+func convertDiagnostics(diags providerops.Diagnostics) types.Diagnostics {
+	result := types.Diagnostics{}
+	if diags == nil {
+		return result
+	}
+
+	for diag := range diags.All() {
+		var severity string
+		switch diag.Severity() {
+		case providerops.DiagnosticError:
+			severity = "error"
+		case providerops.DiagnosticWarning:
+			severity = "warning"
+		default:
+			continue
+		}
+
+		result = append(result, types.Diagnostic{
+			Severity: severity,
+			Summary:  diag.Summary(),
+			Detail:   diag.Detail(),
+		})
+	}
+
+	return result
+}
+
+// checkDiagnostics returns the errors among the diagnostics of a provider response as a *types.DiagnosticsError
+func checkDiagnostics(diags types.Diagnostics) error {
+	if !diags.HasErrors() {
+		return nil
+	}
+
+	return &types.DiagnosticsError{Diagnostics: diags}
+}
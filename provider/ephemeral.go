@@ -0,0 +1,368 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// ephemeralReference matches a reference to a value of an open ephemeral resource, e.g. ${ephemeral.<id>.password}
+var ephemeralReference = regexp.MustCompile(`^\$\{ephemeral\.([0-9a-f-]+)\.([A-Za-z0-9_.-]+)\}$`)
+
+// openEphemeral is an open ephemeral resource. It only lives in memory: it is renewed when the provider
+// asks for it and closed by Cleanup, its values are only ever sent back to providers.
+type openEphemeral struct {
+	id             string
+	resourceType   string
+	providerConfig *types.ProviderConfig
+	providerKey    string
+	result         cty.Value
+
+	private []byte      // guarded by the adapter mu
+	timer   *time.Timer // guarded by the adapter mu
+}
+
+// OpenEphemeralResource opens an ephemeral resource, such as short-lived credentials, and keeps its
+// values in memory until the adapter is cleaned up
+func (a *OpenTofuAdapter) OpenEphemeralResource(ctx context.Context, providerConfig *types.ProviderConfig, ephemeralType string, config map[string]any) (*types.EphemeralResource, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
+
+	opentofuSchema, err := a.getOpentofuEphemeralSchema(ctx, providerConfig, ephemeralType)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
+	}
+	objectType := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// Ephemeral resources may take values of other ephemeral resources in any attribute
+	config, err = a.resolveEphemeralValues(opentofuSchema, config, true)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	configCty, err := a.configToCtyValue(opentofuSchema, config)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
+	}
+
+	// The provider client does not implement ephemeral resources, they go through the raw client
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	opened, openDiags, err := client.openEphemeralResource(ctx, ephemeralType, providerschema.NewDynamicValue(configCty, objectType))
+	diags = append(diags, openDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("open ephemeral resource failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("open ephemeral resource failed: %w", err)
+	}
+
+	result, err := opened.result.AsCtyValue(objectType)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to decode ephemeral resource: %w", err)
+	}
+
+	values, err := a.converter.CtyValueToMap(result)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert ephemeral resource to map: %w", err)
+	}
+
+	resource := &openEphemeral{
+		id:             uuid.NewString(),
+		resourceType:   ephemeralType,
+		providerConfig: providerConfig,
+		providerKey:    providerKey,
+		result:         result,
+		private:        opened.private,
+	}
+
+	a.mu.Lock()
+	a.ephemerals[resource.id] = resource
+	a.scheduleRenew(resource, opened.renewAt)
+	a.mu.Unlock()
+
+	description := &types.EphemeralResource{
+		ID:           resource.id,
+		ResourceType: ephemeralType,
+		Provider:     providerConfig.Name,
+		Values:       values,
+		References:   make(map[string]string, len(values)),
+	}
+	if !opened.renewAt.IsZero() {
+		description.RenewAt = &opened.renewAt
+	}
+
+	for name, attr := range opentofuSchema.Attributes() {
+		if isSensitive(attr) && values[name] != nil {
+			values[name] = "(sensitive)"
+		}
+	}
+	for name := range values {
+		description.References[name] = fmt.Sprintf("${ephemeral.%s.%s}", resource.id, name)
+	}
+
+	return description, diags, nil
+}
+
+// DescribeEphemeralResource returns detailed information about an ephemeral resource type
+func (a *OpenTofuAdapter) DescribeEphemeralResource(ctx context.Context, providerConfig *types.ProviderConfig, ephemeralType string) (*types.TypeDescription, error) {
+	// Ensure provider is loaded
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
+		return nil, err
+	}
+
+	cacheKey, err := providerConfig.FullName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+	}
+
+	schema, exists := a.providerSchema(cacheKey)
+	if !exists {
+		return nil, fmt.Errorf("provider schema not found for %s", providerConfig.Name)
+	}
+
+	ephemeralDescription, exists := schema.EphemeralResources[ephemeralType]
+	if !exists {
+		return nil, fmt.Errorf("ephemeral resource type %s not found in provider %s", ephemeralType, providerConfig.Name)
+	}
+
+	return ephemeralDescription, nil
+}
+
+// getOpentofuEphemeralSchema gets the raw opentofu-providers Schema for an ephemeral resource type
+func (a *OpenTofuAdapter) getOpentofuEphemeralSchema(ctx context.Context, providerConfig *types.ProviderConfig, ephemeralType string) (providerschema.Schema, error) {
+	// Get cached raw schema response
+	schemaResp, err := a.getRawSchema(ctx, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider schema: %w", err)
+	}
+
+	ephemeralSchemas := maps.Collect(schemaResp.ProviderSchema().EphemeralResourceTypeSchemas())
+	opentofuSchema, exists := ephemeralSchemas[ephemeralType]
+	if !exists {
+		return nil, fmt.Errorf("ephemeral resource type %s not found in opentofu schema", ephemeralType)
+	}
+
+	return opentofuSchema, nil
+}
+
+// resolveEphemeralValues replaces references to open ephemeral resources with their values. A reference
+// must be the whole value of a top-level attribute, and unless anyAttribute is set the attribute must be
+// write-only: providers never return those in the state, so the value is not stored anywhere.
+func (a *OpenTofuAdapter) resolveEphemeralValues(schema providerschema.Schema, config map[string]any, anyAttribute bool) (map[string]any, error) {
+	attrs := maps.Collect(schema.Attributes())
+	resolved, cloned := config, false
+
+	for name, value := range config {
+		if !containsEphemeralReference(value) {
+			continue
+		}
+
+		attr, ok := attrs[name]
+		if !ok || !isEphemeralReference(value) {
+			return nil, fmt.Errorf("invalid use of an ephemeral value in %q: references must be the whole value of a top-level attribute", name)
+		}
+		if !anyAttribute && !attr.IsWriteOnly() {
+			return nil, fmt.Errorf("invalid use of an ephemeral value in %q: ephemeral values are never stored, so they can only be used in write-only attributes", name)
+		}
+
+		v, err := a.ephemeralValue(value.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ephemeral value in %q: %w", name, err)
+		}
+
+		if !cloned {
+			resolved, cloned = maps.Clone(config), true
+		}
+		resolved[name] = v
+	}
+
+	return resolved, nil
+}
+
+// ephemeralValue looks up the value a reference points to
+func (a *OpenTofuAdapter) ephemeralValue(reference string) (any, error) {
+	match := ephemeralReference.FindStringSubmatch(reference)
+	if match == nil {
+		return nil, fmt.Errorf("invalid reference %s", reference)
+	}
+
+	a.mu.RLock()
+	resource := a.ephemerals[match[1]]
+	a.mu.RUnlock()
+
+	if resource == nil {
+		return nil, fmt.Errorf("ephemeral resource %s is not open, it expired or the server was restarted since it was opened", match[1])
+	}
+
+	value := resource.result
+	for _, name := range strings.Split(match[2], ".") {
+		if !value.Type().IsObjectType() || !value.Type().HasAttribute(name) || value.IsNull() {
+			return nil, fmt.Errorf("ephemeral resource %s has no value %s", resource.resourceType, match[2])
+		}
+		value = value.GetAttr(name)
+	}
+
+	return a.converter.ctyValueToAny(value)
+}
+
+// scheduleRenew renews an ephemeral resource at the time the provider asked for, must be called with mu held
+func (a *OpenTofuAdapter) scheduleRenew(resource *openEphemeral, renewAt time.Time) {
+	if renewAt.IsZero() {
+		return
+	}
+	resource.timer = time.AfterFunc(time.Until(renewAt), func() { a.renewEphemeral(resource) })
+}
+
+func (a *OpenTofuAdapter) renewEphemeral(resource *openEphemeral) {
+	a.mu.RLock()
+	open := a.ephemerals[resource.id] == resource
+	private := resource.private
+	a.mu.RUnlock()
+
+	if !open {
+		return
+	}
+
+	ctx := context.Background()
+	err := func() error {
+		provider, release, err := a.acquireProvider(ctx, resource.providerConfig, resource.providerKey)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		client, err := newRawClient(provider)
+		if err != nil {
+			return err
+		}
+
+		renewed, diags, err := client.renewEphemeralResource(ctx, resource.resourceType, private)
+		if err != nil {
+			return err
+		}
+		if err := checkDiagnostics(diags); err != nil {
+			return err
+		}
+
+		a.mu.Lock()
+		if a.ephemerals[resource.id] == resource {
+			resource.private = renewed.private
+			a.scheduleRenew(resource, renewed.renewAt)
+		}
+		a.mu.Unlock()
+
+		return nil
+	}()
+
+	if err != nil {
+		log.Printf("Failed to renew ephemeral resource %s (%s), its values can no longer be used: %v", resource.id, resource.resourceType, err)
+
+		a.mu.Lock()
+		if a.ephemerals[resource.id] == resource {
+			delete(a.ephemerals, resource.id)
+		}
+		a.mu.Unlock()
+	}
+}
+
+// closeEphemerals closes every open ephemeral resource, before the plugins they belong to are stopped
+func (a *OpenTofuAdapter) closeEphemerals(ctx context.Context) {
+	a.mu.Lock()
+	ephemerals := a.ephemerals
+	a.ephemerals = make(map[string]*openEphemeral)
+	for _, resource := range ephemerals {
+		if resource.timer != nil {
+			resource.timer.Stop()
+		}
+	}
+	a.mu.Unlock()
+
+	for _, resource := range ephemerals {
+		if err := a.closeEphemeral(ctx, resource); err != nil {
+			log.Printf("Failed to close ephemeral resource %s (%s): %v", resource.id, resource.resourceType, err)
+		}
+	}
+}
+
+func (a *OpenTofuAdapter) closeEphemeral(ctx context.Context, resource *openEphemeral) error {
+	provider, release, err := a.acquireProvider(ctx, resource.providerConfig, resource.providerKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	client, err := newRawClient(provider)
+	if err != nil {
+		return err
+	}
+
+	diags, err := client.closeEphemeralResource(ctx, resource.resourceType, resource.private)
+	if err != nil {
+		return err
+	}
+	if err := checkDiagnostics(diags); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isEphemeralReference reports whether a config value is a reference to an ephemeral resource value
+func isEphemeralReference(value any) bool {
+	s, ok := value.(string)
+	return ok && ephemeralReference.MatchString(s)
+}
+
+// containsEphemeralReference reports whether a config value or anything nested in it refers to an
+// ephemeral resource
+func containsEphemeralReference(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, "${ephemeral.")
+	case []any:
+		for _, item := range v {
+			if containsEphemeralReference(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, item := range v {
+			if containsEphemeralReference(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestResolveEphemeralValues(t *testing.T) {
+	t.Parallel()
+
+	adapter := &OpenTofuAdapter{
+		converter: &CtyConverter{},
+		ephemerals: map[string]*openEphemeral{
+			"0192f3a4": {
+				id:           "0192f3a4",
+				resourceType: "random_password",
+				result: cty.ObjectVal(map[string]cty.Value{
+					"result":  cty.StringVal("s3cret"),
+					"keepers": cty.ObjectVal(map[string]cty.Value{"rotation": cty.StringVal("1")}),
+				}),
+			},
+		},
+	}
+
+	schema := fakeSchema{attrs: map[string]providerschema.Attribute{
+		"name":        fakeAttribute{ty: cty.String, usage: providerschema.AttributeRequired},
+		"password_wo": fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptional, writeOnly: true},
+	}}
+
+	t.Run("resolves write-only attributes without changing the config", func(t *testing.T) {
+		t.Parallel()
+
+		config := map[string]any{"name": "db", "password_wo": "${ephemeral.0192f3a4.result}"}
+
+		resolved, err := adapter.resolveEphemeralValues(schema, config, false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "db", "password_wo": "s3cret"}, resolved)
+		assert.Equal(t, "${ephemeral.0192f3a4.result}", config["password_wo"])
+	})
+
+	t.Run("resolves nested values", func(t *testing.T) {
+		t.Parallel()
+
+		resolved, err := adapter.resolveEphemeralValues(schema, map[string]any{"password_wo": "${ephemeral.0192f3a4.keepers.rotation}"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, "1", resolved["password_wo"])
+	})
+
+	t.Run("rejects attributes that are stored", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := adapter.resolveEphemeralValues(schema, map[string]any{"name": "${ephemeral.0192f3a4.result}"}, false)
+		assert.ErrorContains(t, err, `invalid use of an ephemeral value in "name"`)
+
+		resolved, err := adapter.resolveEphemeralValues(schema, map[string]any{"name": "${ephemeral.0192f3a4.result}"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", resolved["name"])
+	})
+
+	t.Run("rejects references inside other values", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := adapter.resolveEphemeralValues(schema, map[string]any{"password_wo": "pre-${ephemeral.0192f3a4.result}"}, false)
+		assert.ErrorContains(t, err, "references must be the whole value of a top-level attribute")
+	})
+
+	t.Run("rejects unknown resources and values", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := adapter.resolveEphemeralValues(schema, map[string]any{"password_wo": "${ephemeral.0192ffff.result}"}, false)
+		assert.ErrorContains(t, err, "ephemeral resource 0192ffff is not open")
+
+		_, err = adapter.resolveEphemeralValues(schema, map[string]any{"password_wo": "${ephemeral.0192f3a4.special}"}, false)
+		assert.ErrorContains(t, err, "ephemeral resource random_password has no value special")
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// extractedDir is the directory of a provider directory the provider archive is extracted to
+	extractedDir = "provider"
+
+	// maxExtractedSize caps the total decompressed size of a provider archive. The largest providers
+	// are well under 1 GiB.
+	maxExtractedSize = 2 << 30
+)
+
+// extractZip extracts a zip file to a destination directory, replacing it if it exists. The archive is
+// extracted to a temporary directory next to dest first and only renamed to dest once complete, so dest
+// never holds a partial extraction. Entries escaping the destination, symbolic links and other special
+// files are rejected, as is an archive decompressing to more than maxSize bytes. File modes from the
+// archive are not trusted: files are only ever created 0644, or 0755 if the archive marks them executable.
+func (a *OpenTofuAdapter) extractZip(src, dest string, maxSize int64) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), ".extract-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	remaining := maxSize
+	for _, f := range r.File {
+		if err := extractZipFile(f, tmpDir, &remaining); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+	return os.Rename(tmpDir, dest)
+}
+
+// extractZipFile extracts a single archive entry, remaining is the decompressed size still allowed
+func extractZipFile(f *zip.File, dest string, remaining *int64) error {
+	if !filepath.IsLocal(filepath.FromSlash(f.Name)) {
+		return fmt.Errorf("invalid path, archive entries must stay within the destination directory")
+	}
+	path := filepath.Join(dest, filepath.FromSlash(f.Name))
+
+	mode := f.Mode()
+	switch {
+	case mode.IsDir():
+		return os.MkdirAll(path, 0755)
+	case mode&os.ModeSymlink != 0:
+		return fmt.Errorf("symbolic links are not allowed in provider archives")
+	case !mode.IsRegular():
+		return fmt.Errorf("unsupported file type %s", mode.Type())
+	}
+
+	if f.UncompressedSize64 > uint64(*remaining) {
+		return fmt.Errorf("archive exceeds the maximum extracted size")
+	}
+
+	// Archives do not always have entries for parent directories
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// O_EXCL fails on duplicate entries instead of overwriting what was already extracted
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// The declared size can lie, so the decompressed data itself is limited too
+	written, err := io.Copy(file, io.LimitReader(rc, *remaining+1))
+	if err != nil {
+		return err
+	}
+	if written > *remaining {
+		return fmt.Errorf("archive exceeds the maximum extracted size")
+	}
+	*remaining -= written
+
+	return file.Close()
+}
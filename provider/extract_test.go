@@ -0,0 +1,143 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type zipEntry struct {
+	name    string
+	mode    os.FileMode
+	content string
+}
+
+// writeZip crafts a zip archive with the given entries
+func writeZip(t *testing.T, entries ...zipEntry) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "provider.zip")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		header.SetMode(entry.mode)
+		fw, err := w.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	return path
+}
+
+func TestExtractZip(t *testing.T) {
+	t.Parallel()
+
+	adapter := &OpenTofuAdapter{}
+
+	t.Run("extracts files with safe modes", func(t *testing.T) {
+		t.Parallel()
+
+		src := writeZip(t,
+			zipEntry{name: "terraform-provider-random_v3.6.0_x5", mode: 04777, content: "provider"},
+			zipEntry{name: "docs/", mode: os.ModeDir | 0777},
+			zipEntry{name: "docs/LICENSE", mode: 0666, content: "MPL-2.0"},
+		)
+		dest := filepath.Join(t.TempDir(), extractedDir)
+
+		require.NoError(t, adapter.extractZip(src, dest, 1024))
+
+		info, err := os.Stat(filepath.Join(dest, "terraform-provider-random_v3.6.0_x5"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode())
+
+		info, err = os.Stat(filepath.Join(dest, "docs", "LICENSE"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode())
+	})
+
+	rejected := map[string]struct {
+		entries []zipEntry
+		err     string
+	}{
+		"path traversal": {
+			entries: []zipEntry{{name: "../../terraform-provider-evil", mode: 0755, content: "evil"}},
+			err:     "archive entries must stay within the destination directory",
+		},
+		"absolute paths": {
+			entries: []zipEntry{{name: "/tmp/terraform-provider-evil", mode: 0755, content: "evil"}},
+			err:     "archive entries must stay within the destination directory",
+		},
+		"symbolic links": {
+			entries: []zipEntry{{name: "terraform-provider-link", mode: os.ModeSymlink | 0777, content: "/etc/passwd"}},
+			err:     "symbolic links are not allowed",
+		},
+		"oversized archives": {
+			entries: []zipEntry{
+				{name: "terraform-provider-random", mode: 0755, content: "0123456789"},
+				{name: "CHANGELOG.md", mode: 0644, content: "0123456789"},
+			},
+			err: "archive exceeds the maximum extracted size",
+		},
+		"duplicate entries": {
+			entries: []zipEntry{
+				{name: "terraform-provider-random", mode: 0755, content: "provider"},
+				{name: "terraform-provider-random", mode: 0755, content: "evil"},
+			},
+			err: "file exists",
+		},
+	}
+
+	for name, tc := range rejected {
+		t.Run("rejects "+name, func(t *testing.T) {
+			t.Parallel()
+
+			src := writeZip(t, tc.entries...)
+			parent := t.TempDir()
+			dest := filepath.Join(parent, extractedDir)
+
+			// A previous extraction stays in place when the archive is rejected
+			require.NoError(t, os.Mkdir(dest, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dest, "terraform-provider-random"), []byte("previous"), 0755))
+
+			err := adapter.extractZip(src, dest, 15)
+			assert.ErrorContains(t, err, tc.err)
+
+			content, err := os.ReadFile(filepath.Join(dest, "terraform-provider-random"))
+			require.NoError(t, err)
+			assert.Equal(t, "previous", string(content))
+
+			entries, err := os.ReadDir(parent)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1, "temporary extraction directory should be removed")
+		})
+	}
+
+	t.Run("replaces a previous extraction", func(t *testing.T) {
+		t.Parallel()
+
+		src := writeZip(t, zipEntry{name: "terraform-provider-random_v3.6.1_x5", mode: 0755, content: "provider"})
+		dest := filepath.Join(t.TempDir(), extractedDir)
+		require.NoError(t, os.Mkdir(dest, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dest, "terraform-provider-random_v3.6.0_x5"), []byte("previous"), 0755))
+
+		require.NoError(t, adapter.extractZip(src, dest, 1024))
+
+		entries, err := os.ReadDir(dest)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "terraform-provider-random_v3.6.1_x5", entries[0].Name())
+	})
+}
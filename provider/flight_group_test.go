@@ -0,0 +1,116 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent callers share one call", func(t *testing.T) {
+		t.Parallel()
+
+		var group flightGroup[string]
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		const callers = 50
+		var started, finished sync.WaitGroup
+		results := make([]string, callers)
+		started.Add(callers)
+		finished.Add(callers)
+
+		for i := range callers {
+			go func() {
+				defer finished.Done()
+				started.Done()
+
+				value, err := group.do(context.Background(), "hashicorp/random@3.6.0", func() (string, error) {
+					calls.Add(1)
+					<-release
+					return "binary", nil
+				})
+				assert.NoError(t, err)
+				results[i] = value
+			}()
+		}
+
+		started.Wait()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		finished.Wait()
+
+		require.EqualValues(t, 1, calls.Load())
+		for _, result := range results {
+			require.Equal(t, "binary", result)
+		}
+	})
+
+	t.Run("different keys run separately", func(t *testing.T) {
+		t.Parallel()
+
+		var group flightGroup[string]
+
+		first, err := group.do(context.Background(), "a", func() (string, error) { return "a", nil })
+		require.NoError(t, err)
+		second, err := group.do(context.Background(), "b", func() (string, error) { return "b", nil })
+		require.NoError(t, err)
+
+		require.Equal(t, "a", first)
+		require.Equal(t, "b", second)
+	})
+
+	t.Run("failed call is retried by the next caller", func(t *testing.T) {
+		t.Parallel()
+
+		var group flightGroup[string]
+
+		_, err := group.do(context.Background(), "key", func() (string, error) { return "", errors.New("download failed") })
+		require.ErrorContains(t, err, "download failed")
+
+		value, err := group.do(context.Background(), "key", func() (string, error) { return "binary", nil })
+		require.NoError(t, err)
+		require.Equal(t, "binary", value)
+	})
+
+	t.Run("waiting caller gives up when its context is done", func(t *testing.T) {
+		t.Parallel()
+
+		var group flightGroup[string]
+		running := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			value, err := group.do(context.Background(), "key", func() (string, error) {
+				close(running)
+				<-release
+				return "binary", nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "binary", value)
+		}()
+		<-running
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := group.do(ctx, "key", func() (string, error) { return "", errors.New("must not run") })
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		<-done
+	})
+}
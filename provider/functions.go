@@ -0,0 +1,242 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// ListFunctions describes the functions a provider defines, sorted by name
+func (a *OpenTofuAdapter) ListFunctions(ctx context.Context, providerConfig *types.ProviderConfig) ([]types.FunctionDescription, error) {
+	// Ensure provider is loaded
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
+		return nil, err
+	}
+
+	functions, err := a.getFunctions(ctx, providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []types.FunctionDescription{}
+	for _, name := range slices.Sorted(maps.Keys(functions)) {
+		description, err := describeFunction(name, functions[name])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, description)
+	}
+
+	return result, nil
+}
+
+// CallFunction runs a provider function with JSON arguments converted to its parameter types.
+// The name may be given with its provider::<name>:: prefix.
+func (a *OpenTofuAdapter) CallFunction(ctx context.Context, providerConfig *types.ProviderConfig, name string, args []any) (any, error) {
+	if strings.HasPrefix(name, "provider::") {
+		name = name[strings.LastIndex(name, "::")+2:]
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	functions, err := a.getFunctions(ctx, providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	decl, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %s has no function named %q", providerConfig.Name, name)
+	}
+
+	values, valueTypes, err := a.functionArguments(decl, args)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]providerschema.DynamicValueIn, len(values))
+	for i := range values {
+		arguments[i] = providerschema.NewDynamicValue(values[i], valueTypes[i])
+	}
+
+	resp, err := provider.CallFunction(ctx, &providerops.CallFunctionRequest{
+		FunctionName: name,
+		Arguments:    arguments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("call function failed: %w", err)
+	}
+	if funcErr := resp.Error(); funcErr != nil {
+		if i, ok := funcErr.ArgumentIndex(); ok {
+			return nil, fmt.Errorf("function %s failed on argument %d: %s", name, i+1, funcErr.Text())
+		}
+		return nil, fmt.Errorf("function %s failed: %s", name, funcErr.Text())
+	}
+
+	resultType, err := decl.ResultType().AsCtyType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result type of function %s: %w", name, err)
+	}
+
+	result, err := resp.Result().AsCtyValue(resultType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode function result: %w", err)
+	}
+
+	return a.converter.ctyValueToAny(result)
+}
+
+// getFunctions returns the function signatures of a loaded provider. They are read from the cached
+// provider schema because the provider client does not implement the lighter GetFunctions call.
+func (a *OpenTofuAdapter) getFunctions(ctx context.Context, providerConfig *types.ProviderConfig) (map[string]providerschema.FunctionSignature, error) {
+	rawSchema, err := a.getRawSchema(ctx, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider functions: %w", err)
+	}
+
+	return maps.Collect(rawSchema.ProviderSchema().FunctionSignatures()), nil
+}
+
+// describeFunction converts a function declaration to its description
+func describeFunction(name string, decl providerschema.FunctionSignature) (types.FunctionDescription, error) {
+	description, _ := decl.DocDescription()
+	result := types.FunctionDescription{
+		Name:        name,
+		Summary:     decl.DocSummary(),
+		Description: description,
+		Parameters:  []types.FunctionParameter{},
+		Deprecation: decl.DeprecationMessage(),
+	}
+
+	for param := range decl.Parameters() {
+		parameter, err := describeFunctionParameter(param)
+		if err != nil {
+			return types.FunctionDescription{}, fmt.Errorf("function %s: %w", name, err)
+		}
+		result.Parameters = append(result.Parameters, parameter)
+	}
+
+	if variadic := decl.VariadicParameter(); variadic != nil {
+		parameter, err := describeFunctionParameter(variadic)
+		if err != nil {
+			return types.FunctionDescription{}, fmt.Errorf("function %s: %w", name, err)
+		}
+		result.VariadicParameter = &parameter
+	}
+
+	resultType, err := decl.ResultType().AsCtyType()
+	if err != nil {
+		return types.FunctionDescription{}, fmt.Errorf("function %s: invalid result type: %w", name, err)
+	}
+	result.ReturnType = resultType.FriendlyName()
+
+	return result, nil
+}
+
+func describeFunctionParameter(param providerschema.FunctionParameter) (types.FunctionParameter, error) {
+	ty, err := param.Type().AsCtyType()
+	if err != nil {
+		return types.FunctionParameter{}, fmt.Errorf("invalid type of parameter %s: %w", param.Name(), err)
+	}
+
+	description, _ := param.DocDescription()
+	return types.FunctionParameter{
+		Name:        param.Name(),
+		Type:        ty.FriendlyName(),
+		Description: description,
+		AllowNull:   param.NullValueAllowed(),
+	}, nil
+}
+
+// functionArguments converts JSON arguments to the types of the function parameters, matching any
+// arguments after the fixed parameters to the variadic one. It returns each value with the type it is
+// sent as, which is the parameter type unless that is dynamic.
+func (a *OpenTofuAdapter) functionArguments(decl providerschema.FunctionSignature, args []any) ([]cty.Value, []cty.Type, error) {
+	params := slices.Collect(decl.Parameters())
+	variadic := decl.VariadicParameter()
+
+	if len(args) < len(params) || (variadic == nil && len(args) > len(params)) {
+		expected := fmt.Sprintf("%d", len(params))
+		if variadic != nil {
+			expected = fmt.Sprintf("at least %d", len(params))
+		}
+		return nil, nil, fmt.Errorf("function expects %s arguments, got %d", expected, len(args))
+	}
+
+	values := make([]cty.Value, len(args))
+	valueTypes := make([]cty.Type, len(args))
+	for i, arg := range args {
+		param := variadic
+		if i < len(params) {
+			param = params[i]
+		}
+
+		ty, err := param.Type().AsCtyType()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid type of parameter %s: %w", param.Name(), err)
+		}
+
+		if arg == nil && !param.NullValueAllowed() {
+			return nil, nil, fmt.Errorf("argument %d (%s) must not be null", i+1, param.Name())
+		}
+
+		value, err := a.functionArgument(arg, ty)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid argument %d (%s): %w", i+1, param.Name(), err)
+		}
+
+		values[i] = value
+		valueTypes[i] = ty
+		if ty == cty.DynamicPseudoType {
+			valueTypes[i] = value.Type()
+		}
+	}
+
+	return values, valueTypes, nil
+}
+
+// functionArgument converts a single argument. Values for dynamic parameters keep the type implied by
+// their JSON, so objects and mixed lists are passed as objects and tuples.
+func (a *OpenTofuAdapter) functionArgument(arg any, ty cty.Type) (cty.Value, error) {
+	if ty != cty.DynamicPseudoType {
+		return a.converter.convertValue(arg, ty)
+	}
+
+	if arg == nil {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+
+	raw, err := json.Marshal(arg)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	impliedType, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(raw, impliedType)
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// fakeFunctionSignature is a function signature with fixed parameters and an optional variadic one
+type fakeFunctionSignature struct {
+	providerschema.FunctionSignature
+
+	params   []providerschema.FunctionParameter
+	variadic providerschema.FunctionParameter
+	result   cty.Type
+}
+
+func (d fakeFunctionSignature) Parameters() iter.Seq[providerschema.FunctionParameter] {
+	return slices.Values(d.params)
+}
+func (d fakeFunctionSignature) VariadicParameter() providerschema.FunctionParameter {
+	return d.variadic
+}
+func (d fakeFunctionSignature) ResultType() providerschema.TypeConstraint {
+	return fakeType{ty: d.result}
+}
+func (d fakeFunctionSignature) DocSummary() string         { return "Parse an ARN" }
+func (d fakeFunctionSignature) DeprecationMessage() string { return "" }
+func (d fakeFunctionSignature) DocDescription() (string, providerschema.DocStringFormat) {
+	return "Parses an ARN into its parts.", 0
+}
+
+type fakeFunctionParam struct {
+	providerschema.FunctionParameter
+
+	name      string
+	ty        cty.Type
+	allowNull bool
+}
+
+func (p fakeFunctionParam) Name() string                        { return p.name }
+func (p fakeFunctionParam) Type() providerschema.TypeConstraint { return fakeType{ty: p.ty} }
+func (p fakeFunctionParam) NullValueAllowed() bool              { return p.allowNull }
+func (p fakeFunctionParam) DocDescription() (string, providerschema.DocStringFormat) {
+	return p.name + " to use", 0
+}
+
+func TestDescribeFunction(t *testing.T) {
+	t.Parallel()
+
+	decl := fakeFunctionSignature{
+		params:   []providerschema.FunctionParameter{fakeFunctionParam{name: "arn", ty: cty.String}},
+		variadic: fakeFunctionParam{name: "parts", ty: cty.List(cty.String), allowNull: true},
+		result:   cty.Object(map[string]cty.Type{"account_id": cty.String}),
+	}
+
+	description, err := describeFunction("arn_parse", decl)
+	require.NoError(t, err)
+	assert.Equal(t, types.FunctionDescription{
+		Name:        "arn_parse",
+		Summary:     "Parse an ARN",
+		Description: "Parses an ARN into its parts.",
+		Parameters: []types.FunctionParameter{
+			{Name: "arn", Type: "string", Description: "arn to use"},
+		},
+		VariadicParameter: &types.FunctionParameter{Name: "parts", Type: "list of string", Description: "parts to use", AllowNull: true},
+		ReturnType:        "object",
+	}, description)
+}
+
+func TestFunctionArguments(t *testing.T) {
+	t.Parallel()
+
+	adapter := &OpenTofuAdapter{converter: &CtyConverter{}}
+
+	fixed := fakeFunctionSignature{
+		params: []providerschema.FunctionParameter{
+			fakeFunctionParam{name: "arn", ty: cty.String},
+			fakeFunctionParam{name: "count", ty: cty.Number, allowNull: true},
+		},
+	}
+	variadic := fakeFunctionSignature{
+		params:   []providerschema.FunctionParameter{fakeFunctionParam{name: "separator", ty: cty.String}},
+		variadic: fakeFunctionParam{name: "parts", ty: cty.DynamicPseudoType},
+	}
+
+	t.Run("converts to parameter types", func(t *testing.T) {
+		t.Parallel()
+
+		values, valueTypes, err := adapter.functionArguments(fixed, []any{"arn:aws:s3:::bucket", float64(2)})
+		require.NoError(t, err)
+		assert.Equal(t, []cty.Value{cty.StringVal("arn:aws:s3:::bucket"), cty.NumberFloatVal(2)}, values)
+		assert.Equal(t, []cty.Type{cty.String, cty.Number}, valueTypes)
+	})
+
+	t.Run("allows null where the parameter does", func(t *testing.T) {
+		t.Parallel()
+
+		values, _, err := adapter.functionArguments(fixed, []any{"arn:aws:s3:::bucket", nil})
+		require.NoError(t, err)
+		assert.True(t, values[1].IsNull())
+
+		_, _, err = adapter.functionArguments(fixed, []any{nil, float64(1)})
+		assert.ErrorContains(t, err, "argument 1 (arn) must not be null")
+	})
+
+	t.Run("rejects wrong argument count", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := adapter.functionArguments(fixed, []any{"arn:aws:s3:::bucket"})
+		assert.ErrorContains(t, err, "function expects 2 arguments, got 1")
+
+		_, _, err = adapter.functionArguments(variadic, nil)
+		assert.ErrorContains(t, err, "function expects at least 1 arguments, got 0")
+	})
+
+	t.Run("passes dynamic values with their JSON type", func(t *testing.T) {
+		t.Parallel()
+
+		values, valueTypes, err := adapter.functionArguments(variadic, []any{":", "a", []any{"b", true}, map[string]any{"c": float64(1)}})
+		require.NoError(t, err)
+		require.Len(t, values, 4)
+		assert.Equal(t, cty.String, valueTypes[1])
+		assert.Equal(t, cty.Tuple([]cty.Type{cty.String, cty.Bool}), valueTypes[2])
+		assert.Equal(t, cty.Object(map[string]cty.Type{"c": cty.Number}), valueTypes[3])
+		assert.True(t, values[3].GetAttr("c").RawEquals(cty.NumberIntVal(1)))
+	})
+}
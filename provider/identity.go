@@ -0,0 +1,131 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// getIdentitySchemas returns the resource identity schemas of a provider by resource type. The provider
+// client does not implement resource identities, they go through the raw client.
+func (a *OpenTofuAdapter) getIdentitySchemas(ctx context.Context, client rawClient) (map[string]identitySchema, error) {
+	schemas, diags, err := client.identitySchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+// importIdentity converts the identity of a resource to import, upgrading it first if it was given for
+// an older version of the identity schema
+func (a *OpenTofuAdapter) importIdentity(ctx context.Context, client rawClient, identitySchemas map[string]identitySchema, resourceType string, target types.ImportTarget) (providerschema.DynamicValueIn, error) {
+	schema, ok := identitySchemas[resourceType]
+	if !ok {
+		return providerschema.NoDynamicValue, fmt.Errorf("resource type %s does not support import by identity, use an import ID instead", resourceType)
+	}
+
+	identityType := identityObjectType(schema)
+
+	var err error
+	identity := target.Identity
+	if target.IdentityVersion != nil && *target.IdentityVersion != schema.version {
+		identity, err = a.upgradeIdentity(ctx, client, resourceType, *target.IdentityVersion, identity, identityType)
+		if err != nil {
+			return providerschema.NoDynamicValue, err
+		}
+	}
+
+	if err := validateIdentity(schema, identity); err != nil {
+		return providerschema.NoDynamicValue, fmt.Errorf("invalid %s identity: %w", resourceType, err)
+	}
+
+	identityCty, err := a.converter.MapToCtyValue(identity, identityType)
+	if err != nil {
+		return providerschema.NoDynamicValue, fmt.Errorf("failed to convert identity: %w", err)
+	}
+
+	return providerschema.NewDynamicValue(identityCty, identityType), nil
+}
+
+// upgradeIdentity asks the provider to upgrade an identity from an older identity schema version
+func (a *OpenTofuAdapter) upgradeIdentity(ctx context.Context, client rawClient, resourceType string, version int64, identity map[string]any, identityType cty.Type) (map[string]any, error) {
+	// The provider receives the raw JSON identity, exactly as OpenTofu would read it from a state file
+	rawIdentity, err := json.Marshal(identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize identity: %w", err)
+	}
+
+	upgraded, diags, err := client.upgradeResourceIdentity(ctx, resourceType, version, rawIdentity)
+	if err != nil {
+		return nil, fmt.Errorf("identity upgrade failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, fmt.Errorf("identity upgrade failed: %w", err)
+	}
+
+	upgradedCty, err := upgraded.AsCtyValue(identityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode upgraded identity: %w", err)
+	}
+
+	return a.converter.CtyValueToMap(upgradedCty)
+}
+
+// decodeIdentity converts the identity of an imported resource to a map, nil if the provider returned none
+func (a *OpenTofuAdapter) decodeIdentity(schema identitySchema, identity rawValue) (map[string]any, error) {
+	identityCty, err := identity.AsCtyValue(identityObjectType(schema))
+	if err != nil {
+		return nil, err
+	}
+	if identityCty.IsNull() {
+		return nil, nil
+	}
+
+	return a.converter.CtyValueToMap(identityCty)
+}
+
+// identityObjectType returns the object type of the identities described by an identity schema
+func identityObjectType(schema identitySchema) cty.Type {
+	attrTypes := make(map[string]cty.Type, len(schema.attributes))
+	for name, attr := range schema.attributes {
+		attrTypes[name] = attr.ty
+	}
+	return cty.Object(attrTypes)
+}
+
+// validateIdentity checks that an identity sets every attribute required for import and nothing the
+// identity schema does not know
+func validateIdentity(schema identitySchema, identity map[string]any) error {
+	attrs := schema.attributes
+
+	for _, name := range slices.Sorted(maps.Keys(identity)) {
+		if _, ok := attrs[name]; !ok {
+			return fmt.Errorf("unknown attribute %q, expected one of: %s", name, strings.Join(slices.Sorted(maps.Keys(attrs)), ", "))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(attrs)) {
+		if attrs[name].requiredForImport && identity[name] == nil {
+			return fmt.Errorf("attribute %q is required", name)
+		}
+	}
+
+	return nil
+}
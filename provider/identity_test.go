@@ -0,0 +1,48 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestIdentity(t *testing.T) {
+	t.Parallel()
+
+	schema := identitySchema{version: 1, attributes: map[string]identityAttribute{
+		"id":         {ty: cty.String, requiredForImport: true},
+		"region":     {ty: cty.String},
+		"account_id": {ty: cty.String},
+	}}
+
+	t.Run("object type", func(t *testing.T) {
+		t.Parallel()
+
+		ty := identityObjectType(schema)
+		assert.True(t, ty.Equals(cty.Object(map[string]cty.Type{"id": cty.String, "region": cty.String, "account_id": cty.String})))
+	})
+
+	t.Run("accepts optional attributes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, validateIdentity(schema, map[string]any{"id": "sg-123"}))
+		assert.NoError(t, validateIdentity(schema, map[string]any{"id": "sg-123", "region": "eu-west-1"}))
+	})
+
+	t.Run("requires attributes required for import", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, validateIdentity(schema, map[string]any{"region": "eu-west-1"}), `attribute "id" is required`)
+	})
+
+	t.Run("rejects unknown attributes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, validateIdentity(schema, map[string]any{"id": "sg-123", "name": "web"}),
+			`unknown attribute "name", expected one of: account_id, id, region`)
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/opentofu/provider-client/tofuprovider"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// applyChange applies a planned change without letting the cancellation of ctx cut the apply short:
+// the provider would never report what it already changed. When ctx is cancelled, or the plugin is
+// stopped on shutdown, the provider is asked to stop instead, and the partial state it returns within
+// the stop timeout comes back in a *types.InterruptedError.
+func (a *OpenTofuAdapter) applyChange(ctx context.Context, provider tofuprovider.GRPCPluginProvider, req *providerops.ApplyManagedResourceChangeRequest, resourceType cty.Type, schemaVersion int64) (appliedChange, types.Diagnostics, error) {
+	// Apply through the raw client, the provider client drops the attribute paths of diagnostics
+	client, err := newRawClient(provider)
+	if err != nil {
+		return appliedChange{}, nil, err
+	}
+
+	applyCtx, cancelApply := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelApply()
+
+	type applyResult struct {
+		applied appliedChange
+		diags   types.Diagnostics
+		err     error
+	}
+	done := make(chan applyResult, 1)
+	go func() {
+		applied, diags, err := client.applyResourceChange(applyCtx, req)
+		done <- applyResult{applied: applied, diags: diags, err: err}
+	}()
+
+	var result applyResult
+	select {
+	case result = <-done:
+		// Stopping the plugin on shutdown interrupts its calls, those it did not finish are interrupted
+		stopped := isStopped(provider) && (result.err != nil || result.diags.HasErrors())
+		if !stopped {
+			return result.applied, result.diags, result.err
+		}
+	case <-ctx.Done():
+		log.Printf("Apply of %s was cancelled, asking the provider to stop", req.ResourceType)
+		stopProvider(ctx, provider, a.pluginPolicy.StopTimeout)
+
+		timer := time.NewTimer(a.pluginPolicy.StopTimeout)
+		defer timer.Stop()
+
+		select {
+		case result = <-done:
+		case <-timer.C:
+			return appliedChange{}, nil, &types.InterruptedError{Err: fmt.Errorf("the provider did not stop within %s: %w", a.pluginPolicy.StopTimeout, context.Cause(ctx))}
+		}
+	}
+
+	if result.err != nil {
+		return appliedChange{}, result.diags, &types.InterruptedError{Err: result.err}
+	}
+
+	// The cause of the cancellation is kept, callers tell a deadline apart from a cancelled call by it
+	cause := context.Cause(ctx)
+	interrupted := &types.InterruptedError{Err: cause}
+	diagErr := checkDiagnostics(result.diags)
+	switch {
+	case diagErr != nil && cause != nil:
+		interrupted.Err = fmt.Errorf("%w: %w", cause, diagErr)
+	case diagErr != nil:
+		interrupted.Err = diagErr
+	case cause == nil:
+		interrupted.Err = errors.New("the provider was stopped")
+	}
+
+	// What the provider returned when stopping is the best knowledge of the object, even if incomplete
+	if !result.applied.newState.isMissing() {
+		if stateCty, err := result.applied.newState.AsCtyValue(resourceType); err == nil {
+			if stateMap, err := a.converter.CtyValueToMap(stateCty); err == nil {
+				interrupted.State = &types.ResourceState{
+					State:         stateMap,
+					Private:       result.applied.private,
+					SchemaVersion: schemaVersion,
+				}
+			}
+		}
+	}
+
+	return appliedChange{}, result.diags, interrupted
+}
+
+// stopProvider asks a provider to gracefully abort its calls. What the plugin does afterwards is
+// unspecified, so a running plugin is retired and the next call starts a new one.
+func stopProvider(ctx context.Context, provider tofuprovider.GRPCPluginProvider, timeout time.Duration) {
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), max(timeout, time.Second))
+	defer cancel()
+
+	if plugin, ok := provider.(*runningPlugin); ok {
+		plugin.stop(stopCtx)
+		return
+	}
+	if err := provider.GracefulStop(stopCtx); err != nil {
+		log.Printf("Failed to stop provider: %v", err)
+	}
+}
+
+// isStopped reports whether a provider was asked to stop, which interrupts all of its calls
+func isStopped(provider tofuprovider.GRPCPluginProvider) bool {
+	plugin, ok := provider.(*runningPlugin)
+	return ok && plugin.stopped.Load()
+}
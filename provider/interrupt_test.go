@@ -0,0 +1,181 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin6"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/grpc"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+var applyTestType = cty.Object(map[string]cty.Type{"id": cty.String, "status": cty.String})
+
+// applyingPlugin blocks applies until it is asked to stop, unless it ignores stops, and then fails them
+// with the partial state
+type applyingPlugin struct {
+	fakePlugin
+
+	applying    chan struct{}
+	stopped     chan struct{}
+	ignoreStops bool
+}
+
+func newApplyingPlugin(ignoreStops bool) *applyingPlugin {
+	return &applyingPlugin{applying: make(chan struct{}), stopped: make(chan struct{}), ignoreStops: ignoreStops}
+}
+
+func (p *applyingPlugin) GracefulStop(_ context.Context) error {
+	close(p.stopped)
+	return nil
+}
+
+func (p *applyingPlugin) ClientProxy() any {
+	return applyingClient6{plugin: p}
+}
+
+// applyingClient6 is the protocol 6 client of an applyingPlugin
+type applyingClient6 struct {
+	tfplugin6.ProviderClient
+
+	plugin *applyingPlugin
+}
+
+func (c applyingClient6) ApplyResourceChange(ctx context.Context, _ *tfplugin6.ApplyResourceChange_Request, _ ...grpc.CallOption) (*tfplugin6.ApplyResourceChange_Response, error) {
+	close(c.plugin.applying)
+	if c.plugin.ignoreStops {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	<-c.plugin.stopped
+	state, err := msgpack.Marshal(cty.ObjectVal(map[string]cty.Value{
+		"id":     cty.StringVal("i-123"),
+		"status": cty.StringVal("pending"),
+	}), applyTestType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfplugin6.ApplyResourceChange_Response{
+		NewState: &tfplugin6.DynamicValue{Msgpack: state},
+		Private:  []byte("private"),
+		Diagnostics: []*tfplugin6.Diagnostic{{
+			Severity: tfplugin6.Diagnostic_ERROR,
+			Summary:  "Apply was stopped",
+			Attribute: &tfplugin6.AttributePath{Steps: []*tfplugin6.AttributePath_Step{
+				{Selector: &tfplugin6.AttributePath_Step_AttributeName{AttributeName: "status"}},
+			}},
+		}},
+	}, nil
+}
+
+func TestApplyChangeInterrupted(t *testing.T) {
+	t.Parallel()
+
+	type applyResult struct {
+		diags types.Diagnostics
+		err   error
+	}
+	apply := func(ctx context.Context, adapter *OpenTofuAdapter) chan applyResult {
+		provider, release, err := adapter.acquireProvider(ctx, &types.ProviderConfig{}, "random")
+		require.NoError(t, err)
+
+		result := make(chan applyResult, 1)
+		go func() {
+			defer release()
+			_, diags, err := adapter.applyChange(ctx, provider, &providerops.ApplyManagedResourceChangeRequest{ResourceType: "random_id"}, applyTestType, 2)
+			result <- applyResult{diags: diags, err: err}
+		}()
+		return result
+	}
+
+	t.Run("cancelled apply returns the partial state", func(t *testing.T) {
+		t.Parallel()
+
+		plugin := newApplyingPlugin(false)
+		adapter := newApplyingTestAdapter(t, time.Minute, plugin)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := apply(ctx, adapter)
+		<-plugin.applying
+		cancel()
+
+		applied := <-result
+		var interrupted *types.InterruptedError
+		require.ErrorAs(t, applied.err, &interrupted)
+		assert.ErrorContains(t, interrupted, "Apply was stopped")
+		assert.ErrorIs(t, interrupted, context.Canceled)
+
+		stopped := types.Diagnostics{{Severity: "error", Summary: "Apply was stopped", Attribute: "status"}}
+		var diagnosticsErr *types.DiagnosticsError
+		require.ErrorAs(t, interrupted, &diagnosticsErr)
+		assert.Equal(t, stopped, diagnosticsErr.Diagnostics)
+		assert.Equal(t, stopped, applied.diags)
+		require.NotNil(t, interrupted.State)
+		assert.Equal(t, map[string]any{"id": "i-123", "status": "pending"}, interrupted.State.State)
+		assert.Equal(t, []byte("private"), interrupted.State.Private)
+		assert.EqualValues(t, 2, interrupted.State.SchemaVersion)
+
+		// The stopped plugin is retired and closed once the apply is done
+		assert.Empty(t, runningPluginKeys(adapter))
+		assert.EqualValues(t, 1, plugin.closed.Load())
+	})
+
+	t.Run("provider not stopping in time", func(t *testing.T) {
+		t.Parallel()
+
+		plugin := newApplyingPlugin(true)
+		adapter := newApplyingTestAdapter(t, 10*time.Millisecond, plugin)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := apply(ctx, adapter)
+		<-plugin.applying
+		cancel()
+
+		var interrupted *types.InterruptedError
+		require.ErrorAs(t, (<-result).err, &interrupted)
+		assert.ErrorContains(t, interrupted, "the provider did not stop within 10ms")
+		assert.Nil(t, interrupted.State)
+	})
+
+	t.Run("shutdown stops the plugins applying changes", func(t *testing.T) {
+		t.Parallel()
+
+		plugin := newApplyingPlugin(false)
+		adapter := newApplyingTestAdapter(t, time.Minute, plugin)
+
+		result := apply(context.Background(), adapter)
+		<-plugin.applying
+		adapter.Cleanup(context.Background())
+
+		var interrupted *types.InterruptedError
+		require.ErrorAs(t, (<-result).err, &interrupted)
+		require.NotNil(t, interrupted.State)
+		assert.Equal(t, "pending", interrupted.State.State["status"])
+		assert.EqualValues(t, 1, plugin.closed.Load())
+	})
+}
+
+func newApplyingTestAdapter(t *testing.T, stopTimeout time.Duration, plugin *applyingPlugin) *OpenTofuAdapter {
+	adapter := NewOpenTofuAdapter(t.TempDir(), nil, types.PluginPolicy{StopTimeout: stopTimeout}, nil).(*OpenTofuAdapter)
+	t.Cleanup(func() { adapter.Cleanup(context.Background()) })
+
+	adapter.providers["random"] = &runningPlugin{
+		GRPCPluginProvider: plugin,
+		key:                "random",
+		onCrash:            adapter.forgetCrashed,
+	}
+
+	return adapter
+}
@@ -0,0 +1,288 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// configLinter checks a configuration against a schema locally, before it is converted and sent to the provider
+type configLinter struct {
+	diags types.Diagnostics
+}
+
+// lintConfig reports unknown attributes (with suggestions), type mismatches, missing required attributes,
+// computed-only attributes and block nesting mistakes. With partial set the config is merged over an existing
+// state, so top-level required attributes may be left out.
+func lintConfig(schema providerschema.Schema, config map[string]any, partial bool) types.Diagnostics {
+	l := &configLinter{diags: types.Diagnostics{}}
+	l.lintObject(nil, config, maps.Collect(schema.Attributes()), maps.Collect(schema.NestedBlockTypes()), !partial)
+	return l.diags
+}
+
+func (l *configLinter) errorf(path cty.Path, summary, detail string, args ...any) {
+	l.diags = append(l.diags, types.Diagnostic{
+		Severity:  "error",
+		Summary:   summary,
+		Detail:    fmt.Sprintf(detail, args...),
+		Attribute: formatPath(path),
+	})
+}
+
+// lintObject checks the body of a schema, a nested block or a nested attribute object
+func (l *configLinter) lintObject(path cty.Path, config map[string]any, attrs map[string]providerschema.Attribute, blocks map[string]providerschema.NestedBlockType, checkRequired bool) {
+	names := slices.Concat(slices.Collect(maps.Keys(attrs)), slices.Collect(maps.Keys(blocks)))
+
+	for _, name := range slices.Sorted(maps.Keys(config)) {
+		if attr, ok := attrs[name]; ok {
+			l.lintAttribute(path.GetAttr(name), name, attr, config[name])
+		} else if block, ok := blocks[name]; ok {
+			l.lintBlock(path.GetAttr(name), name, block, config[name])
+		} else {
+			l.unsupported(path.GetAttr(name), name, names)
+		}
+	}
+
+	if !checkRequired {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(attrs)) {
+		if attrs[name].Usage() == providerschema.AttributeRequired && config[name] == nil {
+			l.errorf(path.GetAttr(name), "Missing required argument", "The argument %q is required, but no definition was found.", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(blocks)) {
+		if minItems, _ := blocks[name].ItemLimits(); minItems > 0 && config[name] == nil {
+			l.errorf(path.GetAttr(name), "Insufficient "+name+" blocks", "At least %d %q blocks are required.", minItems, name)
+		}
+	}
+}
+
+func (l *configLinter) unsupported(path cty.Path, name string, names []string) {
+	detail := fmt.Sprintf("An argument named %q is not expected here.", name)
+	if suggestion := didYouMean(name, names); suggestion != "" {
+		detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+	}
+	l.errorf(path, "Unsupported argument", "%s", detail)
+}
+
+func (l *configLinter) lintAttribute(path cty.Path, name string, attr providerschema.Attribute, value any) {
+	if value == nil || value == "__cty_unknown__" {
+		return
+	}
+
+	if isEphemeralReference(value) {
+		if len(path) > 1 || !attr.IsWriteOnly() {
+			l.errorf(path, "Invalid use of ephemeral value",
+				"Ephemeral values are never stored, so they can only be used in top-level write-only attributes and %q is not one.", formatPath(path))
+		}
+		return
+	}
+
+	if attr.Usage() == providerschema.AttributeComputed {
+		l.errorf(path, "Value for unconfigurable attribute",
+			"Can't configure a value for %q: its value will be decided automatically based on the result of applying this configuration.", name)
+		return
+	}
+
+	if nestedType := attr.NestedType(); nestedType != nil {
+		attrs := maps.Collect(nestedType.Attributes())
+		l.lintNesting(path, name, nestedType.Nesting(), value, func(path cty.Path, body map[string]any) {
+			l.lintObject(path, body, attrs, nil, true)
+		})
+		return
+	}
+
+	ty, err := attr.Type().AsCtyType()
+	if err != nil {
+		return
+	}
+	l.lintValue(path, value, ty)
+}
+
+func (l *configLinter) lintBlock(path cty.Path, name string, block providerschema.NestedBlockType, value any) {
+	if value == nil || value == "__cty_unknown__" {
+		return
+	}
+
+	attrs := maps.Collect(block.Attributes())
+	blocks := maps.Collect(block.NestedBlockTypes())
+	l.lintNesting(path, name, block.Nesting(), value, func(path cty.Path, body map[string]any) {
+		l.lintObject(path, body, attrs, blocks, true)
+	})
+
+	if _, maxItems := block.ItemLimits(); maxItems > 0 {
+		if items, ok := value.([]any); ok && int64(len(items)) > maxItems {
+			l.errorf(path, "Too many "+name+" blocks", "No more than %d %q blocks are allowed.", maxItems, name)
+		}
+	}
+}
+
+// lintNesting checks that a nested block or nested attribute has the shape its nesting mode requires,
+// and lints each object in it with body
+func (l *configLinter) lintNesting(path cty.Path, name string, nesting providerschema.NestingMode, value any, body func(cty.Path, map[string]any)) {
+	switch nesting {
+	case providerschema.NestingSingle, providerschema.NestingGroup:
+		object, ok := value.(map[string]any)
+		if !ok {
+			l.errorf(path, "Invalid nesting", "%q is a single object: set it to an object, not %s.", name, describeValue(value))
+			return
+		}
+		body(path, object)
+	case providerschema.NestingList, providerschema.NestingSet:
+		items, ok := value.([]any)
+		if !ok {
+			l.errorf(path, "Invalid nesting", "%q can be repeated: set it to a list of objects, even for a single one, not %s.", name, describeValue(value))
+			return
+		}
+		for i, item := range items {
+			object, ok := item.(map[string]any)
+			if !ok {
+				l.errorf(path.Index(cty.NumberIntVal(int64(i))), "Invalid nesting", "Each %q must be an object, not %s.", name, describeValue(item))
+				continue
+			}
+			body(path.Index(cty.NumberIntVal(int64(i))), object)
+		}
+	case providerschema.NestingMap:
+		items, ok := value.(map[string]any)
+		if !ok {
+			l.errorf(path, "Invalid nesting", "%q is a map of objects by key, not %s.", name, describeValue(value))
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(items)) {
+			object, ok := items[key].(map[string]any)
+			if !ok {
+				l.errorf(path.Index(cty.StringVal(key)), "Invalid nesting", "Each %q must be an object, not %s.", name, describeValue(items[key]))
+				continue
+			}
+			body(path.Index(cty.StringVal(key)), object)
+		}
+	}
+}
+
+// lintValue checks that a value can be converted to the given type
+func (l *configLinter) lintValue(path cty.Path, value any, ty cty.Type) {
+	if value == nil || value == "__cty_unknown__" || ty == cty.DynamicPseudoType {
+		return
+	}
+
+	mismatch := func() {
+		l.errorf(path, "Incorrect attribute value type", "Inappropriate value for %q: %s required, got %s.",
+			formatPath(path), ty.FriendlyName(), describeValue(value))
+	}
+
+	switch {
+	case ty == cty.String:
+		switch value.(type) {
+		case map[string]any, []any:
+			mismatch()
+		}
+	case ty == cty.Number:
+		switch value.(type) {
+		case int, int64, float64:
+		default:
+			mismatch()
+		}
+	case ty == cty.Bool:
+		if _, ok := value.(bool); !ok {
+			mismatch()
+		}
+	case ty.IsListType(), ty.IsSetType():
+		items, ok := value.([]any)
+		if !ok {
+			mismatch()
+			return
+		}
+		for i, item := range items {
+			l.lintValue(path.Index(cty.NumberIntVal(int64(i))), item, ty.ElementType())
+		}
+	case ty.IsMapType():
+		items, ok := value.(map[string]any)
+		if !ok {
+			mismatch()
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(items)) {
+			l.lintValue(path.Index(cty.StringVal(key)), items[key], ty.ElementType())
+		}
+	case ty.IsObjectType():
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch()
+			return
+		}
+		attrTypes := ty.AttributeTypes()
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			if attrType, ok := attrTypes[name]; ok {
+				l.lintValue(path.GetAttr(name), object[name], attrType)
+			} else {
+				l.unsupported(path.GetAttr(name), name, slices.Collect(maps.Keys(attrTypes)))
+			}
+		}
+	}
+}
+
+// describeValue names the JSON kind of a config value for error messages
+func describeValue(value any) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case int, int64, float64:
+		return "a number"
+	case bool:
+		return "a bool"
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// didYouMean returns the candidate closest to name, or an empty string if none is close enough
+func didYouMean(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range slices.Sorted(slices.Values(candidates)) {
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
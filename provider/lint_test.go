@@ -0,0 +1,189 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"iter"
+	"maps"
+	"testing"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// fakeSchema is a resource schema with only attributes and nested blocks
+type fakeSchema struct {
+	providerschema.Schema
+
+	attrs  map[string]providerschema.Attribute
+	blocks map[string]providerschema.NestedBlockType
+}
+
+func (s fakeSchema) Attributes() iter.Seq2[string, providerschema.Attribute] {
+	return maps.All(s.attrs)
+}
+
+func (s fakeSchema) NestedBlockTypes() iter.Seq2[string, providerschema.NestedBlockType] {
+	return maps.All(s.blocks)
+}
+
+// fakeAttribute is an attribute with only a type, usage, nested type and sensitive and write-only flags
+type fakeAttribute struct {
+	providerschema.Attribute
+
+	ty         cty.Type
+	usage      providerschema.AttributeUsage
+	nestedType providerschema.ObjectType
+	sensitive  bool
+	writeOnly  bool
+}
+
+func (a fakeAttribute) Type() providerschema.TypeConstraint   { return fakeType{ty: a.ty} }
+func (a fakeAttribute) Usage() providerschema.AttributeUsage  { return a.usage }
+func (a fakeAttribute) NestedType() providerschema.ObjectType { return a.nestedType }
+func (a fakeAttribute) IsSensitive() bool                     { return a.sensitive }
+func (a fakeAttribute) IsDeprecated() bool                    { return false }
+func (a fakeAttribute) IsWriteOnly() bool                     { return a.writeOnly }
+func (a fakeAttribute) DocDescription() (string, providerschema.DocStringFormat) {
+	return "", 0
+}
+
+// fakeType is a type constraint, embedding the interface for its unexported sealing method
+type fakeType struct {
+	providerschema.TypeConstraint
+
+	ty cty.Type
+}
+
+func (t fakeType) AsCtyType() (cty.Type, error) { return t.ty, nil }
+
+func TestLintConfig(t *testing.T) {
+	t.Parallel()
+
+	schema := fakeSchema{
+		attrs: map[string]providerschema.Attribute{
+			"id":            fakeAttribute{ty: cty.String, usage: providerschema.AttributeComputed},
+			"ami":           fakeAttribute{ty: cty.String, usage: providerschema.AttributeRequired},
+			"instance_type": fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptional},
+			"monitoring":    fakeAttribute{ty: cty.Bool, usage: providerschema.AttributeOptional},
+			"cpu_count":     fakeAttribute{ty: cty.Number, usage: providerschema.AttributeOptional},
+			"tags":          fakeAttribute{ty: cty.Map(cty.String), usage: providerschema.AttributeOptional},
+			"password_wo":   fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptional, writeOnly: true},
+		},
+		blocks: map[string]providerschema.NestedBlockType{
+			"ebs_block_device": fakeBlock{
+				nesting: providerschema.NestingList,
+				attrs: map[string]providerschema.Attribute{
+					"device_name": fakeAttribute{ty: cty.String, usage: providerschema.AttributeRequired},
+					"volume_size": fakeAttribute{ty: cty.Number, usage: providerschema.AttributeOptional},
+				},
+			},
+			"timeouts": fakeBlock{nesting: providerschema.NestingSingle, maxItems: 1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		config  map[string]any
+		partial bool
+		want    types.Diagnostics
+	}{
+		{
+			name:   "valid config",
+			config: map[string]any{"ami": "ami-123", "tags": map[string]any{"Name": "web"}, "ebs_block_device": []any{map[string]any{"device_name": "/dev/sda"}}},
+			want:   types.Diagnostics{},
+		},
+		{
+			name:   "unknown attribute with suggestion",
+			config: map[string]any{"ami": "ami-123", "instance_typ": "t3.micro"},
+			want: types.Diagnostics{{
+				Severity:  "error",
+				Summary:   "Unsupported argument",
+				Detail:    `An argument named "instance_typ" is not expected here. Did you mean "instance_type"?`,
+				Attribute: "instance_typ",
+			}},
+		},
+		{
+			name:   "unknown attribute without suggestion",
+			config: map[string]any{"ami": "ami-123", "flavour": "large"},
+			want: types.Diagnostics{{
+				Severity:  "error",
+				Summary:   "Unsupported argument",
+				Detail:    `An argument named "flavour" is not expected here.`,
+				Attribute: "flavour",
+			}},
+		},
+		{
+			name:   "type mismatches",
+			config: map[string]any{"ami": "ami-123", "monitoring": "yes", "tags": map[string]any{"Name": []any{"web"}}},
+			want: types.Diagnostics{
+				{Severity: "error", Summary: "Incorrect attribute value type", Detail: `Inappropriate value for "monitoring": bool required, got a string.`, Attribute: "monitoring"},
+				{Severity: "error", Summary: "Incorrect attribute value type", Detail: `Inappropriate value for "tags[\"Name\"]": string required, got a list.`, Attribute: `tags["Name"]`},
+			},
+		},
+		{
+			name:   "missing required attributes",
+			config: map[string]any{"ebs_block_device": []any{map[string]any{"volume_size": 10}}},
+			want: types.Diagnostics{
+				{Severity: "error", Summary: "Missing required argument", Detail: `The argument "device_name" is required, but no definition was found.`, Attribute: "ebs_block_device[0].device_name"},
+				{Severity: "error", Summary: "Missing required argument", Detail: `The argument "ami" is required, but no definition was found.`, Attribute: "ami"},
+			},
+		},
+		{
+			name:    "partial config skips top-level required attributes",
+			config:  map[string]any{"instance_type": "t3.small"},
+			partial: true,
+			want:    types.Diagnostics{},
+		},
+		{
+			name:   "computed attribute",
+			config: map[string]any{"ami": "ami-123", "id": "i-123"},
+			want: types.Diagnostics{{
+				Severity:  "error",
+				Summary:   "Value for unconfigurable attribute",
+				Detail:    `Can't configure a value for "id": its value will be decided automatically based on the result of applying this configuration.`,
+				Attribute: "id",
+			}},
+		},
+		{
+			name:   "block nesting mistakes",
+			config: map[string]any{"ami": "ami-123", "ebs_block_device": map[string]any{"device_name": "/dev/sda"}, "timeouts": []any{map[string]any{}}},
+			want: types.Diagnostics{
+				{Severity: "error", Summary: "Invalid nesting", Detail: `"ebs_block_device" can be repeated: set it to a list of objects, even for a single one, not an object.`, Attribute: "ebs_block_device"},
+				{Severity: "error", Summary: "Invalid nesting", Detail: `"timeouts" is a single object: set it to an object, not a list.`, Attribute: "timeouts"},
+			},
+		},
+		{
+			name:   "ephemeral values only in write-only attributes",
+			config: map[string]any{"ami": "${ephemeral.0192f3a4.ami}", "password_wo": "${ephemeral.0192f3a4.result}"},
+			want: types.Diagnostics{{
+				Severity:  "error",
+				Summary:   "Invalid use of ephemeral value",
+				Detail:    `Ephemeral values are never stored, so they can only be used in top-level write-only attributes and "ami" is not one.`,
+				Attribute: "ami",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, lintConfig(schema, tt.config, tt.partial))
+		})
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	t.Parallel()
+
+	candidates := []string{"instance_type", "ami", "tags"}
+
+	assert.Equal(t, "instance_type", didYouMean("instance_typ", candidates))
+	assert.Equal(t, "tags", didYouMean("Tag", candidates))
+	assert.Equal(t, "", didYouMean("subnet", candidates))
+}
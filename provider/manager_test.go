@@ -6,10 +6,13 @@ package provider
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/spacelift-io/spacelift-intent/registry"
 	"github.com/spacelift-io/spacelift-intent/types"
@@ -21,7 +24,7 @@ func TestPlanResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -36,13 +39,23 @@ func TestPlanResource(t *testing.T) {
 		Version: "3.6.0",
 	}
 
-	plannedState, err := adapter.PlanResource(ctx, providerConfig, "random_password", nil, config)
+	planned, _, err := adapter.PlanResource(ctx, providerConfig, "random_password", nil, config)
 	require.NoError(t, err)
 
 	// Basic validations
-	assert.NotNil(t, plannedState)
+	require.NotNil(t, planned)
+	plannedState := planned.PlannedState.State
 	assert.EqualValues(t, 16, plannedState["length"])
 	assert.Equal(t, true, plannedState["special"])
+	assert.Equal(t, "create", planned.Action)
+
+	changes := map[string]types.AttributeChange{}
+	for _, change := range planned.Changes {
+		changes[change.Attribute] = change
+	}
+	assert.Equal(t, "add", changes["length"].Action)
+	assert.True(t, changes["result"].Computed)
+	assert.True(t, changes["result"].Sensitive)
 
 	t.Logf("Planned random_password resource: %+v", plannedState)
 }
@@ -53,7 +66,7 @@ func TestCreateResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -68,11 +81,12 @@ func TestCreateResource(t *testing.T) {
 		Version: "3.6.0",
 	}
 
-	createdState, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
+	created, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
 	require.NoError(t, err)
 
 	// Basic validations
-	assert.NotNil(t, createdState)
+	require.NotNil(t, created)
+	createdState := created.State
 	assert.EqualValues(t, 8, createdState["length"])
 	assert.Equal(t, false, createdState["special"])
 	assert.NotEmpty(t, createdState["result"]) // Should have generated a string
@@ -87,7 +101,7 @@ func TestUpdateResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -102,8 +116,9 @@ func TestUpdateResource(t *testing.T) {
 		"special": false,
 	}
 
-	currentState, err := adapter.CreateResource(ctx, providerConfig, "random_string", initialConfig)
+	current, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", initialConfig)
 	require.NoError(t, err)
+	currentState := current.State
 	require.NotEmpty(t, currentState["result"])
 
 	// Now update it with new config
@@ -112,11 +127,12 @@ func TestUpdateResource(t *testing.T) {
 		"special": true,
 	}
 
-	updatedState, err := adapter.UpdateResource(ctx, providerConfig, "random_string", currentState, newConfig)
+	updated, _, err := adapter.UpdateResource(ctx, providerConfig, "random_string", current, newConfig)
 	require.NoError(t, err)
 
 	// Basic validations
-	assert.NotNil(t, updatedState)
+	require.NotNil(t, updated)
+	updatedState := updated.State
 	assert.EqualValues(t, 10, updatedState["length"])
 	assert.Equal(t, true, updatedState["special"])
 	assert.NotEmpty(t, updatedState["result"]) // Should have generated a new string
@@ -136,7 +152,7 @@ func TestDeleteResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -151,25 +167,88 @@ func TestDeleteResource(t *testing.T) {
 		"special": false,
 	}
 
-	state, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
+	created, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
 	require.NoError(t, err)
+	state := created.State
 	require.NotEmpty(t, state["result"])
 
 	// Now delete the resource
 	// TODO(michal): why do we need state to delete the resource?
-	err = adapter.DeleteResource(ctx, providerConfig, "random_string", state)
+	_, err = adapter.DeleteResource(ctx, providerConfig, "random_string", created)
 	require.NoError(t, err)
 
 	t.Logf("Successfully deleted random_string resource with id: %v", state["id"])
 }
 
+func TestValidateResourceUpdate(t *testing.T) {
+	tmpDir := "./test-providers"
+	err := os.MkdirAll(tmpDir, 0755)
+	require.NoError(t, err)
+
+	registryClient := registry.NewOpenTofuClient()
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
+	ctx := context.Background()
+	defer adapter.Cleanup(ctx)
+
+	providerConfig := &types.ProviderConfig{
+		Name:    "hashicorp/random",
+		Version: "3.6.0",
+	}
+
+	created, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", map[string]any{"length": 8})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.State["result"], "result is computed only")
+
+	// The computed-only attributes of the state are not sent with the configuration
+	diags, err := adapter.ValidateResource(ctx, providerConfig, "random_string", created, map[string]any{"length": 12})
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), "unexpected diagnostics: %v", diags)
+}
+
+func TestWithoutComputed(t *testing.T) {
+	t.Parallel()
+
+	ruleAttrs := map[string]providerschema.Attribute{
+		"port": fakeAttribute{ty: cty.Number, usage: providerschema.AttributeRequired},
+		"id":   fakeAttribute{ty: cty.String, usage: providerschema.AttributeComputed},
+	}
+	attrs := map[string]providerschema.Attribute{
+		"arn":    fakeAttribute{ty: cty.String, usage: providerschema.AttributeComputed},
+		"bucket": fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptionalComputed},
+		"tags":   fakeAttribute{ty: cty.Map(cty.String), usage: providerschema.AttributeOptional},
+		"rules":  fakeAttribute{usage: providerschema.AttributeOptional, nestedType: fakeObjectType{nesting: providerschema.NestingList, attrs: ruleAttrs}},
+	}
+	blocks := map[string]providerschema.NestedBlockType{
+		"logging": fakeBlock{nesting: providerschema.NestingSingle, attrs: map[string]providerschema.Attribute{
+			"target":  fakeAttribute{ty: cty.String, usage: providerschema.AttributeRequired},
+			"created": fakeAttribute{ty: cty.String, usage: providerschema.AttributeComputed},
+		}},
+	}
+
+	state := map[string]any{
+		"arn":     "arn:aws:s3:::logs",
+		"bucket":  "logs",
+		"tags":    map[string]any{"team": "infra"},
+		"rules":   []any{map[string]any{"port": 443, "id": "r-1"}},
+		"logging": map[string]any{"target": "audit", "created": "2025-01-01"},
+	}
+
+	assert.Equal(t, map[string]any{
+		"bucket":  "logs",
+		"tags":    map[string]any{"team": "infra"},
+		"rules":   []any{map[string]any{"port": 443}},
+		"logging": map[string]any{"target": "audit"},
+	}, withoutComputed(attrs, blocks, state))
+	assert.Contains(t, state, "arn", "the state is left unchanged")
+}
+
 func TestRefreshResource(t *testing.T) {
 	tmpDir := "./test-providers"
 	err := os.MkdirAll(tmpDir, 0755)
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -184,16 +263,18 @@ func TestRefreshResource(t *testing.T) {
 		"special": true,
 	}
 
-	state, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
+	created, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
 	require.NoError(t, err)
+	state := created.State
 	require.NotEmpty(t, state["result"])
 
 	// Now refresh the resource
-	refreshedState, err := adapter.RefreshResource(ctx, providerConfig, "random_string", state)
+	refreshed, _, err := adapter.RefreshResource(ctx, providerConfig, "random_string", created)
 	require.NoError(t, err)
 
 	// Basic validations
-	assert.NotNil(t, refreshedState)
+	require.NotNil(t, refreshed)
+	refreshedState := refreshed.State
 	assert.Equal(t, state["id"], refreshedState["id"])         // ID should remain the same
 	assert.Equal(t, state["result"], refreshedState["result"]) // Result should remain the same
 	assert.EqualValues(t, 12, refreshedState["length"])
@@ -208,7 +289,7 @@ func TestListResources(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -236,7 +317,7 @@ func TestDescribeResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -346,7 +427,7 @@ func TestImportResource(t *testing.T) {
 	require.NoError(t, err)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -361,18 +442,21 @@ func TestImportResource(t *testing.T) {
 		Version: "3.6.0",
 	}
 
-	state, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
+	created, _, err := adapter.CreateResource(ctx, providerConfig, "random_string", config)
 	require.NoError(t, err)
+	state := created.State
 	require.NotEmpty(t, state["id"])
 
 	resourceID := state["id"].(string)
 
 	// Now import the resource using its ID
-	importedState, err := adapter.ImportResource(ctx, providerConfig, "random_string", resourceID)
+	imported, _, err := adapter.ImportResource(ctx, providerConfig, "random_string", types.ImportTarget{ID: resourceID})
 	require.NoError(t, err)
 
 	// Basic validations
-	assert.NotNil(t, importedState)
+	require.Len(t, imported, 1)
+	assert.Equal(t, "random_string", imported[0].ResourceType)
+	importedState := imported[0].State.State
 	assert.Equal(t, resourceID, importedState["id"])
 	assert.Equal(t, state["result"], importedState["result"]) // Should be the same random string
 	assert.EqualValues(t, 8, importedState["length"])
@@ -386,7 +470,7 @@ func TestMultipleVersionsInCache(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	registryClient := registry.NewOpenTofuClient()
-	adapter := NewOpenTofuAdapter(tmpDir, registryClient)
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil)
 	ctx := context.Background()
 	defer adapter.Cleanup(ctx)
 
@@ -396,7 +480,7 @@ func TestMultipleVersionsInCache(t *testing.T) {
 		Version: "3.6.0",
 	}
 
-	err = adapter.LoadProvider(ctx, providerConfigV360)
+	_, err = adapter.LoadProvider(ctx, providerConfigV360)
 	require.NoError(t, err)
 
 	// Load version 3.5.1 of random provider
@@ -405,7 +489,7 @@ func TestMultipleVersionsInCache(t *testing.T) {
 		Version: "3.5.1",
 	}
 
-	err = adapter.LoadProvider(ctx, providerConfigV351)
+	_, err = adapter.LoadProvider(ctx, providerConfigV351)
 	require.NoError(t, err)
 
 	// Verify that two different provider binaries were downloaded to separate directories
@@ -420,7 +504,7 @@ func TestMultipleVersionsInCache(t *testing.T) {
 	require.NoError(t, err, "v3.5.1 provider directory should exist: %s", expectedDirV351)
 
 	// Verify loading the same provider version again doesn't fail (idempotent)
-	err = adapter.LoadProvider(ctx, providerConfigV360)
+	_, err = adapter.LoadProvider(ctx, providerConfigV360)
 	require.NoError(t, err, "Loading already-cached provider should be idempotent")
 
 	// Create resources with v3.6.0
@@ -429,8 +513,9 @@ func TestMultipleVersionsInCache(t *testing.T) {
 		"special": false,
 	}
 
-	stateV360, err := adapter.CreateResource(ctx, providerConfigV360, "random_string", configV360)
+	createdV360, _, err := adapter.CreateResource(ctx, providerConfigV360, "random_string", configV360)
 	require.NoError(t, err)
+	stateV360 := createdV360.State
 
 	require.NotEmpty(t, stateV360["result"])
 	assert.EqualValues(t, 8, stateV360["length"])
@@ -442,8 +527,9 @@ func TestMultipleVersionsInCache(t *testing.T) {
 		"special": true,
 	}
 
-	stateV351, err := adapter.CreateResource(ctx, providerConfigV351, "random_string", configV351)
+	createdV351, _, err := adapter.CreateResource(ctx, providerConfigV351, "random_string", configV351)
 	require.NoError(t, err)
+	stateV351 := createdV351.State
 
 	require.NotEmpty(t, stateV351["result"])
 	assert.EqualValues(t, 10, stateV351["length"])
@@ -451,16 +537,66 @@ func TestMultipleVersionsInCache(t *testing.T) {
 
 	// Verify we can use both versions independently after creation
 	// Refresh with v3.6.0
-	refreshedV360, err := adapter.RefreshResource(ctx, providerConfigV360, "random_string", stateV360)
+	refreshedV360, _, err := adapter.RefreshResource(ctx, providerConfigV360, "random_string", createdV360)
 	require.NoError(t, err)
 
-	assert.Equal(t, stateV360["id"], refreshedV360["id"])
-	assert.Equal(t, stateV360["result"], refreshedV360["result"])
+	assert.Equal(t, stateV360["id"], refreshedV360.State["id"])
+	assert.Equal(t, stateV360["result"], refreshedV360.State["result"])
 
 	// Refresh with v3.5.1
-	refreshedV351, err := adapter.RefreshResource(ctx, providerConfigV351, "random_string", stateV351)
+	refreshedV351, _, err := adapter.RefreshResource(ctx, providerConfigV351, "random_string", createdV351)
+	require.NoError(t, err)
+
+	assert.Equal(t, stateV351["id"], refreshedV351.State["id"])
+	assert.Equal(t, stateV351["result"], refreshedV351.State["result"])
+}
+
+func TestConcurrentProviderCalls(t *testing.T) {
+	tmpDir := "./test-providers"
+	err := os.MkdirAll(tmpDir, 0755)
 	require.NoError(t, err)
 
-	assert.Equal(t, stateV351["id"], refreshedV351["id"])
-	assert.Equal(t, stateV351["result"], refreshedV351["result"])
+	registryClient := registry.NewOpenTofuClient()
+	adapter := NewOpenTofuAdapter(tmpDir, registryClient, types.PluginPolicy{}, nil).(*OpenTofuAdapter)
+	ctx := context.Background()
+	defer adapter.Cleanup(ctx)
+
+	providerConfig := types.ProviderConfig{
+		Name:    "hashicorp/random",
+		Version: "3.6.0",
+	}
+	config := map[string]any{"length": 16}
+
+	// Fire many parallel calls at a provider that is not loaded yet, as concurrent tool calls would
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Every call gets its own copy, like separate tool calls
+			providerConfig := providerConfig
+
+			switch i % 4 {
+			case 0:
+				_, err := adapter.DescribeResource(ctx, &providerConfig, "random_password")
+				assert.NoError(t, err)
+			case 1:
+				_, err := adapter.ListResources(ctx, &providerConfig)
+				assert.NoError(t, err)
+			case 2:
+				diags, err := adapter.ValidateResource(ctx, &providerConfig, "random_password", nil, config)
+				assert.NoError(t, err)
+				assert.False(t, diags.HasErrors())
+			case 3:
+				_, _, err := adapter.PlanResource(ctx, &providerConfig, "random_password", nil, config)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+	assert.Len(t, adapter.providers, 1, "the provider must be started only once")
 }
@@ -4,15 +4,18 @@
 package provider
 
 import (
-	"archive/zip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"maps"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/opentofu/provider-client/tofuprovider"
 	"github.com/opentofu/provider-client/tofuprovider/providerops"
@@ -22,74 +25,179 @@ import (
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
-// NewOpenTofuAdapter creates a new adapter using the opentofu-providers library
-func NewOpenTofuAdapter(tmpDir string, registry types.RegistryClient) types.ProviderManager {
-	return &OpenTofuAdapter{
+// sourceProviderHost is the registry host of provider addresses sent to providers, as OpenTofu sends them
+const sourceProviderHost = "registry.opentofu.org"
+
+// loadTimeout bounds a provider load shared by concurrent callers, from the download to configuring the
+// plugin. Shared loads do not end with the context of the caller that started them.
+const loadTimeout = 10 * time.Minute
+
+// NewOpenTofuAdapter creates a new adapter using the opentofu-providers library.
+// Provider plugins are stopped according to pluginPolicy and started again when needed.
+// Providers in devOverrides are started from their local binary instead of being downloaded.
+func NewOpenTofuAdapter(tmpDir string, registry types.RegistryClient, pluginPolicy types.PluginPolicy, devOverrides types.DevOverrides) types.ProviderManager {
+	adapter := &OpenTofuAdapter{
 		tmpDir:          tmpDir,
 		registry:        registry,
-		providers:       make(map[string]tofuprovider.GRPCPluginProvider),
+		pluginPolicy:    pluginPolicy,
+		devOverrides:    devOverrides,
+		providers:       make(map[string]*runningPlugin),
 		schemas:         make(map[string]*types.ProviderSchema),
 		rawSchemas:      make(map[string]providerops.GetProviderSchemaResponse),
 		converter:       &CtyConverter{},
 		schemaConverter: &SchemaConverter{},
 		binaries:        make(map[string]string),
+		protocols:       make(map[string]int),
+		ephemerals:      make(map[string]*openEphemeral),
+		acquiring:       make(map[string]int),
+		done:            make(chan struct{}),
+	}
+
+	if pluginPolicy.IdleTimeout > 0 {
+		go adapter.evictIdlePlugins(adapter.done)
 	}
+
+	return adapter
 }
 
-// OpenTofuAdapter implements ProviderAdapter using the opentofu-providers library
+// OpenTofuAdapter implements ProviderAdapter using the opentofu-providers library.
+// It is safe for concurrent use: the caches are guarded by mu, and loads and downloads
+// of the same provider are shared between concurrent callers.
 type OpenTofuAdapter struct {
 	tmpDir          string
 	registry        types.RegistryClient
-	providers       map[string]tofuprovider.GRPCPluginProvider
-	schemas         map[string]*types.ProviderSchema
-	rawSchemas      map[string]providerops.GetProviderSchemaResponse // provider key -> raw schema response
 	converter       *CtyConverter
 	schemaConverter *SchemaConverter
-	binaries        map[string]string // provider key -> binary path
+	pluginPolicy    types.PluginPolicy
+	devOverrides    types.DevOverrides
+
+	mu         sync.RWMutex
+	providers  map[string]*runningPlugin // provider cache key -> running plugin
+	schemas    map[string]*types.ProviderSchema
+	rawSchemas map[string]providerops.GetProviderSchemaResponse // provider key -> raw schema response
+	binaries   map[string]string                                // provider key -> binary path
+	protocols  map[string]int                                   // provider key -> negotiated plugin protocol
+	ephemerals map[string]*openEphemeral                        // ephemeral resource ID -> open ephemeral resource
+	acquiring  map[string]int                                   // provider key -> acquireProvider calls still loading the plugin
+
+	loads     flightGroup[types.Diagnostics] // by provider cache key
+	downloads flightGroup[string]            // by name@version
+
+	done      chan struct{} // closed by Cleanup to stop idle eviction
+	closeDone sync.Once
 }
 
 func (a *OpenTofuAdapter) GetProviderVersions(ctx context.Context, provider types.ProviderConfig) ([]types.ProviderVersionInfo, error) {
 	return a.registry.GetProviderVersions(ctx, provider)
 }
 
-func (a *OpenTofuAdapter) LoadProvider(ctx context.Context, providerConfig *types.ProviderConfig) error {
+// LoadProvider starts and configures the plugin of a provider unless it is running already. The
+// diagnostics of configuring it are returned to every caller waiting for the load.
+func (a *OpenTofuAdapter) LoadProvider(ctx context.Context, providerConfig *types.ProviderConfig) (types.Diagnostics, error) {
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
 	cacheKey, err := providerConfig.FullName()
 	if err != nil {
-		return fmt.Errorf("invalid provider config: %w", err)
+		return nil, fmt.Errorf("invalid provider config: %w", err)
 	}
 
 	// Check if already loaded
-	if _, exists := a.providers[cacheKey]; exists {
-		return nil
+	if a.isLoaded(providerKey) {
+		return nil, nil
 	}
 
-	provider, schema, rawSchema, err := a.loadProvider(ctx, providerConfig)
-	if err != nil {
-		return err
-	}
+	return a.loads.do(ctx, providerKey, func() (types.Diagnostics, error) {
+		// Another caller may have finished loading it in the meantime
+		if a.isLoaded(providerKey) {
+			return nil, nil
+		}
 
-	if err := a.configureProvider(ctx, providerConfig, provider, rawSchema); err != nil {
-		provider.Close()
-		return err
-	}
+		// Callers waiting for the load must not see it fail because the first one went away
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		provider, schema, rawSchema, err := a.loadProvider(ctx, providerConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		diags, err := a.configureProvider(ctx, providerConfig, provider, rawSchema)
+		if err != nil {
+			provider.Close()
+			return diags, err
+		}
+
+		a.mu.Lock()
+		a.providers[providerKey] = &runningPlugin{
+			GRPCPluginProvider: provider,
+			key:                providerKey,
+			lastUsed:           time.Now(),
+			onCrash:            a.forgetCrashed,
+		}
+		a.schemas[cacheKey] = schema
+		a.rawSchemas[cacheKey] = rawSchema
+		a.mu.Unlock()
+
+		// Make room by stopping the least recently used plugins
+		if a.pluginPolicy.MaxRunning > 0 {
+			a.evictPlugins(time.Now())
+		}
+
+		return diags, nil
+	})
+}
+
+// isLoaded reports whether a plugin is running for a provider cache key
+func (a *OpenTofuAdapter) isLoaded(providerKey string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 
-	a.providers[cacheKey] = provider
-	a.schemas[cacheKey] = schema
-	a.rawSchemas[cacheKey] = rawSchema
+	return a.providers[providerKey] != nil
+}
+
+// providerSchema returns the converted schema of a loaded provider version
+func (a *OpenTofuAdapter) providerSchema(cacheKey string) (*types.ProviderSchema, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 
-	return nil
+	schema, ok := a.schemas[cacheKey]
+	return schema, ok
 }
 
-func (a *OpenTofuAdapter) configureProvider(ctx context.Context, providerConfig *types.ProviderConfig, provider tofuprovider.GRPCPluginProvider, schemaResponse providerops.GetProviderSchemaResponse) error {
+// configureProvider configures a provider with the explicit configuration, or else the first of the
+// fallback configurations it accepts. Only the diagnostics of the configuration that was used, or of
+// the last one tried, are returned.
+func (a *OpenTofuAdapter) configureProvider(ctx context.Context, providerConfig *types.ProviderConfig, provider tofuprovider.GRPCPluginProvider, schemaResponse providerops.GetProviderSchemaResponse) (types.Diagnostics, error) {
 	configCandidates := []func(providerops.GetProviderSchemaResponse) (providerschema.DynamicValueIn, error){
 		a.emptyConfig,               // first, try empty config
 		a.configWithEmptyProperties, // second, try config with all properties set to undefined (null or empty)
 		/// ^^^ "hashicorp/azurerm" requires "features" block to be present, even if empty
 	}
 
+	// Explicit configuration is the only candidate - silently falling back to an
+	// empty config would manage resources in a region or account nobody asked for.
+	if len(providerConfig.Config) > 0 {
+		configCandidates = []func(providerops.GetProviderSchemaResponse) (providerschema.DynamicValueIn, error){
+			func(schemaResponse providerops.GetProviderSchemaResponse) (providerschema.DynamicValueIn, error) {
+				return a.explicitConfig(schemaResponse, providerConfig.Config)
+			},
+		}
+	}
+
+	// Configure through the raw client, the provider client drops the attribute paths of diagnostics
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, err
+	}
+
 	finalErr := errors.New("no configuration method succeeded")
 
+	var diags types.Diagnostics
 	for _, configCandidate := range configCandidates {
+		diags = nil
 		config, err := configCandidate(schemaResponse)
 		if err != nil {
 			finalErr = err
@@ -101,21 +209,22 @@ func (a *OpenTofuAdapter) configureProvider(ctx context.Context, providerConfig
 			TerraformVersion: "1.6.0",
 		}
 
-		configResp, err := provider.ConfigureProvider(ctx, configReq)
+		configDiags, err := client.configureProvider(ctx, configReq)
 		if err != nil {
 			finalErr = fmt.Errorf("failed to configure provider %s: %w", providerConfig.Name, err)
 			continue
 		}
 
-		if configResp.Diagnostics().HasErrors() {
-			finalErr = fmt.Errorf("provider configuration failed: %s", a.formatDiagnostics(configResp.Diagnostics()))
+		diags = configDiags
+		if diags.HasErrors() {
+			finalErr = fmt.Errorf("provider configuration failed: %w", &types.DiagnosticsError{Diagnostics: diags})
 			continue
 		}
 
-		return nil
+		return diags, nil
 	}
 
-	return finalErr
+	return diags, finalErr
 }
 
 func (a *OpenTofuAdapter) emptyConfig(_ providerops.GetProviderSchemaResponse) (providerschema.DynamicValueIn, error) {
@@ -127,6 +236,48 @@ func (a *OpenTofuAdapter) emptyConfig(_ providerops.GetProviderSchemaResponse) (
 	return providerschema.NewDynamicValue(emptyConfig, cty.DynamicPseudoType), nil
 }
 
+// configToCtyValue converts a user configuration with its schema. Attributes and nested blocks the user
+// left out are filled with typed nulls and empty blocks, so only the fields that matter need to be sent.
+// References to ephemeral resource values in write-only attributes are replaced with the values.
+func (a *OpenTofuAdapter) configToCtyValue(schema providerschema.Schema, config map[string]any) (cty.Value, error) {
+	if config == nil {
+		config = map[string]any{}
+	}
+
+	config, err := a.resolveEphemeralValues(schema, config, false)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	configCty, err := a.converter.MapToCtyValue(config, a.schemaConverter.opentofuSchemaToObjectType(schema))
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return a.schemaConverter.fillNestedBlocks(schema, configCty), nil
+}
+
+// explicitConfig encodes user-supplied provider configuration using the provider config schema
+func (a *OpenTofuAdapter) explicitConfig(schemaResponse providerops.GetProviderSchemaResponse, config map[string]any) (providerschema.DynamicValueIn, error) {
+	configSchema := schemaResponse.ProviderSchema().ProviderConfigSchema()
+	configType := a.schemaConverter.opentofuSchemaToObjectType(configSchema)
+
+	if configType.IsObjectType() {
+		for name := range config {
+			if !configType.HasAttribute(name) {
+				return providerschema.DynamicValueIn{}, fmt.Errorf("unsupported provider configuration argument %q", name)
+			}
+		}
+	}
+
+	configCty, err := a.configToCtyValue(configSchema, config)
+	if err != nil {
+		return providerschema.DynamicValueIn{}, fmt.Errorf("failed to convert provider config: %w", err)
+	}
+
+	return providerschema.NewDynamicValue(configCty, configType), nil
+}
+
 func (a *OpenTofuAdapter) configWithEmptyProperties(schemaResponse providerops.GetProviderSchemaResponse) (providerschema.DynamicValueIn, error) {
 	sh := schemaResponse.ProviderSchema().ProviderConfigSchema()
 	attrs := maps.Collect(sh.Attributes())
@@ -161,8 +312,8 @@ func (a *OpenTofuAdapter) loadProvider(ctx context.Context, providerConfig *type
 		return nil, nil, nil, fmt.Errorf("invalid provider name format, expected 'namespace/type'")
 	}
 
-	// Download provider if needed
-	binary, err := a.downloadProvider(ctx, providerConfig)
+	// Use the development override, or download the provider if needed
+	binary, err := a.providerBinary(ctx, providerConfig)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to download provider %s: %w", providerConfig.Name, err)
 	}
@@ -172,7 +323,9 @@ func (a *OpenTofuAdapter) loadProvider(ctx context.Context, providerConfig *type
 		return nil, nil, nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
+	a.mu.Lock()
 	a.binaries[cacheKey] = binary
+	a.mu.Unlock()
 
 	// Start the provider using the opentofu-providers library
 	provider, err := tofuprovider.StartGRPCPlugin(ctx, binary)
@@ -182,6 +335,7 @@ func (a *OpenTofuAdapter) loadProvider(ctx context.Context, providerConfig *type
 
 	schema, rawSchema, err := a.getSchemaWithProvider(ctx, providerConfig, provider)
 	if err != nil {
+		provider.Close()
 		return nil, nil, nil, fmt.Errorf("failed to get provider schema for %s: %w", providerConfig.Name, err)
 	}
 
@@ -197,7 +351,7 @@ func (a *OpenTofuAdapter) DescribeProvider(ctx context.Context, providerConfig *
 	defer provider.Close()
 
 	var configureError *string
-	if err := a.configureProvider(ctx, providerConfig, provider, rawSchema); err != nil {
+	if _, err := a.configureProvider(ctx, providerConfig, provider, rawSchema); err != nil {
 		message := err.Error()
 		configureError = &message
 	}
@@ -217,9 +371,14 @@ func (a *OpenTofuAdapter) getSchemaWithProvider(ctx context.Context, providerCon
 		Provider: &types.TypeDescription{
 			ProviderName: providerConfig.Name,
 		},
-		Resources:   make(map[string]*types.TypeDescription),
-		DataSources: make(map[string]*types.TypeDescription),
-		Version:     providerConfig.Version,
+		Resources:          make(map[string]*types.TypeDescription),
+		DataSources:        make(map[string]*types.TypeDescription),
+		EphemeralResources: make(map[string]*types.TypeDescription),
+		Version:            providerConfig.Version,
+	}
+
+	if versionedName, err := providerConfig.FullName(); err == nil {
+		schema.ProtocolVersion = a.providerProtocol(versionedName)
 	}
 
 	providerSchema := schemaResp.ProviderSchema()
@@ -241,6 +400,12 @@ func (a *OpenTofuAdapter) getSchemaWithProvider(ctx context.Context, providerCon
 		schema.DataSources[dataSourceType] = desc
 	}
 
+	// Convert ephemeral resource schemas
+	for ephemeralType, ephemeralSchema := range providerSchema.EphemeralResourceTypeSchemas() {
+		desc := a.schemaConverter.convertSchemaToTypeDescription(providerConfig.Name, ephemeralType, ephemeralSchema, "ephemeral_resource")
+		schema.EphemeralResources[ephemeralType] = desc
+	}
+
 	return schema, schemaResp, nil
 }
 
@@ -252,7 +417,11 @@ func (a *OpenTofuAdapter) getRawSchema(_ context.Context, providerConfig *types.
 		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	if rawSchema, exists := a.rawSchemas[cacheKey]; exists {
+	a.mu.RLock()
+	rawSchema, exists := a.rawSchemas[cacheKey]
+	a.mu.RUnlock()
+
+	if exists {
 		return rawSchema, nil
 	}
 
@@ -273,19 +442,21 @@ func (a *OpenTofuAdapter) getRawSchemaWithProvider(ctx context.Context, provider
 		return nil, fmt.Errorf("failed to get provider schema: %w", err)
 	}
 
-	if schemaResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("schema request failed: %s", a.formatDiagnostics(schemaResp.Diagnostics()))
+	if err := checkDiagnostics(convertDiagnostics(schemaResp.Diagnostics())); err != nil {
+		return nil, fmt.Errorf("schema request failed: %w", err)
 	}
 
 	// Cache the raw schema
+	a.mu.Lock()
 	a.rawSchemas[cacheKey] = schemaResp
+	a.mu.Unlock()
 
 	return schemaResp, nil
 }
 
 // getOpentofuResourceSchema gets the raw opentofu-providers Schema for a resource type
 func (a *OpenTofuAdapter) getOpentofuResourceSchema(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string) (providerschema.Schema, error) {
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -306,7 +477,7 @@ func (a *OpenTofuAdapter) getOpentofuResourceSchema(ctx context.Context, provide
 
 // getOpentofuDataSourceSchema gets the raw opentofu-providers Schema for a data source type
 func (a *OpenTofuAdapter) getOpentofuDataSourceSchema(ctx context.Context, providerConfig *types.ProviderConfig, dataSourceType string) (providerschema.Schema, error) {
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -331,113 +502,350 @@ func (a *OpenTofuAdapter) getOpentofuDataSourceSchema(ctx context.Context, provi
 	return opentofuSchema, nil
 }
 
-func (a *OpenTofuAdapter) PlanResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *map[string]any, newConfig map[string]any) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+// ValidateResource asks the provider to validate a resource configuration without calling any cloud API.
+// When currentState is given the config is merged over it the same way as for an update.
+func (a *OpenTofuAdapter) ValidateResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState, config map[string]any) (types.Diagnostics, error) {
+	// Ensure provider is loaded, the diagnostics of configuring it are passed on with those of the validation
+	loadDiags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return loadDiags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+	}
+
+	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// Mistakes found locally are reported without asking the provider, with suggestions where possible
+	if diags := lintConfig(opentofuSchema, config, !currentState.IsEmpty()); diags.HasErrors() {
+		return append(loadDiags, diags...), nil
+	}
+
+	// Computed-only values of the state are not configuration, providers reject them
+	if !currentState.IsEmpty() {
+		state := withoutComputed(maps.Collect(opentofuSchema.Attributes()), maps.Collect(opentofuSchema.NestedBlockTypes()), currentState.State)
+		config = mergeConfig(state, config)
+	}
+
+	configCty, err := a.configToCtyValue(opentofuSchema, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config: %w", err)
+	}
+
+	// Validation goes through the raw client to keep the attribute paths of the diagnostics
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	diags, err := client.validateResourceConfig(ctx, resourceType, providerschema.NewDynamicValue(configCty, resourceTypeCty))
+	if err != nil {
+		return nil, fmt.Errorf("validate failed: %w", err)
+	}
+
+	return append(loadDiags, diags...), nil
+}
+
+// ValidateDataSource asks the provider to validate a data source configuration without reading it
+func (a *OpenTofuAdapter) ValidateDataSource(ctx context.Context, providerConfig *types.ProviderConfig, dataSourceType string, config map[string]any) (types.Diagnostics, error) {
+	// Ensure provider is loaded, the diagnostics of configuring it are passed on with those of the validation
+	loadDiags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return loadDiags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	opentofuSchema, err := a.getOpentofuDataSourceSchema(ctx, providerConfig, dataSourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+	}
+
+	dataSourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// Mistakes found locally are reported without asking the provider, with suggestions where possible
+	if diags := lintConfig(opentofuSchema, config, false); diags.HasErrors() {
+		return append(loadDiags, diags...), nil
+	}
+
+	configCty, err := a.configToCtyValue(opentofuSchema, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config: %w", err)
+	}
+
+	// Validation goes through the raw client to keep the attribute paths of the diagnostics
+	client, err := newRawClient(provider)
+	if err != nil {
 		return nil, err
 	}
 
+	diags, err := client.validateDataSourceConfig(ctx, dataSourceType, providerschema.NewDynamicValue(configCty, dataSourceTypeCty))
+	if err != nil {
+		return nil, fmt.Errorf("validate failed: %w", err)
+	}
+
+	return append(loadDiags, diags...), nil
+}
+
+// PlanResource plans creating a resource, or updating it when currentState is given, without applying the change
+func (a *OpenTofuAdapter) PlanResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState, newConfig map[string]any) (*types.ResourcePlan, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
 	cacheKey, err := providerConfig.FullName()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
-	schema := a.schemas[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
+	schema, _ := a.providerSchema(cacheKey)
 
 	_, exists := schema.Resources[resourceType]
 	if !exists {
-		return nil, fmt.Errorf("resource type %s not found", resourceType)
+		return nil, diags, fmt.Errorf("resource type %s not found", resourceType)
 	}
 
 	// Get the opentofu-providers schema directly for proper type conversion
 	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert current state to cty.Value
-	var priorState providerschema.DynamicValueIn
-	if currentState != nil && len(*currentState) > 0 {
-		priorStateCty, err := a.converter.MapToCtyValue(*currentState, resourceTypeCty)
+	// For new resources, use null value of the proper type instead of NoDynamicValue
+	priorStateCty := cty.NullVal(resourceTypeCty)
+	var priorPrivate []byte
+	if !currentState.IsEmpty() {
+		priorStateCty, err = a.converter.MapToCtyValue(currentState.State, resourceTypeCty)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert prior state: %w", err)
+			return nil, diags, fmt.Errorf("failed to convert prior state: %w", err)
 		}
-		priorState = providerschema.NewDynamicValue(priorStateCty, resourceTypeCty)
-	} else {
-		// For new resources, use null value of the proper type instead of NoDynamicValue
-		nullStateCty := cty.NullVal(resourceTypeCty)
-		priorState = providerschema.NewDynamicValue(nullStateCty, resourceTypeCty)
+		priorPrivate = currentState.Private
+		newConfig = mergeConfig(currentState.State, newConfig)
 	}
+	priorState := providerschema.NewDynamicValue(priorStateCty, resourceTypeCty)
 
 	// Convert new config to cty.Value
-	configCty, err := a.converter.MapToCtyValue(newConfig, resourceTypeCty)
+	configCty, err := a.configToCtyValue(opentofuSchema, newConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert config: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
 	}
 	config := providerschema.NewDynamicValue(configCty, resourceTypeCty)
 
 	// Create plan request
 	planReq := &providerops.PlanManagedResourceChangeRequest{
-		ResourceType:     resourceType,
-		PriorState:       priorState,
-		Config:           config,
-		ProposedNewState: config, // Use config as proposed state for simplicity
+		ResourceType:          resourceType,
+		PriorState:            priorState,
+		Config:                config,
+		ProposedNewState:      config, // Use config as proposed state for simplicity
+		PriorProviderInternal: priorPrivate,
 	}
 
-	// Plan the resource
-	planResp, err := provider.PlanManagedResourceChange(ctx, planReq)
+	// Plan the resource through the raw client, the provider client does not expose the attributes
+	// whose change requires replacing the resource
+	client, err := newRawClient(provider)
 	if err != nil {
-		return nil, fmt.Errorf("plan failed: %w", err)
+		return nil, diags, err
 	}
 
-	if planResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("plan failed: %s", a.formatDiagnostics(planResp.Diagnostics()))
+	planned, planDiags, err := client.planResourceChange(ctx, planReq)
+	diags = append(diags, planDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("plan failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("plan failed: %w", err)
 	}
 
 	// Convert planned state back to map
-	plannedStateCty, err := planResp.PlannedNewState().AsCtyValue(resourceTypeCty)
+	plannedStateCty, err := planned.plannedState.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode planned state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode planned state: %w", err)
 	}
 
 	plannedStateMap, err := a.converter.CtyValueToMap(plannedStateCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert planned state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert planned state to map: %w", err)
+	}
+
+	var requiresReplacePaths []string
+	for _, path := range planned.requiresReplace {
+		requiresReplacePaths = append(requiresReplacePaths, formatPath(path))
+	}
+
+	sensitive := sensitiveAttributes(maps.Collect(opentofuSchema.Attributes()), maps.Collect(opentofuSchema.NestedBlockTypes()))
+
+	changes, err := a.converter.diffPlannedState(priorStateCty, plannedStateCty, planned.requiresReplace, sensitive)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to diff planned state: %w", err)
 	}
 
-	return plannedStateMap, nil
+	return &types.ResourcePlan{
+		Action:          planAction(priorStateCty, changes),
+		Changes:         changes,
+		RequiresReplace: requiresReplacePaths,
+		PlannedState: &types.ResourceState{
+			State:         plannedStateMap,
+			Private:       planned.plannedPrivate,
+			SchemaVersion: opentofuSchema.SchemaVersion(),
+		},
+	}, diags, nil
 }
 
-func (a *OpenTofuAdapter) CreateResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, config map[string]any) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return nil, err
+// ApplyResource applies a previously planned state, creating the resource when currentState is empty
+func (a *OpenTofuAdapter) ApplyResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState, newConfig map[string]any, plannedState *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
+
+	// Get the opentofu-providers schema directly for proper type conversion
+	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	// Convert opentofu-providers schema to proper cty.Type
+	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// Convert current state to cty.Value, null for new resources
+	priorStateCty := cty.NullVal(resourceTypeCty)
+	if !currentState.IsEmpty() {
+		priorStateCty, err = a.converter.MapToCtyValue(currentState.State, resourceTypeCty)
+		if err != nil {
+			return nil, diags, fmt.Errorf("failed to convert prior state: %w", err)
+		}
+		newConfig = mergeConfig(currentState.State, newConfig)
+	}
+
+	configCty, err := a.configToCtyValue(opentofuSchema, newConfig)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
+	}
+
+	// Unknown values in the planned state are restored from the markers written when it was saved
+	plannedStateCty, err := a.converter.MapToCtyValue(plannedState.State, resourceTypeCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert planned state: %w", err)
+	}
+
+	applyReq := &providerops.ApplyManagedResourceChangeRequest{
+		ResourceType:            resourceType,
+		PriorState:              providerschema.NewDynamicValue(priorStateCty, resourceTypeCty),
+		Config:                  providerschema.NewDynamicValue(configCty, resourceTypeCty),
+		PlannedNewState:         providerschema.NewDynamicValue(plannedStateCty, resourceTypeCty),
+		PlannedProviderInternal: plannedState.Private,
+	}
+
+	applied, applyDiags, err := a.applyChange(ctx, provider, applyReq, resourceTypeCty, opentofuSchema.SchemaVersion())
+	diags = append(diags, applyDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("apply failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("apply failed: %w", err)
+	}
+
+	// Convert final state back to map
+	finalStateCty, err := applied.newState.AsCtyValue(resourceTypeCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to decode final state: %w", err)
+	}
+
+	finalStateMap, err := a.converter.CtyValueToMap(finalStateCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert final state to map: %w", err)
+	}
+
+	return &types.ResourceState{
+		State:         finalStateMap,
+		Private:       applied.private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, nil
+}
+
+func (a *OpenTofuAdapter) CreateResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, config map[string]any) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
 
 	// Get resource schema
 	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert config to cty.Value
-	configCty, err := a.converter.MapToCtyValue(config, resourceTypeCty)
+	configCty, err := a.configToCtyValue(opentofuSchema, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert config: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
 	}
 	configDV := providerschema.NewDynamicValue(configCty, resourceTypeCty)
 
@@ -453,19 +861,25 @@ func (a *OpenTofuAdapter) CreateResource(ctx context.Context, providerConfig *ty
 		ProposedNewState: configDV,
 	}
 
-	planResp, err := provider.PlanManagedResourceChange(ctx, planReq)
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	planned, planDiags, err := client.planResourceChange(ctx, planReq)
+	diags = append(diags, planDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("plan failed: %w", err)
+		return nil, diags, fmt.Errorf("plan failed: %w", err)
 	}
 
-	if planResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("plan failed: %s", a.formatDiagnostics(planResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("plan failed: %w", err)
 	}
 
-	// Convert planned state from DynamicValueOut to DynamicValueIn
-	plannedStateCty, err := planResp.PlannedNewState().AsCtyValue(resourceTypeCty)
+	// Decode the planned state to pass it back to the provider
+	plannedStateCty, err := planned.plannedState.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode planned state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode planned state: %w", err)
 	}
 	plannedStateDV := providerschema.NewDynamicValue(plannedStateCty, resourceTypeCty)
 
@@ -475,73 +889,82 @@ func (a *OpenTofuAdapter) CreateResource(ctx context.Context, providerConfig *ty
 		PriorState:              priorState,
 		Config:                  configDV,
 		PlannedNewState:         plannedStateDV,
-		PlannedProviderInternal: planResp.PlannedProviderInternal(),
+		PlannedProviderInternal: planned.plannedPrivate,
 	}
 
-	applyResp, err := provider.ApplyManagedResourceChange(ctx, applyReq)
+	applied, applyDiags, err := a.applyChange(ctx, provider, applyReq, resourceTypeCty, opentofuSchema.SchemaVersion())
+	diags = append(diags, applyDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("apply failed: %w", err)
+		return nil, diags, fmt.Errorf("apply failed: %w", err)
 	}
 
 	var applyErr error
-	if applyResp.Diagnostics().HasErrors() {
-		applyErr = fmt.Errorf("apply failed: %s", a.formatDiagnostics(applyResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		applyErr = fmt.Errorf("apply failed: %w", err)
 	}
 
-	// Convert final state back to map — PlannedNewState() can be nil when diagnostics have errors.
-	newState := applyResp.PlannedNewState()
-	if newState == nil {
+	// Convert final state back to map — the new state can be missing when diagnostics have errors.
+	if applied.newState.isMissing() {
 		if applyErr != nil {
-			return nil, applyErr
+			return nil, diags, applyErr
 		}
-		return nil, errors.New("apply returned nil state")
+		return nil, diags, errors.New("apply returned nil state")
 	}
 
-	finalStateCty, err := newState.AsCtyValue(resourceTypeCty)
+	finalStateCty, err := applied.newState.AsCtyValue(resourceTypeCty)
 	if err != nil {
 		if applyErr != nil {
-			return nil, applyErr
+			return nil, diags, applyErr
 		}
-		return nil, fmt.Errorf("failed to decode final state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode final state: %w", err)
 	}
 
 	finalStateMap, err := a.converter.CtyValueToMap(finalStateCty)
 	if err != nil {
 		if applyErr != nil {
-			return nil, applyErr
+			return nil, diags, applyErr
 		}
-		return nil, fmt.Errorf("failed to convert final state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert final state to map: %w", err)
 	}
 
-	return finalStateMap, applyErr
+	return &types.ResourceState{
+		State:         finalStateMap,
+		Private:       applied.private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, applyErr
 }
 
-func (a *OpenTofuAdapter) DeleteResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, state map[string]any) error {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return err
+func (a *OpenTofuAdapter) DeleteResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, state *types.ResourceState) (types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return fmt.Errorf("failed to get versioned provider name: %w", err)
+		return diags, fmt.Errorf("failed to get provider cache key: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return diags, err
+	}
+	defer release()
 
 	// Get resource schema
 	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
 	if err != nil {
-		return fmt.Errorf("failed to get opentofu schema: %w", err)
+		return diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert current state to cty.Value
-	currentStateCty, err := a.converter.MapToCtyValue(state, resourceTypeCty)
+	currentStateCty, err := a.converter.MapToCtyValue(state.State, resourceTypeCty)
 	if err != nil {
-		return fmt.Errorf("failed to convert current state: %w", err)
+		return diags, fmt.Errorf("failed to convert current state: %w", err)
 	}
 	priorState := providerschema.NewDynamicValue(currentStateCty, resourceTypeCty)
 
@@ -552,25 +975,32 @@ func (a *OpenTofuAdapter) DeleteResource(ctx context.Context, providerConfig *ty
 
 	// First plan the deletion
 	planReq := &providerops.PlanManagedResourceChangeRequest{
-		ResourceType:     resourceType,
-		PriorState:       priorState,
-		Config:           emptyConfig,
-		ProposedNewState: proposedNewState,
+		ResourceType:          resourceType,
+		PriorState:            priorState,
+		Config:                emptyConfig,
+		ProposedNewState:      proposedNewState,
+		PriorProviderInternal: state.Private,
+	}
+
+	client, err := newRawClient(provider)
+	if err != nil {
+		return diags, err
 	}
 
-	planResp, err := provider.PlanManagedResourceChange(ctx, planReq)
+	planned, planDiags, err := client.planResourceChange(ctx, planReq)
+	diags = append(diags, planDiags...)
 	if err != nil {
-		return fmt.Errorf("plan deletion failed: %w", err)
+		return diags, fmt.Errorf("plan deletion failed: %w", err)
 	}
 
-	if planResp.Diagnostics().HasErrors() {
-		return fmt.Errorf("plan deletion failed: %s", a.formatDiagnostics(planResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return diags, fmt.Errorf("plan deletion failed: %w", err)
 	}
 
-	// Convert planned state from DynamicValueOut to DynamicValueIn
-	plannedStateCty, err := planResp.PlannedNewState().AsCtyValue(resourceTypeCty)
+	// Decode the planned state to pass it back to the provider
+	plannedStateCty, err := planned.plannedState.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return fmt.Errorf("failed to decode planned deletion state: %w", err)
+		return diags, fmt.Errorf("failed to decode planned deletion state: %w", err)
 	}
 	plannedStateDV := providerschema.NewDynamicValue(plannedStateCty, resourceTypeCty)
 
@@ -580,47 +1010,53 @@ func (a *OpenTofuAdapter) DeleteResource(ctx context.Context, providerConfig *ty
 		PriorState:              priorState,
 		Config:                  emptyConfig,
 		PlannedNewState:         plannedStateDV,
-		PlannedProviderInternal: planResp.PlannedProviderInternal(),
+		PlannedProviderInternal: planned.plannedPrivate,
 	}
 
-	applyResp, err := provider.ApplyManagedResourceChange(ctx, applyReq)
+	_, applyDiags, err := a.applyChange(ctx, provider, applyReq, resourceTypeCty, opentofuSchema.SchemaVersion())
+	diags = append(diags, applyDiags...)
 	if err != nil {
-		return fmt.Errorf("apply deletion failed: %w", err)
+		return diags, fmt.Errorf("apply deletion failed: %w", err)
 	}
 
-	if applyResp.Diagnostics().HasErrors() {
-		return fmt.Errorf("apply deletion failed: %s", a.formatDiagnostics(applyResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return diags, fmt.Errorf("apply deletion failed: %w", err)
 	}
 
-	return nil
+	return diags, nil
 }
 
-func (a *OpenTofuAdapter) ReadDataSource(ctx context.Context, providerConfig *types.ProviderConfig, dataSourceType string, config map[string]any) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return nil, err
+func (a *OpenTofuAdapter) ReadDataSource(ctx context.Context, providerConfig *types.ProviderConfig, dataSourceType string, config map[string]any) (map[string]any, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
 
 	// Get data source schema for proper type information
 	opentofuSchema, err := a.getOpentofuDataSourceSchema(ctx, providerConfig, dataSourceType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	dataSourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert config to cty.Value
-	configCty, err := a.converter.MapToCtyValue(config, dataSourceTypeCty)
+	configCty, err := a.configToCtyValue(opentofuSchema, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert config: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
 	}
 	configDV := providerschema.NewDynamicValue(configCty, dataSourceTypeCty)
 
@@ -631,149 +1067,205 @@ func (a *OpenTofuAdapter) ReadDataSource(ctx context.Context, providerConfig *ty
 		ProviderMeta: configDV,
 	}
 
-	// Read the data source
-	readResp, err := provider.ReadDataResource(ctx, readReq)
+	// Read the data source through the raw client, the provider client drops the attribute paths of diagnostics
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	state, readDiags, err := client.readDataSource(ctx, readReq)
+	diags = append(diags, readDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("read data source failed: %w", err)
+		return nil, diags, fmt.Errorf("read data source failed: %w", err)
 	}
 
-	if readResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("read data source failed: %s", a.formatDiagnostics(readResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("read data source failed: %w", err)
 	}
 
 	// Convert state back to map
-	stateCty, err := readResp.State().AsCtyValue(dataSourceTypeCty)
+	stateCty, err := state.AsCtyValue(dataSourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode state: %w", err)
 	}
 
 	stateMap, err := a.converter.CtyValueToMap(stateCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert state to map: %w", err)
 	}
 
-	return stateMap, nil
+	return stateMap, diags, nil
 }
 
-func (a *OpenTofuAdapter) ImportResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType, importID string) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return nil, err
+func (a *OpenTofuAdapter) ImportResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, target types.ImportTarget) ([]types.ImportedResource, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
 
-	// Get resource schema for proper type information
-	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
+	// The provider client does not implement resource identities, imports go through the raw client
+	client, err := newRawClient(provider)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, err
 	}
 
-	// Convert opentofu-providers schema to proper cty.Type
-	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+	// Identity schemas are needed to import by identity and to decode the identities of imported resources.
+	// Providers that predate resource identities do not have any.
+	identitySchemas, identityErr := a.getIdentitySchemas(ctx, client)
 
-	// Create import request
-	importReq := &providerops.ImportManagedResourceStateRequest{
-		ResourceType: resourceType,
-		ID:           importID,
+	identity := providerschema.NoDynamicValue
+	if target.Identity != nil {
+		if target.ID != "" {
+			return nil, diags, fmt.Errorf("an import ID and an identity are mutually exclusive")
+		}
+		if identityErr != nil {
+			return nil, diags, fmt.Errorf("failed to get resource identity schemas: %w", identityErr)
+		}
+
+		identity, err = a.importIdentity(ctx, client, identitySchemas, resourceType, target)
+		if err != nil {
+			return nil, diags, err
+		}
 	}
 
 	// Import the resource
-	importResp, err := provider.ImportManagedResourceState(ctx, importReq)
+	resources, importDiags, err := client.importResourceState(ctx, resourceType, target.ID, identity)
+	diags = append(diags, importDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("import failed: %w", err)
+		return nil, diags, fmt.Errorf("import failed: %w", err)
 	}
 
-	if importResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("import failed: %s", a.formatDiagnostics(importResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("import failed: %w", err)
 	}
 
-	// Get the first imported resource state (providers can return multiple)
-	importedResourcesIter := importResp.ImportedResources()
-	var firstResource providerops.ImportedManagedResource
-	var found bool
+	// Providers can return several resources, e.g. a security group and its rules
+	var imported []types.ImportedResource
+	for _, resource := range resources {
+		importedResource, readDiags, err := a.readImportedResource(ctx, providerConfig, client, resource, identitySchemas)
+		diags = append(diags, readDiags...)
+		if err != nil {
+			return nil, diags, err
+		}
+		imported = append(imported, *importedResource)
+	}
 
-	// Get the first resource from the iterator
-	for resource := range importedResourcesIter {
-		firstResource = resource
-		found = true
-		break
+	if len(imported) == 0 {
+		return nil, diags, fmt.Errorf("no resources were imported")
 	}
 
-	if !found {
-		return nil, fmt.Errorf("no resources were imported")
+	return imported, diags, nil
+}
+
+// readImportedResource reads an imported resource to get its complete current state, not just what
+// the import returned
+func (a *OpenTofuAdapter) readImportedResource(ctx context.Context, providerConfig *types.ProviderConfig, client rawClient, resource importedResource, identitySchemas map[string]identitySchema) (*types.ImportedResource, types.Diagnostics, error) {
+	resourceType := resource.resourceType
+
+	// Get resource schema for proper type information
+	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
-	// Now read the resource to get the complete current state, following the same pattern as legacy manager
-	// This ensures we capture the full resource data, not just what import returned
+	// Convert opentofu-providers schema to proper cty.Type
+	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
-	// First convert the imported state from DynamicValueOut to DynamicValueIn
-	importedStateCty, err := firstResource.State().AsCtyValue(resourceTypeCty)
+	// First decode the imported state to pass it back to the provider
+	importedStateCty, err := resource.state.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode imported state: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode imported %s state: %w", resourceType, err)
 	}
 	importedStateDV := providerschema.NewDynamicValue(importedStateCty, resourceTypeCty)
 
 	readReq := &providerops.ReadManagedResourceRequest{
 		ResourceType:     resourceType,
 		CurrentState:     importedStateDV,
-		ProviderInternal: firstResource.ProviderInternal(),
+		ProviderInternal: resource.private,
 	}
 
-	readResp, err := provider.ReadManagedResource(ctx, readReq)
+	current, diags, err := client.readResource(ctx, readReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read imported resource: %w", err)
+		return nil, diags, fmt.Errorf("failed to read imported %s: %w", resourceType, err)
 	}
 
-	if readResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("read after import failed: %s", a.formatDiagnostics(readResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("read after import of %s failed: %w", resourceType, err)
 	}
 
 	// Convert the complete current state back to map
-	stateCty, err := readResp.NewState().AsCtyValue(resourceTypeCty)
+	stateCty, err := current.state.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode current state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode current %s state: %w", resourceType, err)
 	}
 
 	stateMap, err := a.converter.CtyValueToMap(stateCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert current state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert current state to map: %w", err)
+	}
+
+	imported := &types.ImportedResource{
+		ResourceType: resourceType,
+		State: &types.ResourceState{
+			State:         stateMap,
+			Private:       current.private,
+			SchemaVersion: opentofuSchema.SchemaVersion(),
+		},
 	}
 
-	return stateMap, nil
+	if schema, ok := identitySchemas[resourceType]; ok && resource.identity != nil {
+		imported.Identity, err = a.decodeIdentity(schema, *resource.identity)
+		if err != nil {
+			return nil, diags, fmt.Errorf("failed to decode %s identity: %w", resourceType, err)
+		}
+	}
+
+	return imported, diags, nil
 }
 
-func (a *OpenTofuAdapter) RefreshResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState map[string]any) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return nil, err
+func (a *OpenTofuAdapter) RefreshResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
 
 	// Get resource schema
 	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert current state to cty.Value
-	currentStateCty, err := a.converter.MapToCtyValue(currentState, resourceTypeCty)
+	currentStateCty, err := a.converter.MapToCtyValue(currentState.State, resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert current state: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert current state: %w", err)
 	}
 	currentStateDV := providerschema.NewDynamicValue(currentStateCty, resourceTypeCty)
 
@@ -781,31 +1273,191 @@ func (a *OpenTofuAdapter) RefreshResource(ctx context.Context, providerConfig *t
 	refreshReq := &providerops.ReadManagedResourceRequest{
 		ResourceType:     resourceType,
 		CurrentState:     currentStateDV,
-		ProviderInternal: nil, // No provider internal data for basic refresh
+		ProviderInternal: currentState.Private,
 	}
 
-	// Refresh the resource
-	refreshResp, err := provider.ReadManagedResource(ctx, refreshReq)
+	// Refresh the resource through the raw client, the provider client drops the attribute paths of diagnostics
+	client, err := newRawClient(provider)
 	if err != nil {
-		return nil, fmt.Errorf("refresh failed: %w", err)
+		return nil, diags, err
 	}
 
-	if refreshResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("refresh failed: %s", a.formatDiagnostics(refreshResp.Diagnostics()))
+	refreshed, readDiags, err := client.readResource(ctx, refreshReq)
+	diags = append(diags, readDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("refresh failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("refresh failed: %w", err)
 	}
 
 	// Convert refreshed state back to map
-	refreshedStateCty, err := refreshResp.NewState().AsCtyValue(resourceTypeCty)
+	refreshedStateCty, err := refreshed.state.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode refreshed state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode refreshed state: %w", err)
 	}
 
 	refreshedStateMap, err := a.converter.CtyValueToMap(refreshedStateCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert refreshed state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert refreshed state to map: %w", err)
 	}
 
-	return refreshedStateMap, nil
+	return &types.ResourceState{
+		State:         refreshedStateMap,
+		Private:       refreshed.private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, nil
+}
+
+// UpgradeResourceState upgrades state written with an older resource schema version to the current one
+func (a *OpenTofuAdapter) UpgradeResourceState(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
+
+	// Get resource schema
+	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
+	}
+
+	if state.SchemaVersion > opentofuSchema.SchemaVersion() {
+		return nil, diags, fmt.Errorf("state was written with schema version %d which is newer than version %d supported by %s", state.SchemaVersion, opentofuSchema.SchemaVersion(), providerConfig.Name)
+	}
+
+	// Convert opentofu-providers schema to proper cty.Type
+	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// The provider receives the raw JSON state, exactly as OpenTofu would read it from a state file
+	rawState, err := json.Marshal(state.State)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	// The provider client does not implement state upgrades, they go through the raw client
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	upgradedState, upgradeDiags, err := client.upgradeResourceState(ctx, resourceType, state.SchemaVersion, providerschema.RawState{JSON: rawState})
+	diags = append(diags, upgradeDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("upgrade state failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("upgrade state failed: %w", err)
+	}
+
+	upgradedStateCty, err := upgradedState.AsCtyValue(resourceTypeCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to decode upgraded state: %w", err)
+	}
+
+	upgradedStateMap, err := a.converter.CtyValueToMap(upgradedStateCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert upgraded state to map: %w", err)
+	}
+
+	return &types.ResourceState{
+		State:         upgradedStateMap,
+		Private:       state.Private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, nil
+}
+
+// MoveResourceState asks the provider to convert the state of a resource of another type, possibly managed
+// by another provider, to a resource of targetType. sourceProvider is the namespaced source provider name.
+func (a *OpenTofuAdapter) MoveResourceState(ctx context.Context, providerConfig *types.ProviderConfig, sourceProvider, sourceType string, state *types.ResourceState, targetType string) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	providerKey, err := providerConfig.CacheKey()
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
+	}
+
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
+
+	// Get target resource schema
+	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, targetType)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
+	}
+
+	// Convert opentofu-providers schema to proper cty.Type
+	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
+
+	// The provider receives the raw JSON state, exactly as OpenTofu would read it from a state file
+	rawState, err := json.Marshal(state.State)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	// The provider client does not implement moving state, it goes through the raw client
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	moved, moveDiags, err := client.moveResourceState(ctx, moveRequest{
+		sourceProviderAddress: sourceProviderHost + "/" + sourceProvider,
+		sourceType:            sourceType,
+		sourceSchemaVersion:   state.SchemaVersion,
+		sourceStateJSON:       rawState,
+		sourcePrivate:         state.Private,
+		targetType:            targetType,
+	})
+	diags = append(diags, moveDiags...)
+	if err != nil {
+		return nil, diags, fmt.Errorf("move state failed: %w", err)
+	}
+
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("move state failed: %w", err)
+	}
+
+	movedStateCty, err := moved.state.AsCtyValue(resourceTypeCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to decode moved state: %w", err)
+	}
+
+	if movedStateCty.IsNull() {
+		return nil, diags, fmt.Errorf("provider %s does not support moving %s to %s", providerConfig.Name, sourceType, targetType)
+	}
+
+	movedStateMap, err := a.converter.CtyValueToMap(movedStateCty)
+	if err != nil {
+		return nil, diags, fmt.Errorf("failed to convert moved state to map: %w", err)
+	}
+
+	return &types.ResourceState{
+		State:         movedStateMap,
+		Private:       moved.private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, nil
 }
 
 // GetProviderVersion returns the version of the provider - not implemented
@@ -813,76 +1465,88 @@ func (a *OpenTofuAdapter) GetProviderVersion(ctx context.Context, providerName s
 	return "", fmt.Errorf("GetProviderVersion not implemented")
 }
 
-// UpdateResource updates an existing resource
-func (a *OpenTofuAdapter) UpdateResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState, newConfig map[string]any) (map[string]any, error) {
-	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
-		return nil, err
+// UpdateResource updates an existing resource in place.
+// Returns a *types.ReplaceRequiredError without changing anything when the provider requires replacing the resource.
+func (a *OpenTofuAdapter) UpdateResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState, newConfig map[string]any) (*types.ResourceState, types.Diagnostics, error) {
+	// Ensure provider is loaded, configuring it may report diagnostics already
+	diags, err := a.LoadProvider(ctx, providerConfig)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	cacheKey, err := providerConfig.FullName()
+	providerKey, err := providerConfig.CacheKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
+		return nil, diags, fmt.Errorf("failed to get provider cache key: %w", err)
 	}
 
-	provider := a.providers[cacheKey]
+	provider, release, err := a.acquireProvider(ctx, providerConfig, providerKey)
+	if err != nil {
+		return nil, diags, err
+	}
+	defer release()
 
 	// Get resource schema
 	opentofuSchema, err := a.getOpentofuResourceSchema(ctx, providerConfig, resourceType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get opentofu schema: %w", err)
+		return nil, diags, fmt.Errorf("failed to get opentofu schema: %w", err)
 	}
 
 	// Convert opentofu-providers schema to proper cty.Type
 	resourceTypeCty := a.schemaConverter.opentofuSchemaToObjectType(opentofuSchema)
 
 	// Convert current state to cty.Value
-	currentStateCty, err := a.converter.MapToCtyValue(currentState, resourceTypeCty)
+	currentStateCty, err := a.converter.MapToCtyValue(currentState.State, resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert current state: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert current state: %w", err)
 	}
 	priorState := providerschema.NewDynamicValue(currentStateCty, resourceTypeCty)
 
-	// Merge currentState with newConfig to preserve fields not present in newConfig, otherwise it would potentially crash provider.
-	// Merge (i.e. replace) only at top level, otherwise it would be impossible to remove items from maps, e.g. remove a tag from AWS resource.
-	// If top level object had nested items, LLM will provide them as well
-	mergedConfig := map[string]any{}
-	for k, v := range currentState {
-		mergedConfig[k] = v
-
-		if _, ok := newConfig[k]; ok {
-			mergedConfig[k] = newConfig[k]
-		}
-	}
-
 	// Convert merged config to cty.Value
-	configCty, err := a.converter.MapToCtyValue(mergedConfig, resourceTypeCty)
+	configCty, err := a.configToCtyValue(opentofuSchema, mergeConfig(currentState.State, newConfig))
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert config: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert config: %w", err)
 	}
 	configDV := providerschema.NewDynamicValue(configCty, resourceTypeCty)
 
 	// Plan the update
 	planReq := &providerops.PlanManagedResourceChangeRequest{
-		ResourceType:     resourceType,
-		PriorState:       priorState,
-		Config:           configDV,
-		ProposedNewState: configDV, // Use config as proposed state for simplicity
+		ResourceType:          resourceType,
+		PriorState:            priorState,
+		Config:                configDV,
+		ProposedNewState:      configDV, // Use config as proposed state for simplicity
+		PriorProviderInternal: currentState.Private,
+	}
+
+	// The provider client does not expose the attributes whose change requires replacing the
+	// resource, the update is planned through the raw client
+	client, err := newRawClient(provider)
+	if err != nil {
+		return nil, diags, err
 	}
 
-	planResp, err := provider.PlanManagedResourceChange(ctx, planReq)
+	planned, planDiags, err := client.planResourceChange(ctx, planReq)
+	diags = append(diags, planDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("plan update failed: %w", err)
+		return nil, diags, fmt.Errorf("plan update failed: %w", err)
 	}
 
-	if planResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("plan update failed: %s", a.formatDiagnostics(planResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("plan update failed: %w", err)
 	}
 
-	// Convert planned state from DynamicValueOut to DynamicValueIn
-	plannedStateCty, err := planResp.PlannedNewState().AsCtyValue(resourceTypeCty)
+	// The change cannot be applied in place, let the caller decide how to replace the resource
+	if len(planned.requiresReplace) > 0 {
+		attributes := make([]string, 0, len(planned.requiresReplace))
+		for _, path := range planned.requiresReplace {
+			attributes = append(attributes, formatPath(path))
+		}
+		return nil, diags, &types.ReplaceRequiredError{Attributes: attributes}
+	}
+
+	// Convert planned state to DynamicValueIn
+	plannedStateCty, err := planned.plannedState.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode planned state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode planned state: %w", err)
 	}
 	plannedStateDV := providerschema.NewDynamicValue(plannedStateCty, resourceTypeCty)
 
@@ -892,36 +1556,118 @@ func (a *OpenTofuAdapter) UpdateResource(ctx context.Context, providerConfig *ty
 		PriorState:              priorState,
 		Config:                  configDV,
 		PlannedNewState:         plannedStateDV,
-		PlannedProviderInternal: planResp.PlannedProviderInternal(),
+		PlannedProviderInternal: planned.plannedPrivate,
 	}
 
-	applyResp, err := provider.ApplyManagedResourceChange(ctx, applyReq)
+	applied, applyDiags, err := a.applyChange(ctx, provider, applyReq, resourceTypeCty, opentofuSchema.SchemaVersion())
+	diags = append(diags, applyDiags...)
 	if err != nil {
-		return nil, fmt.Errorf("apply update failed: %w", err)
+		return nil, diags, fmt.Errorf("apply update failed: %w", err)
 	}
 
-	if applyResp.Diagnostics().HasErrors() {
-		return nil, fmt.Errorf("apply update failed: %s", a.formatDiagnostics(applyResp.Diagnostics()))
+	if err := checkDiagnostics(diags); err != nil {
+		return nil, diags, fmt.Errorf("apply update failed: %w", err)
 	}
 
 	// Convert final state back to map
-	finalStateCty, err := applyResp.PlannedNewState().AsCtyValue(resourceTypeCty)
+	finalStateCty, err := applied.newState.AsCtyValue(resourceTypeCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode final state: %w", err)
+		return nil, diags, fmt.Errorf("failed to decode final state: %w", err)
 	}
 
 	finalStateMap, err := a.converter.CtyValueToMap(finalStateCty)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert final state to map: %w", err)
+		return nil, diags, fmt.Errorf("failed to convert final state to map: %w", err)
 	}
 
-	return finalStateMap, nil
+	return &types.ResourceState{
+		State:         finalStateMap,
+		Private:       applied.private,
+		SchemaVersion: opentofuSchema.SchemaVersion(),
+	}, diags, nil
+}
+
+// mergeConfig merges newConfig over the current state to preserve fields not present in newConfig, otherwise it would potentially crash provider.
+// Merge (i.e. replace) only at top level, otherwise it would be impossible to remove items from maps, e.g. remove a tag from AWS resource.
+// If top level object had nested items, LLM will provide them as well
+func mergeConfig(currentState, newConfig map[string]any) map[string]any {
+	mergedConfig := map[string]any{}
+	for k, v := range currentState {
+		mergedConfig[k] = v
+
+		if _, ok := newConfig[k]; ok {
+			mergedConfig[k] = newConfig[k]
+		}
+	}
+
+	return mergedConfig
+}
+
+// withoutComputed returns a copy of a state without the attributes that are computed only, in nested blocks
+// and nested attributes too. Optional computed attributes are kept, they may have been configured.
+func withoutComputed(attrs map[string]providerschema.Attribute, blocks map[string]providerschema.NestedBlockType, state map[string]any) map[string]any {
+	result := make(map[string]any, len(state))
+	for name, value := range state {
+		if attr, ok := attrs[name]; ok {
+			if attr.Usage() == providerschema.AttributeComputed {
+				continue
+			}
+			if nestedType := attr.NestedType(); nestedType != nil {
+				nestedAttrs := maps.Collect(nestedType.Attributes())
+				value = mapNestedObjects(nestedType.Nesting(), value, func(object map[string]any) map[string]any {
+					return withoutComputed(nestedAttrs, nil, object)
+				})
+			}
+		} else if block, ok := blocks[name]; ok {
+			nestedAttrs, nestedBlocks := maps.Collect(block.Attributes()), maps.Collect(block.NestedBlockTypes())
+			value = mapNestedObjects(block.Nesting(), value, func(object map[string]any) map[string]any {
+				return withoutComputed(nestedAttrs, nestedBlocks, object)
+			})
+		}
+		result[name] = value
+	}
+
+	return result
+}
+
+// mapNestedObjects applies fn to each object of a nested block or nested attribute value
+func mapNestedObjects(nesting providerschema.NestingMode, value any, fn func(map[string]any) map[string]any) any {
+	switch nesting {
+	case providerschema.NestingSingle, providerschema.NestingGroup:
+		if object, ok := value.(map[string]any); ok {
+			return fn(object)
+		}
+	case providerschema.NestingList, providerschema.NestingSet:
+		if items, ok := value.([]any); ok {
+			mapped := make([]any, len(items))
+			for i, item := range items {
+				mapped[i] = item
+				if object, ok := item.(map[string]any); ok {
+					mapped[i] = fn(object)
+				}
+			}
+			return mapped
+		}
+	case providerschema.NestingMap:
+		if items, ok := value.(map[string]any); ok {
+			mapped := make(map[string]any, len(items))
+			for key, item := range items {
+				mapped[key] = item
+				if object, ok := item.(map[string]any); ok {
+					mapped[key] = fn(object)
+				}
+			}
+			return mapped
+		}
+	}
+
+	return value
 }
 
 // ListResources lists all available resource types for a provider
 func (a *OpenTofuAdapter) ListResources(ctx context.Context, providerConfig *types.ProviderConfig) ([]string, error) {
 	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -930,7 +1676,7 @@ func (a *OpenTofuAdapter) ListResources(ctx context.Context, providerConfig *typ
 		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	schema, exists := a.schemas[cacheKey]
+	schema, exists := a.providerSchema(cacheKey)
 	if !exists {
 		return nil, fmt.Errorf("provider schema not found for %s", providerConfig.Name)
 	}
@@ -946,7 +1692,7 @@ func (a *OpenTofuAdapter) ListResources(ctx context.Context, providerConfig *typ
 // DescribeResource returns detailed information about a resource type
 func (a *OpenTofuAdapter) DescribeResource(ctx context.Context, providerConfig *types.ProviderConfig, resourceType string) (*types.TypeDescription, error) {
 	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -955,7 +1701,7 @@ func (a *OpenTofuAdapter) DescribeResource(ctx context.Context, providerConfig *
 		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	schema, exists := a.schemas[cacheKey]
+	schema, exists := a.providerSchema(cacheKey)
 	if !exists {
 		return nil, fmt.Errorf("provider schema not found for %s", providerConfig.Name)
 	}
@@ -971,7 +1717,7 @@ func (a *OpenTofuAdapter) DescribeResource(ctx context.Context, providerConfig *
 // DescribeDataSource returns detailed information about a data source type
 func (a *OpenTofuAdapter) DescribeDataSource(ctx context.Context, providerConfig *types.ProviderConfig, dataSourceType string) (*types.TypeDescription, error) {
 	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -980,7 +1726,7 @@ func (a *OpenTofuAdapter) DescribeDataSource(ctx context.Context, providerConfig
 		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	schema, exists := a.schemas[cacheKey]
+	schema, exists := a.providerSchema(cacheKey)
 	if !exists {
 		return nil, fmt.Errorf("provider schema not found for %s", providerConfig.Name)
 	}
@@ -996,7 +1742,7 @@ func (a *OpenTofuAdapter) DescribeDataSource(ctx context.Context, providerConfig
 // ListDataSources lists all available data source types for a provider
 func (a *OpenTofuAdapter) ListDataSources(ctx context.Context, providerConfig *types.ProviderConfig) ([]string, error) {
 	// Ensure provider is loaded
-	if err := a.LoadProvider(ctx, providerConfig); err != nil {
+	if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
 		return nil, err
 	}
 
@@ -1005,7 +1751,7 @@ func (a *OpenTofuAdapter) ListDataSources(ctx context.Context, providerConfig *t
 		return nil, fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	schema, exists := a.schemas[cacheKey]
+	schema, exists := a.providerSchema(cacheKey)
 	if !exists {
 		return nil, fmt.Errorf("provider schema not found for %s", providerConfig.Name)
 	}
@@ -1019,16 +1765,33 @@ func (a *OpenTofuAdapter) ListDataSources(ctx context.Context, providerConfig *t
 }
 
 func (a *OpenTofuAdapter) Cleanup(ctx context.Context) {
-	// Close all provider connections
-	for _, provider := range a.providers {
-		provider.Close()
-	}
+	a.closeDone.Do(func() { close(a.done) })
+
+	// Close ephemeral resources while their plugins are still running
+	a.closeEphemerals(ctx)
 
 	// Clear caches
-	a.providers = make(map[string]tofuprovider.GRPCPluginProvider)
+	a.mu.Lock()
+	providers := a.providers
+	a.providers = make(map[string]*runningPlugin)
 	a.schemas = make(map[string]*types.ProviderSchema)
 	a.rawSchemas = make(map[string]providerops.GetProviderSchemaResponse)
 	a.binaries = make(map[string]string)
+	a.mu.Unlock()
+
+	// Close all provider connections. Plugins still applying changes are asked to stop first, they are
+	// closed once their calls have returned the partial state to save.
+	for _, provider := range providers {
+		a.mu.RLock()
+		busy := provider.inUse > 0
+		a.mu.RUnlock()
+
+		if busy {
+			provider.stop(ctx)
+			continue
+		}
+		provider.Close()
+	}
 }
 
 // Helper methods
@@ -1056,23 +1819,53 @@ func (a *OpenTofuAdapter) findProviderBinary(dir string) (string, error) {
 }
 
 func (a *OpenTofuAdapter) downloadProvider(ctx context.Context, providerConfig *types.ProviderConfig) (string, error) {
-	// Use the existing registry logic to download the provider
-	downloadInfo, err := a.registry.GetProviderDownload(ctx, *providerConfig)
+	versionedName, err := providerConfig.FullName()
 	if err != nil {
-		return "", fmt.Errorf("failed to get provider download info: %w", err)
+		return "", fmt.Errorf("failed to get versioned provider name: %w", err)
 	}
 
-	// Download and extract provider
-	binaryPath, err := a.downloadAndExtractProvider(ctx, providerConfig, downloadInfo.DownloadURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to download provider: %w", err)
-	}
+	// Concurrent loads of the same version, e.g. with different configurations, share one download
+	return a.downloads.do(ctx, versionedName, func() (string, error) {
+		// Like a load, the shared download does not end with the context of the first caller
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
 
-	return binaryPath, nil
+		protocol, err := a.negotiateProviderProtocol(ctx, providerConfig)
+		if err != nil {
+			return "", err
+		}
+		if protocol > 0 {
+			a.mu.Lock()
+			a.protocols[versionedName] = protocol
+			a.mu.Unlock()
+		}
+
+		// Use the existing registry logic to download the provider
+		downloadInfo, err := a.registry.GetProviderDownload(ctx, *providerConfig)
+		if err != nil {
+			return "", fmt.Errorf("failed to get provider download info: %w", err)
+		}
+
+		// Unpacked mirror packages are used in place
+		if downloadInfo.BinaryPath != "" {
+			log.Printf("Using provider %s from %s: %s", versionedName, downloadInfo.Mirror, downloadInfo.BinaryPath)
+			return downloadInfo.BinaryPath, nil
+		}
+
+		// Download and extract provider
+		binaryPath, err := a.downloadAndExtractProvider(ctx, providerConfig, downloadInfo)
+		if err != nil {
+			return "", fmt.Errorf("failed to download provider: %w", err)
+		}
+
+		return binaryPath, nil
+	})
 }
 
-// downloadAndExtractProvider downloads and extracts a provider binary
-func (a *OpenTofuAdapter) downloadAndExtractProvider(ctx context.Context, providerConfig *types.ProviderConfig, downloadURL string) (string, error) {
+// downloadAndExtractProvider downloads, verifies and extracts a provider binary. The zip must match
+// the checksums signed by one of the registry's signing keys, or the hashes published by the mirror it
+// comes from; any mismatch fails the download.
+func (a *OpenTofuAdapter) downloadAndExtractProvider(ctx context.Context, providerConfig *types.ProviderConfig, downloadInfo *types.DownloadInfo) (string, error) {
 	// Create provider directory using name@version as key
 	versionedName, err := providerConfig.FullName()
 	if err != nil {
@@ -1084,28 +1877,53 @@ func (a *OpenTofuAdapter) downloadAndExtractProvider(ctx context.Context, provid
 		return "", fmt.Errorf("failed to create provider directory: %w", err)
 	}
 
-	// Check if binary already exists
-	binaryPath, err := a.findProviderBinary(providerDir)
-	if err == nil {
-		// Binary exists, check if it's executable
-		if info, err := os.Stat(binaryPath); err == nil && info.Mode()&0111 != 0 {
-			return binaryPath, nil
+	// Check if a verified binary already exists
+	binaryPath, err := a.cachedProviderBinary(providerDir)
+	if err != nil {
+		return "", err
+	}
+	if binaryPath != "" {
+		return binaryPath, nil
+	}
+
+	// Verify the signed checksums before downloading anything large, mirrors publish no signatures
+	var shasum, keyID string
+	if downloadInfo.Mirror == "" {
+		shasum, keyID, err = a.verifiedShasum(ctx, downloadInfo)
+		if err != nil {
+			return "", fmt.Errorf("failed to verify provider %s: %w", versionedName, err)
 		}
 	}
 
 	// Download zip file
 	zipPath := filepath.Join(providerDir, "provider.zip")
-	if err := a.downloadFile(ctx, downloadURL, zipPath); err != nil {
+	if err := a.downloadFile(ctx, downloadInfo.DownloadURL, zipPath); err != nil {
 		return "", fmt.Errorf("failed to download provider: %w", err)
 	}
 
+	zipShasum, err := fileSHA256(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash provider zip: %w", err)
+	}
+	if downloadInfo.Mirror != "" {
+		if err := verifyMirrorPackage(zipPath, zipShasum, downloadInfo.Hashes); err != nil {
+			os.Remove(zipPath)
+			return "", fmt.Errorf("failed to verify provider %s from %s: %w", versionedName, downloadInfo.Mirror, err)
+		}
+		shasum = zipShasum
+	} else if zipShasum != shasum {
+		os.Remove(zipPath)
+		return "", fmt.Errorf("checksum mismatch for provider %s: downloaded %s has SHA-256 %s, expected %s", versionedName, downloadInfo.Filename, zipShasum, shasum)
+	}
+
 	// Extract zip file
-	if err := a.extractZip(zipPath, providerDir); err != nil {
+	extractDir := filepath.Join(providerDir, extractedDir)
+	if err := a.extractZip(zipPath, extractDir, maxExtractedSize); err != nil {
 		return "", fmt.Errorf("failed to extract provider: %w", err)
 	}
 
 	// Find and make binary executable
-	binaryPath, err = a.findProviderBinary(providerDir)
+	binaryPath, err = a.findProviderBinary(extractDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to find provider binary: %w", err)
 	}
@@ -1114,26 +1932,52 @@ func (a *OpenTofuAdapter) downloadAndExtractProvider(ctx context.Context, provid
 		return "", fmt.Errorf("failed to make binary executable: %w", err)
 	}
 
+	// Record the verified hashes so the binary is only reused while it is unchanged
+	binaryShasum, err := fileSHA256(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash provider binary: %w", err)
+	}
+
+	verification := providerVerification{ZipSHA256: shasum, BinarySHA256: binaryShasum, SigningKeyID: keyID}
+	if err := writeVerification(providerDir, verification); err != nil {
+		return "", fmt.Errorf("failed to record provider verification: %w", err)
+	}
+
+	if downloadInfo.Mirror != "" {
+		log.Printf("Installed provider %s from %s: SHA-256 %s", versionedName, downloadInfo.Mirror, shasum)
+	} else {
+		log.Printf("Verified provider %s: SHA-256 %s signed by key %s", versionedName, shasum, keyID)
+	}
+
 	return binaryPath, nil
 }
 
-func (a *OpenTofuAdapter) formatDiagnostics(diags providerops.Diagnostics) string {
-	if !diags.HasErrors() {
-		return "no errors"
+// cachedProviderBinary returns the binary extracted to a provider directory by an earlier download,
+// after checking it still matches the hash recorded when it was verified. It returns an empty path if
+// there is no binary, or if it was extracted before downloads were verified and must be downloaded again.
+func (a *OpenTofuAdapter) cachedProviderBinary(providerDir string) (string, error) {
+	binaryPath, err := a.findProviderBinary(filepath.Join(providerDir, extractedDir))
+	if err != nil {
+		return "", nil
 	}
 
-	var messages []string
-	for diag := range diags.All() {
-		if diag.Severity() == providerops.DiagnosticError {
-			msg := diag.Summary()
-			if detail := diag.Detail(); detail != "" {
-				msg += ": " + detail
-			}
-			messages = append(messages, msg)
-		}
+	verification, err := readVerification(providerDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read provider verification: %w", err)
+	}
+	if verification == nil {
+		return "", nil
+	}
+
+	shasum, err := fileSHA256(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash cached provider binary: %w", err)
+	}
+	if shasum != verification.BinarySHA256 {
+		return "", fmt.Errorf("cached provider binary %s has SHA-256 %s but %s was verified, remove %s to download it again", binaryPath, shasum, verification.BinarySHA256, providerDir)
 	}
 
-	return strings.Join(messages, "; ")
+	return binaryPath, nil
 }
 
 // downloadFile downloads a file from URL to local path
@@ -1153,40 +1997,3 @@ func (a *OpenTofuAdapter) downloadFile(ctx context.Context, url, path string) er
 	_, err = io.Copy(file, resp)
 	return err
 }
-
-// extractZip extracts a zip file to a destination directory
-func (a *OpenTofuAdapter) extractZip(src, dest string) error {
-	r, err := zip.OpenReader(src)
-	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-
-		path := filepath.Join(dest, f.Name)
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(path, f.FileInfo().Mode())
-			rc.Close()
-			continue
-		}
-
-		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.FileInfo().Mode())
-		if err != nil {
-			rc.Close()
-			return err
-		}
-
-		_, err = io.Copy(file, rc)
-		file.Close()
-		rc.Close()
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
@@ -0,0 +1,73 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// IsDevOverride reports whether a provider is loaded from a locally built binary instead of a released one
+func (a *OpenTofuAdapter) IsDevOverride(providerName string) bool {
+	_, ok := a.devOverrides[providerName]
+	return ok
+}
+
+// providerBinary returns the binary of a provider: the locally built one of a development override, or
+// else the downloaded release
+func (a *OpenTofuAdapter) providerBinary(ctx context.Context, providerConfig *types.ProviderConfig) (string, error) {
+	override, ok := a.devOverrides[providerConfig.Name]
+	if !ok {
+		return a.downloadProvider(ctx, providerConfig)
+	}
+
+	binary, err := devOverrideBinary(providerConfig.Name, override)
+	if err != nil {
+		return "", fmt.Errorf("invalid development override: %w", err)
+	}
+
+	log.Printf("Using development override of provider %s: %s (requested version %s is ignored)", providerConfig.Name, binary, providerConfig.Version)
+
+	return binary, nil
+}
+
+// devOverrideBinary finds the binary of a development override, which is either the binary itself or
+// the directory it was built into
+func devOverrideBinary(providerName, override string) (string, error) {
+	info, err := os.Stat(override)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return override, nil
+	}
+
+	_, name, _ := strings.Cut(providerName, "/")
+	entries, err := os.ReadDir(override)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && isProviderBinary(entry.Name(), name) {
+			return filepath.Join(override, entry.Name()), nil
+		}
+	}
+
+	return "", fmt.Errorf("no terraform-provider-%s binary in %s", name, override)
+}
+
+// isProviderBinary reports whether a file is the binary of a provider type, named like
+// terraform-provider-aws or with a version suffix like terraform-provider-aws_v5.0.0_x5. Other providers
+// whose type starts with the same name, like terraform-provider-awscc, don't match.
+func isProviderBinary(fileName, providerType string) bool {
+	rest, ok := strings.CutPrefix(fileName, "terraform-provider-"+providerType)
+	return ok && (rest == "" || strings.HasPrefix(rest, "_"))
+}
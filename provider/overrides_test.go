@@ -0,0 +1,61 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestProviderBinaryDevOverride(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "terraform-provider-internal")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0644))
+	require.NoError(t, os.WriteFile(binary, []byte("provider"), 0755))
+
+	// No registry: overridden providers must never be downloaded
+	adapter := NewOpenTofuAdapter(t.TempDir(), nil, types.PluginPolicy{}, types.DevOverrides{
+		"acme/internal": dir,
+		"acme/binary":   binary,
+		"acme/missing":  filepath.Join(dir, "missing"),
+		"acme/empty":    t.TempDir(),
+	}).(*OpenTofuAdapter)
+	ctx := context.Background()
+
+	assert.True(t, adapter.IsDevOverride("acme/internal"))
+	assert.False(t, adapter.IsDevOverride("hashicorp/random"))
+
+	got, err := adapter.providerBinary(ctx, &types.ProviderConfig{Name: "acme/internal", Version: "0.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, binary, got)
+
+	got, err = adapter.providerBinary(ctx, &types.ProviderConfig{Name: "acme/binary", Version: "9.9.9"})
+	require.NoError(t, err)
+	assert.Equal(t, binary, got)
+
+	_, err = adapter.providerBinary(ctx, &types.ProviderConfig{Name: "acme/missing", Version: "0.0.1"})
+	assert.ErrorContains(t, err, "invalid development override")
+
+	_, err = adapter.providerBinary(ctx, &types.ProviderConfig{Name: "acme/empty", Version: "0.0.1"})
+	assert.ErrorContains(t, err, "no terraform-provider-empty binary in")
+}
+
+func TestIsProviderBinary(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isProviderBinary("terraform-provider-aws", "aws"))
+	assert.True(t, isProviderBinary("terraform-provider-aws_v5.0.0_x5", "aws"))
+	assert.False(t, isProviderBinary("terraform-provider-awscc", "aws"))
+	assert.False(t, isProviderBinary("terraform-provider-awscc_v1.0.0", "aws"))
+	assert.False(t, isProviderBinary("terraform-provider-aws.md", "aws"))
+}
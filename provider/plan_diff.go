@@ -0,0 +1,179 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// sensitiveValue replaces values of sensitive attributes in plan output
+const sensitiveValue = "(sensitive value)"
+
+// sensitiveValues masks the sensitive values of attributes, by attribute name. Attributes that are not
+// listed hold no sensitive values.
+type sensitiveValues map[string]func(value any) any
+
+// sensitiveAttributes returns the masks of the attributes and nested blocks that are sensitive or hold
+// sensitive values. Values nested in an attribute or block are masked by their path, the rest is kept.
+func sensitiveAttributes(attrs map[string]providerschema.Attribute, blocks map[string]providerschema.NestedBlockType) sensitiveValues {
+	masks := sensitiveValues{}
+
+	for name, attr := range attrs {
+		if attr.IsSensitive() {
+			masks[name] = maskValue
+			continue
+		}
+		if nestedType := attr.NestedType(); nestedType != nil {
+			if nested := sensitiveAttributes(maps.Collect(nestedType.Attributes()), nil); len(nested) > 0 {
+				nesting := nestedType.Nesting()
+				masks[name] = func(value any) any { return mapNestedObjects(nesting, value, nested.mask) }
+			}
+		}
+	}
+
+	for name, block := range blocks {
+		if nested := sensitiveAttributes(maps.Collect(block.Attributes()), maps.Collect(block.NestedBlockTypes())); len(nested) > 0 {
+			nesting := block.Nesting()
+			masks[name] = func(value any) any { return mapNestedObjects(nesting, value, nested.mask) }
+		}
+	}
+
+	return masks
+}
+
+// mask returns a copy of an object with its sensitive values masked
+func (s sensitiveValues) mask(object map[string]any) map[string]any {
+	masked := maps.Clone(object)
+	for name, mask := range s {
+		if value := masked[name]; value != nil {
+			masked[name] = mask(value)
+		}
+	}
+	return masked
+}
+
+// maskValue masks a whole sensitive value
+func maskValue(any) any {
+	return sensitiveValue
+}
+
+// diffPlannedState computes the top-level attribute changes between the prior and the planned state.
+// A null prior state means the resource is about to be created.
+func (c *CtyConverter) diffPlannedState(prior, planned cty.Value, requiresReplace []cty.Path, sensitive sensitiveValues) ([]types.AttributeChange, error) {
+	replaceAttributes := map[string]bool{}
+	for _, path := range requiresReplace {
+		if len(path) == 0 {
+			continue
+		}
+		if step, ok := path[0].(cty.GetAttrStep); ok {
+			replaceAttributes[step.Name] = true
+		}
+	}
+
+	changes := []types.AttributeChange{}
+	for _, name := range slices.Sorted(maps.Keys(planned.Type().AttributeTypes())) {
+		before := cty.NullVal(planned.Type().AttributeType(name))
+		if !prior.IsNull() && prior.Type().HasAttribute(name) {
+			before = prior.GetAttr(name)
+		}
+		after := planned.GetAttr(name)
+		mask, hasSensitive := sensitive[name]
+
+		change := types.AttributeChange{
+			Attribute:       name,
+			Sensitive:       hasSensitive,
+			RequiresReplace: replaceAttributes[name],
+		}
+
+		switch {
+		case after.IsWhollyKnown() && before.RawEquals(after):
+			continue
+		case before.IsNull():
+			change.Action = "add"
+		case after.IsKnown() && after.IsNull():
+			change.Action = "remove"
+		default:
+			change.Action = "update"
+		}
+
+		change.Computed = !after.IsWhollyKnown()
+
+		var err error
+		if !before.IsNull() {
+			if change.Before, err = c.ctyValueToAny(before); err != nil {
+				return nil, fmt.Errorf("failed to convert prior value of %s: %w", name, err)
+			}
+		}
+		if after.IsKnown() && !after.IsNull() {
+			if change.After, err = c.ctyValueToAny(after); err != nil {
+				return nil, fmt.Errorf("failed to convert planned value of %s: %w", name, err)
+			}
+		}
+
+		if change.Sensitive {
+			if change.Before != nil {
+				change.Before = mask(change.Before)
+			}
+			if change.After != nil {
+				change.After = mask(change.After)
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// planAction summarizes attribute changes into the action the provider will take
+func planAction(prior cty.Value, changes []types.AttributeChange) string {
+	if prior.IsNull() {
+		return "create"
+	}
+	if len(changes) == 0 {
+		return "no-op"
+	}
+	for _, change := range changes {
+		if change.RequiresReplace {
+			return "replace"
+		}
+	}
+	return "update"
+}
+
+// formatPath renders a cty attribute path in the dotted form used by OpenTofu, e.g. tags["Name"] or rule[0].port
+func formatPath(path cty.Path) string {
+	var sb strings.Builder
+
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(s.Name)
+		case cty.IndexStep:
+			switch {
+			case !s.Key.IsKnown() || s.Key.IsNull():
+				sb.WriteString("[*]")
+			case s.Key.Type() == cty.String:
+				fmt.Fprintf(&sb, "[%q]", s.Key.AsString())
+			case s.Key.Type() == cty.Number:
+				fmt.Fprintf(&sb, "[%s]", s.Key.AsBigFloat().Text('f', -1))
+			default:
+				sb.WriteString("[?]")
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestDiffPlannedState(t *testing.T) {
+	converter := &CtyConverter{}
+
+	objectType := cty.Object(map[string]cty.Type{
+		"id":       cty.String,
+		"name":     cty.String,
+		"password": cty.String,
+		"tags":     cty.Map(cty.String),
+		"zone":     cty.String,
+	})
+
+	t.Run("create", func(t *testing.T) {
+		planned := cty.ObjectVal(map[string]cty.Value{
+			"id":       cty.UnknownVal(cty.String),
+			"name":     cty.StringVal("web"),
+			"password": cty.StringVal("secret"),
+			"tags":     cty.NullVal(cty.Map(cty.String)),
+			"zone":     cty.StringVal("a"),
+		})
+
+		changes, err := converter.diffPlannedState(cty.NullVal(objectType), planned, nil, sensitiveValues{"password": maskValue})
+		require.NoError(t, err)
+
+		assert.Equal(t, []types.AttributeChange{
+			{Attribute: "id", Action: "add", Computed: true},
+			{Attribute: "name", Action: "add", After: "web"},
+			{Attribute: "password", Action: "add", After: sensitiveValue, Sensitive: true},
+			{Attribute: "zone", Action: "add", After: "a"},
+		}, changes)
+		assert.Equal(t, "create", planAction(cty.NullVal(objectType), changes))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		prior := cty.ObjectVal(map[string]cty.Value{
+			"id":       cty.StringVal("i-123"),
+			"name":     cty.StringVal("web"),
+			"password": cty.StringVal("secret"),
+			"tags":     cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+			"zone":     cty.StringVal("a"),
+		})
+		planned := cty.ObjectVal(map[string]cty.Value{
+			"id":       cty.UnknownVal(cty.String),
+			"name":     cty.StringVal("web"),
+			"password": cty.StringVal("secret"),
+			"tags":     cty.NullVal(cty.Map(cty.String)),
+			"zone":     cty.StringVal("b"),
+		})
+
+		changes, err := converter.diffPlannedState(prior, planned, []cty.Path{cty.GetAttrPath("zone")}, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []types.AttributeChange{
+			{Attribute: "id", Action: "update", Before: "i-123", Computed: true},
+			{Attribute: "tags", Action: "remove", Before: map[string]any{"env": "dev"}},
+			{Attribute: "zone", Action: "update", Before: "a", After: "b", RequiresReplace: true},
+		}, changes)
+		assert.Equal(t, "replace", planAction(prior, changes))
+	})
+
+	t.Run("no-op", func(t *testing.T) {
+		prior := cty.ObjectVal(map[string]cty.Value{
+			"id":       cty.StringVal("i-123"),
+			"name":     cty.StringVal("web"),
+			"password": cty.NullVal(cty.String),
+			"tags":     cty.NullVal(cty.Map(cty.String)),
+			"zone":     cty.StringVal("a"),
+		})
+
+		changes, err := converter.diffPlannedState(prior, prior, nil, nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, changes)
+		assert.Equal(t, "no-op", planAction(prior, changes))
+	})
+}
+
+func TestSensitiveAttributes(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]providerschema.Attribute{
+		"name":     fakeAttribute{ty: cty.String},
+		"password": fakeAttribute{ty: cty.String, sensitive: true},
+		"users": fakeAttribute{nestedType: fakeObjectType{nesting: providerschema.NestingMap, attrs: map[string]providerschema.Attribute{
+			"role":  fakeAttribute{ty: cty.String},
+			"token": fakeAttribute{ty: cty.String, sensitive: true},
+		}}},
+	}
+	blocks := map[string]providerschema.NestedBlockType{
+		"connection": fakeBlock{nesting: providerschema.NestingList, blocks: map[string]providerschema.NestedBlockType{
+			"auth": fakeBlock{nesting: providerschema.NestingSingle, attrs: map[string]providerschema.Attribute{
+				"user":   fakeAttribute{ty: cty.String},
+				"secret": fakeAttribute{ty: cty.String, sensitive: true},
+			}},
+		}},
+		"logging": fakeBlock{nesting: providerschema.NestingSingle, attrs: map[string]providerschema.Attribute{
+			"target": fakeAttribute{ty: cty.String},
+		}},
+	}
+
+	sensitive := sensitiveAttributes(attrs, blocks)
+	assert.ElementsMatch(t, []string{"password", "users", "connection"}, slices.Collect(maps.Keys(sensitive)))
+
+	object := map[string]any{
+		"name":       "db",
+		"password":   "hunter2",
+		"users":      map[string]any{"admin": map[string]any{"role": "owner", "token": "t-1"}},
+		"connection": []any{map[string]any{"auth": map[string]any{"user": "root", "secret": "s-1"}}},
+		"logging":    map[string]any{"target": "audit"},
+	}
+
+	assert.Equal(t, map[string]any{
+		"name":       "db",
+		"password":   sensitiveValue,
+		"users":      map[string]any{"admin": map[string]any{"role": "owner", "token": sensitiveValue}},
+		"connection": []any{map[string]any{"auth": map[string]any{"user": "root", "secret": sensitiveValue}}},
+		"logging":    map[string]any{"target": "audit"},
+	}, sensitive.mask(object))
+	assert.Equal(t, "t-1", object["users"].(map[string]any)["admin"].(map[string]any)["token"], "the object is left unchanged")
+}
+
+func TestDiffPlannedStateNestedSensitive(t *testing.T) {
+	converter := &CtyConverter{}
+
+	sensitive := sensitiveAttributes(map[string]providerschema.Attribute{
+		"users": fakeAttribute{nestedType: fakeObjectType{nesting: providerschema.NestingList, attrs: map[string]providerschema.Attribute{
+			"name":  fakeAttribute{ty: cty.String},
+			"token": fakeAttribute{ty: cty.String, sensitive: true},
+		}}},
+	}, nil)
+
+	prior := cty.ObjectVal(map[string]cty.Value{
+		"users": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("a"), "token": cty.StringVal("t-1")})}),
+	})
+	planned := cty.ObjectVal(map[string]cty.Value{
+		"users": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("b"), "token": cty.StringVal("t-2")})}),
+	})
+
+	changes, err := converter.diffPlannedState(prior, planned, nil, sensitive)
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.AttributeChange{{
+		Attribute: "users",
+		Action:    "update",
+		Before:    []any{map[string]any{"name": "a", "token": sensitiveValue}},
+		After:     []any{map[string]any{"name": "b", "token": sensitiveValue}},
+		Sensitive: true,
+	}}, changes)
+}
+
+func TestFormatPath(t *testing.T) {
+	tests := []struct {
+		path     cty.Path
+		expected string
+	}{
+		{cty.GetAttrPath("name"), "name"},
+		{cty.GetAttrPath("tags").IndexString("Name"), `tags["Name"]`},
+		{cty.GetAttrPath("rule").IndexInt(0).GetAttr("port"), "rule[0].port"},
+		{cty.Path{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatPath(tt.path))
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentofu/provider-client/tofuprovider"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// runningPlugin is a started and configured provider plugin process. The adapter counts the calls using it
+// so that idle and least recently used plugins can be stopped without interrupting a call. RPCs failing
+// because the process is gone mark it crashed, and the next call starts and configures a new one.
+type runningPlugin struct {
+	tofuprovider.GRPCPluginProvider
+
+	key      string
+	inUse    int       // guarded by the adapter mu
+	lastUsed time.Time // guarded by the adapter mu
+	crashed  atomic.Bool
+	stopped  atomic.Bool // asked to abort its calls, retired like a crashed plugin
+	onCrash  func(*runningPlugin)
+}
+
+// observe checks an RPC error for signs of the plugin process being gone
+func (p *runningPlugin) observe(err error) error {
+	if err == nil || status.Code(err) != codes.Unavailable {
+		return err
+	}
+
+	if p.crashed.CompareAndSwap(false, true) {
+		log.Printf("Provider plugin %s stopped responding, it will be restarted on the next call", p.key)
+		p.onCrash(p)
+	}
+
+	return fmt.Errorf("provider plugin stopped responding and will be restarted on the next call: %w", err)
+}
+
+// stop asks the plugin to gracefully abort its calls, which return as soon as it is safe to. The plugin
+// behaves unpredictably afterwards, so it is retired like a crashed one and the next call starts a new one.
+func (p *runningPlugin) stop(ctx context.Context) {
+	if !p.stopped.CompareAndSwap(false, true) {
+		return
+	}
+
+	log.Printf("Stopping the calls of provider plugin %s", p.key)
+	if err := p.GracefulStop(ctx); err != nil {
+		log.Printf("Failed to stop the calls of provider plugin %s: %v", p.key, err)
+	}
+
+	if p.crashed.CompareAndSwap(false, true) {
+		p.onCrash(p)
+	}
+}
+
+func (p *runningPlugin) GetProviderSchema(ctx context.Context, req *providerops.GetProviderSchemaRequest) (providerops.GetProviderSchemaResponse, error) {
+	resp, err := p.GRPCPluginProvider.GetProviderSchema(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ValidateManagedResourceConfig(ctx context.Context, req *providerops.ValidateManagedResourceConfigRequest) (providerops.ValidateManagedResourceConfigResponse, error) {
+	resp, err := p.GRPCPluginProvider.ValidateManagedResourceConfig(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ValidateDataResourceConfig(ctx context.Context, req *providerops.ValidateDataResourceConfigRequest) (providerops.ValidateDataResourceConfigResponse, error) {
+	resp, err := p.GRPCPluginProvider.ValidateDataResourceConfig(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) PlanManagedResourceChange(ctx context.Context, req *providerops.PlanManagedResourceChangeRequest) (providerops.PlanManagedResourceChangeResponse, error) {
+	resp, err := p.GRPCPluginProvider.PlanManagedResourceChange(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ApplyManagedResourceChange(ctx context.Context, req *providerops.ApplyManagedResourceChangeRequest) (providerops.ApplyManagedResourceChangeResponse, error) {
+	resp, err := p.GRPCPluginProvider.ApplyManagedResourceChange(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ReadManagedResource(ctx context.Context, req *providerops.ReadManagedResourceRequest) (providerops.ReadManagedResourceResponse, error) {
+	resp, err := p.GRPCPluginProvider.ReadManagedResource(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ImportManagedResourceState(ctx context.Context, req *providerops.ImportManagedResourceStateRequest) (providerops.ImportManagedResourceStateResponse, error) {
+	resp, err := p.GRPCPluginProvider.ImportManagedResourceState(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) ReadDataResource(ctx context.Context, req *providerops.ReadDataResourceRequest) (providerops.ReadDataResourceResponse, error) {
+	resp, err := p.GRPCPluginProvider.ReadDataResource(ctx, req)
+	return resp, p.observe(err)
+}
+
+func (p *runningPlugin) CallFunction(ctx context.Context, req *providerops.CallFunctionRequest) (providerops.CallFunctionResponse, error) {
+	resp, err := p.GRPCPluginProvider.CallFunction(ctx, req)
+	return resp, p.observe(err)
+}
+
+// acquireProvider returns the running plugin for a provider configuration, loading it again if it was
+// stopped in the meantime. The plugin is not stopped before release is called.
+func (a *OpenTofuAdapter) acquireProvider(ctx context.Context, providerConfig *types.ProviderConfig, providerKey string) (tofuprovider.GRPCPluginProvider, func(), error) {
+	// Eviction must not stop the plugin between it being loaded and acquired
+	a.mu.Lock()
+	a.acquiring[providerKey]++
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		if a.acquiring[providerKey]--; a.acquiring[providerKey] == 0 {
+			delete(a.acquiring, providerKey)
+		}
+		a.mu.Unlock()
+	}()
+
+	for attempt := 0; ; attempt++ {
+		a.mu.Lock()
+		plugin := a.providers[providerKey]
+		if plugin != nil {
+			plugin.inUse++
+			plugin.lastUsed = time.Now()
+		}
+		a.mu.Unlock()
+
+		if plugin != nil {
+			return plugin, func() { a.releasePlugin(plugin) }, nil
+		}
+
+		if attempt > 0 {
+			return nil, nil, fmt.Errorf("provider %s was stopped while loading, retry", providerConfig.Name)
+		}
+		if _, err := a.LoadProvider(ctx, providerConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (a *OpenTofuAdapter) releasePlugin(plugin *runningPlugin) {
+	a.mu.Lock()
+	plugin.inUse--
+	plugin.lastUsed = time.Now()
+	closeCrashed := plugin.crashed.Load() && plugin.inUse == 0
+	overLimit := a.pluginPolicy.MaxRunning > 0 && len(a.providers) > a.pluginPolicy.MaxRunning
+	a.mu.Unlock()
+
+	if closeCrashed {
+		plugin.Close()
+	}
+	if overLimit {
+		a.evictPlugins(time.Now())
+	}
+}
+
+// forgetCrashed drops a crashed plugin from the cache, it is closed once no call uses it anymore
+func (a *OpenTofuAdapter) forgetCrashed(plugin *runningPlugin) {
+	a.mu.Lock()
+	if a.providers[plugin.key] == plugin {
+		delete(a.providers, plugin.key)
+	}
+	closeNow := plugin.inUse == 0
+	a.mu.Unlock()
+
+	if closeNow {
+		plugin.Close()
+	}
+}
+
+// evictPlugins stops plugins idle for longer than the idle timeout and, above the running limit,
+// the least recently used idle ones. Plugins that are in use or being acquired are never stopped.
+func (a *OpenTofuAdapter) evictPlugins(now time.Time) {
+	var evicted []*runningPlugin
+
+	a.mu.Lock()
+	if a.pluginPolicy.IdleTimeout > 0 {
+		for key, plugin := range a.providers {
+			if a.evictable(plugin) && now.Sub(plugin.lastUsed) >= a.pluginPolicy.IdleTimeout {
+				delete(a.providers, key)
+				evicted = append(evicted, plugin)
+			}
+		}
+	}
+
+	for a.pluginPolicy.MaxRunning > 0 && len(a.providers) > a.pluginPolicy.MaxRunning {
+		var oldest *runningPlugin
+		for _, plugin := range a.providers {
+			if a.evictable(plugin) && (oldest == nil || plugin.lastUsed.Before(oldest.lastUsed)) {
+				oldest = plugin
+			}
+		}
+		if oldest == nil {
+			// Every plugin is in use or being acquired, the limit is enforced again once calls finish
+			break
+		}
+		delete(a.providers, oldest.key)
+		evicted = append(evicted, oldest)
+	}
+	a.mu.Unlock()
+
+	for _, plugin := range evicted {
+		log.Printf("Stopping provider plugin %s", plugin.key)
+		plugin.Close()
+	}
+}
+
+// evictable reports whether a plugin can be stopped, must be called with mu held
+func (a *OpenTofuAdapter) evictable(plugin *runningPlugin) bool {
+	return plugin.inUse == 0 && a.acquiring[plugin.key] == 0
+}
+
+// evictIdlePlugins periodically stops idle plugins until done is closed
+func (a *OpenTofuAdapter) evictIdlePlugins(done <-chan struct{}) {
+	ticker := time.NewTicker(max(min(a.pluginPolicy.IdleTimeout/2, time.Minute), time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			a.evictPlugins(now)
+		}
+	}
+}
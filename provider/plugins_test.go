@@ -0,0 +1,155 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opentofu/provider-client/tofuprovider"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// fakePlugin counts how often it was closed and fails reads when its process is gone
+type fakePlugin struct {
+	tofuprovider.GRPCPluginProvider
+
+	closed atomic.Int32
+	gone   bool
+}
+
+func (p *fakePlugin) Close() error {
+	p.closed.Add(1)
+	return nil
+}
+
+func (p *fakePlugin) ReadDataResource(_ context.Context, _ *providerops.ReadDataResourceRequest) (providerops.ReadDataResourceResponse, error) {
+	if p.gone {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	return nil, nil
+}
+
+func newPluginTestAdapter(t *testing.T, policy types.PluginPolicy, plugins map[string]*fakePlugin, lastUsed map[string]time.Time) *OpenTofuAdapter {
+	adapter := NewOpenTofuAdapter(t.TempDir(), nil, policy, nil).(*OpenTofuAdapter)
+	t.Cleanup(func() { adapter.Cleanup(context.Background()) })
+
+	for key, plugin := range plugins {
+		adapter.providers[key] = &runningPlugin{
+			GRPCPluginProvider: plugin,
+			key:                key,
+			lastUsed:           lastUsed[key],
+			onCrash:            adapter.forgetCrashed,
+		}
+	}
+
+	return adapter
+}
+
+func TestEvictPlugins(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	t.Run("idle plugins are stopped unless in use", func(t *testing.T) {
+		t.Parallel()
+
+		idle, busy, recent := &fakePlugin{}, &fakePlugin{}, &fakePlugin{}
+		adapter := newPluginTestAdapter(t, types.PluginPolicy{IdleTimeout: time.Minute},
+			map[string]*fakePlugin{"idle": idle, "busy": busy, "recent": recent},
+			map[string]time.Time{"idle": now.Add(-time.Hour), "busy": now.Add(-time.Hour), "recent": now})
+
+		_, release, err := adapter.acquireProvider(context.Background(), &types.ProviderConfig{}, "busy")
+		require.NoError(t, err)
+		adapter.providers["busy"].lastUsed = now.Add(-time.Hour)
+
+		adapter.evictPlugins(now)
+
+		require.EqualValues(t, 1, idle.closed.Load())
+		require.EqualValues(t, 0, busy.closed.Load())
+		require.EqualValues(t, 0, recent.closed.Load())
+		require.ElementsMatch(t, []string{"busy", "recent"}, runningPluginKeys(adapter))
+
+		release()
+		adapter.evictPlugins(now.Add(2 * time.Minute))
+		require.EqualValues(t, 1, busy.closed.Load())
+	})
+
+	t.Run("least recently used plugins are stopped above the limit", func(t *testing.T) {
+		t.Parallel()
+
+		oldest, older, newest := &fakePlugin{}, &fakePlugin{}, &fakePlugin{}
+		adapter := newPluginTestAdapter(t, types.PluginPolicy{MaxRunning: 1},
+			map[string]*fakePlugin{"oldest": oldest, "older": older, "newest": newest},
+			map[string]time.Time{"oldest": now.Add(-time.Hour), "older": now.Add(-time.Minute), "newest": now})
+
+		adapter.evictPlugins(now)
+
+		require.EqualValues(t, 1, oldest.closed.Load())
+		require.EqualValues(t, 1, older.closed.Load())
+		require.EqualValues(t, 0, newest.closed.Load())
+		require.Equal(t, []string{"newest"}, runningPluginKeys(adapter))
+	})
+
+	t.Run("a plugin being acquired is not stopped above the limit", func(t *testing.T) {
+		t.Parallel()
+
+		busy, loaded := &fakePlugin{}, &fakePlugin{}
+		adapter := newPluginTestAdapter(t, types.PluginPolicy{MaxRunning: 1},
+			map[string]*fakePlugin{"busy": busy, "loaded": loaded},
+			map[string]time.Time{"busy": now, "loaded": now.Add(-time.Hour)})
+
+		_, release, err := adapter.acquireProvider(context.Background(), &types.ProviderConfig{}, "busy")
+		require.NoError(t, err)
+		defer release()
+
+		// The plugin was just loaded for a call that has not acquired it yet
+		adapter.mu.Lock()
+		adapter.acquiring["loaded"]++
+		adapter.mu.Unlock()
+
+		adapter.evictPlugins(now)
+
+		require.EqualValues(t, 0, loaded.closed.Load())
+		require.ElementsMatch(t, []string{"busy", "loaded"}, runningPluginKeys(adapter))
+	})
+}
+
+func TestCrashedPlugin(t *testing.T) {
+	t.Parallel()
+
+	plugin := &fakePlugin{gone: true}
+	adapter := newPluginTestAdapter(t, types.PluginPolicy{}, map[string]*fakePlugin{"random": plugin}, nil)
+
+	provider, release, err := adapter.acquireProvider(context.Background(), &types.ProviderConfig{}, "random")
+	require.NoError(t, err)
+
+	_, err = provider.ReadDataResource(context.Background(), &providerops.ReadDataResourceRequest{})
+	require.ErrorContains(t, err, "will be restarted on the next call")
+
+	// The crashed plugin is no longer handed out, but only closed once the call using it is done
+	require.Empty(t, runningPluginKeys(adapter))
+	require.EqualValues(t, 0, plugin.closed.Load())
+
+	release()
+	require.EqualValues(t, 1, plugin.closed.Load())
+}
+
+func runningPluginKeys(adapter *OpenTofuAdapter) []string {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+
+	var result []string
+	for key := range adapter.providers {
+		result = append(result, key)
+	}
+	return result
+}
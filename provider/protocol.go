@@ -0,0 +1,78 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// supportedProtocols are the plugin protocol major versions the provider client speaks, most preferred
+// first. Framework-based providers often only speak protocol 6, SDKv2 ones protocol 5.
+var supportedProtocols = []int{6, 5}
+
+// negotiateProtocol picks the plugin protocol to use with a provider version from the protocols the
+// registry lists for it, such as "5.0" or "6.0". Without any listed protocols, as from mirrors, the
+// plugin handshake decides and 0 is returned.
+func negotiateProtocol(protocols []string) (int, error) {
+	if len(protocols) == 0 {
+		return 0, nil
+	}
+
+	offered := make([]int, 0, len(protocols))
+	for _, protocol := range protocols {
+		major, _, _ := strings.Cut(protocol, ".")
+		if version, err := strconv.Atoi(major); err == nil {
+			offered = append(offered, version)
+		}
+	}
+
+	for _, version := range supportedProtocols {
+		if slices.Contains(offered, version) {
+			return version, nil
+		}
+	}
+
+	return 0, fmt.Errorf("the provider speaks plugin protocol %s, only protocols 5 and 6 are supported", strings.Join(protocols, ", "))
+}
+
+// negotiateProviderProtocol picks the plugin protocol of a provider version before downloading it, so a
+// provider that can't be used fails early. The registry being unable to tell is not an error: the plugin
+// handshake negotiates the protocol anyway.
+func (a *OpenTofuAdapter) negotiateProviderProtocol(ctx context.Context, providerConfig *types.ProviderConfig) (int, error) {
+	versions, err := a.registry.GetProviderVersions(ctx, types.ProviderConfig{Name: providerConfig.Name})
+	if err != nil {
+		log.Printf("Could not list the plugin protocols of provider %s, leaving it to the plugin handshake: %v", providerConfig.Name, err)
+		return 0, nil
+	}
+
+	requested := strings.TrimPrefix(providerConfig.Version, "v")
+	for _, version := range versions {
+		if strings.TrimPrefix(version.Version, "v") != requested {
+			continue
+		}
+
+		protocol, err := negotiateProtocol(version.Protocols)
+		if err != nil {
+			return 0, fmt.Errorf("provider %s@%s cannot be used: %w", providerConfig.Name, requested, err)
+		}
+		return protocol, nil
+	}
+
+	return 0, nil
+}
+
+// providerProtocol returns the plugin protocol negotiated for a provider version, 0 if unknown
+func (a *OpenTofuAdapter) providerProtocol(versionedName string) int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.protocols[versionedName]
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateProtocol(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		protocols []string
+		expected  int
+	}{
+		"protocol 5 only":    {protocols: []string{"5.0"}, expected: 5},
+		"protocol 6 only":    {protocols: []string{"6.0"}, expected: 6},
+		"both prefer 6":      {protocols: []string{"5.0", "6.0"}, expected: 6},
+		"minor versions":     {protocols: []string{"5.2"}, expected: 5},
+		"unlisted protocols": {protocols: nil, expected: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			protocol, err := negotiateProtocol(tc.protocols)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, protocol)
+		})
+	}
+
+	_, err := negotiateProtocol([]string{"4.0"})
+	assert.ErrorContains(t, err, "the provider speaks plugin protocol 4.0, only protocols 5 and 6 are supported")
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opentofu/provider-client/tofuprovider"
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin5"
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin6"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// rawClient makes the plugin protocol calls the provider client does not implement, or whose responses
+// it does not fully model, such as the attribute paths of diagnostics or the attributes whose change
+// requires replacing a resource. It uses the gRPC client of the protocol version negotiated with the
+// plugin.
+type rawClient interface {
+	validateResourceConfig(ctx context.Context, resourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error)
+	validateDataSourceConfig(ctx context.Context, dataSourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error)
+	upgradeResourceState(ctx context.Context, resourceType string, version int64, rawState providerschema.RawState) (rawValue, types.Diagnostics, error)
+	configureProvider(ctx context.Context, req *providerops.ConfigureProviderRequest) (types.Diagnostics, error)
+	planResourceChange(ctx context.Context, req *providerops.PlanManagedResourceChangeRequest) (plannedChange, types.Diagnostics, error)
+	applyResourceChange(ctx context.Context, req *providerops.ApplyManagedResourceChangeRequest) (appliedChange, types.Diagnostics, error)
+	readResource(ctx context.Context, req *providerops.ReadManagedResourceRequest) (currentResource, types.Diagnostics, error)
+	readDataSource(ctx context.Context, req *providerops.ReadDataResourceRequest) (rawValue, types.Diagnostics, error)
+	openEphemeralResource(ctx context.Context, ephemeralType string, config providerschema.DynamicValueIn) (openedEphemeral, types.Diagnostics, error)
+	renewEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (renewedEphemeral, types.Diagnostics, error)
+	closeEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (types.Diagnostics, error)
+	moveResourceState(ctx context.Context, req moveRequest) (movedResource, types.Diagnostics, error)
+	identitySchemas(ctx context.Context) (map[string]identitySchema, types.Diagnostics, error)
+	upgradeResourceIdentity(ctx context.Context, resourceType string, version int64, rawIdentityJSON []byte) (rawValue, types.Diagnostics, error)
+	importResourceState(ctx context.Context, resourceType, id string, identity providerschema.DynamicValueIn) ([]importedResource, types.Diagnostics, error)
+}
+
+// newRawClient returns the raw client of a running provider plugin
+func newRawClient(provider tofuprovider.GRPCPluginProvider) (rawClient, error) {
+	observe := func(err error) error { return err }
+	if plugin, ok := provider.(*runningPlugin); ok {
+		observe = plugin.observe
+	}
+
+	switch client := provider.ClientProxy().(type) {
+	case tfplugin6.ProviderClient:
+		return rawClient6{client: client, observe: observe}, nil
+	case tfplugin5.ProviderClient:
+		return rawClient5{client: client, observe: observe}, nil
+	default:
+		return nil, fmt.Errorf("unsupported plugin protocol %d", provider.ProtocolMajorVersion())
+	}
+}
+
+// plannedChange is the outcome of planning a resource change
+type plannedChange struct {
+	plannedState    rawValue
+	plannedPrivate  []byte
+	requiresReplace []cty.Path
+}
+
+// appliedChange is the outcome of applying a resource change. The new state is missing if the provider
+// failed before knowing what it changed.
+type appliedChange struct {
+	newState rawValue
+	private  []byte
+}
+
+// currentResource is the current state of a resource as read by its provider
+type currentResource struct {
+	state   rawValue
+	private []byte
+}
+
+// openedEphemeral is the result of opening an ephemeral resource
+type openedEphemeral struct {
+	result  rawValue
+	private []byte
+	renewAt time.Time // zero if the resource does not need to be renewed
+}
+
+// renewedEphemeral is the result of renewing an ephemeral resource
+type renewedEphemeral struct {
+	private []byte
+	renewAt time.Time // zero if the resource does not need to be renewed again
+}
+
+// moveRequest asks a provider to convert the state of a resource of another type
+type moveRequest struct {
+	sourceProviderAddress string
+	sourceType            string
+	sourceSchemaVersion   int64
+	sourceStateJSON       []byte
+	sourcePrivate         []byte
+	targetType            string
+}
+
+// movedResource is the state of a resource converted from another type
+type movedResource struct {
+	state   rawValue
+	private []byte
+}
+
+// identitySchema describes the identity of the resources of a type
+type identitySchema struct {
+	version    int64
+	attributes map[string]identityAttribute
+}
+
+// identityAttribute is an attribute of a resource identity
+type identityAttribute struct {
+	ty                cty.Type
+	requiredForImport bool
+}
+
+// importedResource is a resource returned by an import, before it is read
+type importedResource struct {
+	resourceType string
+	state        rawValue
+	private      []byte
+	identity     *rawValue // nil if the provider returned no identity
+}
+
+// rawValue is a value as encoded by the plugin protocol, decoded once its type is known
+type rawValue struct {
+	msgpack []byte
+	json    []byte
+}
+
+// isMissing reports whether the provider returned no value
+func (v rawValue) isMissing() bool {
+	return len(v.msgpack) == 0 && len(v.json) == 0
+}
+
+// AsCtyValue decodes the value with the given type
+func (v rawValue) AsCtyValue(ty cty.Type) (cty.Value, error) {
+	switch {
+	case len(v.msgpack) != 0:
+		return msgpack.Unmarshal(v.msgpack, ty)
+	case len(v.json) != 0:
+		return ctyjson.Unmarshal(v.json, ty)
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported value serialization format")
+	}
+}
+
+// renewAt converts the time a provider asks for an ephemeral resource to be renewed at
+func renewAt(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return timestamp.AsTime()
+}
+
+// encodeDynamicValue encodes a value as MessagePack, nil for no value
+func encodeDynamicValue(value providerschema.DynamicValueIn) ([]byte, error) {
+	if value == providerschema.NoDynamicValue {
+		return nil, nil
+	}
+	return msgpack.Marshal(value.Value(), value.SerializationType())
+}
+
+// protocolValue is a DynamicValue message of either plugin protocol version
+type protocolValue interface {
+	GetMsgpack() []byte
+	GetJson() []byte
+}
+
+// newRawValue returns a value of either protocol version to decode
+func newRawValue(value protocolValue) rawValue {
+	return rawValue{msgpack: value.GetMsgpack(), json: value.GetJson()}
+}
+
+// protocolIdentityAttribute is an identity attribute of either plugin protocol version
+type protocolIdentityAttribute interface {
+	GetName() string
+	GetType() []byte
+	GetRequiredForImport() bool
+}
+
+// identityAttributes converts the identity attributes of a resource type of either protocol version
+func identityAttributes[A protocolIdentityAttribute](resourceType string, attrs []A) (map[string]identityAttribute, error) {
+	attributes := make(map[string]identityAttribute, len(attrs))
+	for _, attr := range attrs {
+		ty, err := ctyjson.UnmarshalType(attr.GetType())
+		if err != nil {
+			return nil, fmt.Errorf("invalid type of %s identity attribute %s: %w", resourceType, attr.GetName(), err)
+		}
+		attributes[attr.GetName()] = identityAttribute{ty: ty, requiredForImport: attr.GetRequiredForImport()}
+	}
+	return attributes, nil
+}
+
+// protocolDiagnostics converts the diagnostics of either plugin protocol version, keeping the attribute
+// path each one refers to. The Diagnostic messages of both versions are identical, so they are read
+// through protobuf reflection.
+func protocolDiagnostics[D proto.Message](diags []D) types.Diagnostics {
+	result := types.Diagnostics{}
+
+	for _, diag := range diags {
+		message := diag.ProtoReflect()
+		fields := message.Descriptor().Fields()
+
+		var severity string
+		switch message.Get(fields.ByName("severity")).Enum() {
+		case tfplugin6.Diagnostic_ERROR.Number():
+			severity = "error"
+		case tfplugin6.Diagnostic_WARNING.Number():
+			severity = "warning"
+		default:
+			continue
+		}
+
+		result = append(result, types.Diagnostic{
+			Severity:  severity,
+			Summary:   message.Get(fields.ByName("summary")).String(),
+			Detail:    message.Get(fields.ByName("detail")).String(),
+			Attribute: formatPath(attributePath(message.Get(fields.ByName("attribute")).Message())),
+		})
+	}
+
+	return result
+}
+
+// attributePath converts an AttributePath message of either plugin protocol version
+func attributePath(path protoreflect.Message) cty.Path {
+	var result cty.Path
+
+	steps := path.Get(path.Descriptor().Fields().ByName("steps")).List()
+	for i := range steps.Len() {
+		step := steps.Get(i).Message()
+		field := step.WhichOneof(step.Descriptor().Oneofs().ByName("selector"))
+		if field == nil {
+			continue
+		}
+
+		switch value := step.Get(field); field.Name() {
+		case "attribute_name":
+			result = result.GetAttr(value.String())
+		case "element_key_string":
+			result = result.Index(cty.StringVal(value.String()))
+		case "element_key_int":
+			result = result.Index(cty.NumberIntVal(value.Int()))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin5"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// rawClient5 is the raw client of plugins speaking protocol 5
+type rawClient5 struct {
+	client  tfplugin5.ProviderClient
+	observe func(error) error
+}
+
+func (c rawClient5) validateResourceConfig(ctx context.Context, resourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error) {
+	encoded, err := dynamicValue5(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ValidateResourceTypeConfig(ctx, &tfplugin5.ValidateResourceTypeConfig_Request{
+		TypeName: resourceType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) validateDataSourceConfig(ctx context.Context, dataSourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error) {
+	encoded, err := dynamicValue5(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ValidateDataSourceConfig(ctx, &tfplugin5.ValidateDataSourceConfig_Request{
+		TypeName: dataSourceType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) upgradeResourceState(ctx context.Context, resourceType string, version int64, rawState providerschema.RawState) (rawValue, types.Diagnostics, error) {
+	resp, err := c.client.UpgradeResourceState(ctx, &tfplugin5.UpgradeResourceState_Request{
+		TypeName: resourceType,
+		Version:  version,
+		RawState: &tfplugin5.RawState{Json: rawState.JSON, Flatmap: rawState.Flatmap},
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetUpgradedState()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) planResourceChange(ctx context.Context, req *providerops.PlanManagedResourceChangeRequest) (plannedChange, types.Diagnostics, error) {
+	priorState, err := dynamicValue5(req.PriorState)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid prior state: %w", err)
+	}
+	config, err := dynamicValue5(req.Config)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	proposedNewState, err := dynamicValue5(req.ProposedNewState)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid proposed new state: %w", err)
+	}
+
+	resp, err := c.client.PlanResourceChange(ctx, &tfplugin5.PlanResourceChange_Request{
+		TypeName:         req.ResourceType,
+		PriorState:       priorState,
+		Config:           config,
+		ProposedNewState: proposedNewState,
+		PriorPrivate:     req.PriorProviderInternal,
+	})
+	if err != nil {
+		return plannedChange{}, nil, c.observe(err)
+	}
+
+	requiresReplace := make([]cty.Path, 0, len(resp.RequiresReplace))
+	for _, path := range resp.RequiresReplace {
+		requiresReplace = append(requiresReplace, attributePath(path.ProtoReflect()))
+	}
+
+	return plannedChange{
+		plannedState:    newRawValue(resp.GetPlannedState()),
+		plannedPrivate:  resp.GetPlannedPrivate(),
+		requiresReplace: requiresReplace,
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) configureProvider(ctx context.Context, req *providerops.ConfigureProviderRequest) (types.Diagnostics, error) {
+	config, err := dynamicValue5(req.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.Configure(ctx, &tfplugin5.Configure_Request{
+		TerraformVersion: req.TerraformVersion,
+		Config:           config,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) applyResourceChange(ctx context.Context, req *providerops.ApplyManagedResourceChangeRequest) (appliedChange, types.Diagnostics, error) {
+	priorState, err := dynamicValue5(req.PriorState)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid prior state: %w", err)
+	}
+	plannedState, err := dynamicValue5(req.PlannedNewState)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid planned state: %w", err)
+	}
+	config, err := dynamicValue5(req.Config)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ApplyResourceChange(ctx, &tfplugin5.ApplyResourceChange_Request{
+		TypeName:       req.ResourceType,
+		PriorState:     priorState,
+		PlannedState:   plannedState,
+		Config:         config,
+		PlannedPrivate: req.PlannedProviderInternal,
+	})
+	if err != nil {
+		return appliedChange{}, nil, c.observe(err)
+	}
+
+	return appliedChange{
+		newState: newRawValue(resp.GetNewState()),
+		private:  resp.GetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) readResource(ctx context.Context, req *providerops.ReadManagedResourceRequest) (currentResource, types.Diagnostics, error) {
+	currentState, err := dynamicValue5(req.CurrentState)
+	if err != nil {
+		return currentResource{}, nil, fmt.Errorf("invalid current state: %w", err)
+	}
+
+	resp, err := c.client.ReadResource(ctx, &tfplugin5.ReadResource_Request{
+		TypeName:     req.ResourceType,
+		CurrentState: currentState,
+		Private:      req.ProviderInternal,
+	})
+	if err != nil {
+		return currentResource{}, nil, c.observe(err)
+	}
+
+	return currentResource{
+		state:   newRawValue(resp.GetNewState()),
+		private: resp.GetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) readDataSource(ctx context.Context, req *providerops.ReadDataResourceRequest) (rawValue, types.Diagnostics, error) {
+	config, err := dynamicValue5(req.Config)
+	if err != nil {
+		return rawValue{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	providerMeta, err := dynamicValue5(req.ProviderMeta)
+	if err != nil {
+		return rawValue{}, nil, fmt.Errorf("invalid provider meta: %w", err)
+	}
+
+	resp, err := c.client.ReadDataSource(ctx, &tfplugin5.ReadDataSource_Request{
+		TypeName:     req.ResourceType,
+		Config:       config,
+		ProviderMeta: providerMeta,
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetState()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+// dynamicValue5 encodes a value for protocol 5
+func dynamicValue5(value providerschema.DynamicValueIn) (*tfplugin5.DynamicValue, error) {
+	encoded, err := encodeDynamicValue(value)
+	if encoded == nil || err != nil {
+		return nil, err
+	}
+	return &tfplugin5.DynamicValue{Msgpack: encoded}, nil
+}
+
+func (c rawClient5) openEphemeralResource(ctx context.Context, ephemeralType string, config providerschema.DynamicValueIn) (openedEphemeral, types.Diagnostics, error) {
+	encoded, err := dynamicValue5(config)
+	if err != nil {
+		return openedEphemeral{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.OpenEphemeralResource(ctx, &tfplugin5.OpenEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return openedEphemeral{}, nil, c.observe(err)
+	}
+
+	return openedEphemeral{
+		result:  newRawValue(resp.GetResult()),
+		private: resp.GetPrivate(),
+		renewAt: renewAt(resp.GetRenewAt()),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) renewEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (renewedEphemeral, types.Diagnostics, error) {
+	resp, err := c.client.RenewEphemeralResource(ctx, &tfplugin5.RenewEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Private:  private,
+	})
+	if err != nil {
+		return renewedEphemeral{}, nil, c.observe(err)
+	}
+
+	return renewedEphemeral{
+		private: resp.GetPrivate(),
+		renewAt: renewAt(resp.GetRenewAt()),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) closeEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (types.Diagnostics, error) {
+	resp, err := c.client.CloseEphemeralResource(ctx, &tfplugin5.CloseEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Private:  private,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) moveResourceState(ctx context.Context, req moveRequest) (movedResource, types.Diagnostics, error) {
+	resp, err := c.client.MoveResourceState(ctx, &tfplugin5.MoveResourceState_Request{
+		SourceProviderAddress: req.sourceProviderAddress,
+		SourceTypeName:        req.sourceType,
+		SourceSchemaVersion:   req.sourceSchemaVersion,
+		SourceState:           &tfplugin5.RawState{Json: req.sourceStateJSON},
+		SourcePrivate:         req.sourcePrivate,
+		TargetTypeName:        req.targetType,
+	})
+	if err != nil {
+		return movedResource{}, nil, c.observe(err)
+	}
+
+	return movedResource{
+		state:   newRawValue(resp.GetTargetState()),
+		private: resp.GetTargetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) identitySchemas(ctx context.Context) (map[string]identitySchema, types.Diagnostics, error) {
+	resp, err := c.client.GetResourceIdentitySchemas(ctx, &tfplugin5.GetResourceIdentitySchemas_Request{})
+	if err != nil {
+		return nil, nil, c.observe(err)
+	}
+
+	schemas := make(map[string]identitySchema, len(resp.IdentitySchemas))
+	for resourceType, schema := range resp.IdentitySchemas {
+		attributes, err := identityAttributes(resourceType, schema.IdentityAttributes)
+		if err != nil {
+			return nil, nil, err
+		}
+		schemas[resourceType] = identitySchema{version: schema.Version, attributes: attributes}
+	}
+
+	return schemas, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) upgradeResourceIdentity(ctx context.Context, resourceType string, version int64, rawIdentityJSON []byte) (rawValue, types.Diagnostics, error) {
+	resp, err := c.client.UpgradeResourceIdentity(ctx, &tfplugin5.UpgradeResourceIdentity_Request{
+		TypeName:    resourceType,
+		Version:     version,
+		RawIdentity: &tfplugin5.RawState{Json: rawIdentityJSON},
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetUpgradedIdentity().GetIdentityData()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient5) importResourceState(ctx context.Context, resourceType, id string, identity providerschema.DynamicValueIn) ([]importedResource, types.Diagnostics, error) {
+	req := &tfplugin5.ImportResourceState_Request{
+		TypeName: resourceType,
+		Id:       id,
+	}
+	if identity != providerschema.NoDynamicValue {
+		identityData, err := dynamicValue5(identity)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid identity: %w", err)
+		}
+		req.Identity = &tfplugin5.ResourceIdentityData{IdentityData: identityData}
+	}
+
+	resp, err := c.client.ImportResourceState(ctx, req)
+	if err != nil {
+		return nil, nil, c.observe(err)
+	}
+
+	imported := make([]importedResource, 0, len(resp.ImportedResources))
+	for _, resource := range resp.ImportedResources {
+		result := importedResource{
+			resourceType: resource.GetTypeName(),
+			state:        newRawValue(resource.GetState()),
+			private:      resource.GetPrivate(),
+		}
+		if identityData := resource.GetIdentity().GetIdentityData(); identityData != nil {
+			identity := newRawValue(identityData)
+			result.identity = &identity
+		}
+		imported = append(imported, result)
+	}
+
+	return imported, protocolDiagnostics(resp.Diagnostics), nil
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin6"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// rawClient6 is the raw client of plugins speaking protocol 6
+type rawClient6 struct {
+	client  tfplugin6.ProviderClient
+	observe func(error) error
+}
+
+func (c rawClient6) validateResourceConfig(ctx context.Context, resourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error) {
+	encoded, err := dynamicValue6(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ValidateResourceConfig(ctx, &tfplugin6.ValidateResourceConfig_Request{
+		TypeName: resourceType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) validateDataSourceConfig(ctx context.Context, dataSourceType string, config providerschema.DynamicValueIn) (types.Diagnostics, error) {
+	encoded, err := dynamicValue6(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ValidateDataResourceConfig(ctx, &tfplugin6.ValidateDataResourceConfig_Request{
+		TypeName: dataSourceType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) upgradeResourceState(ctx context.Context, resourceType string, version int64, rawState providerschema.RawState) (rawValue, types.Diagnostics, error) {
+	resp, err := c.client.UpgradeResourceState(ctx, &tfplugin6.UpgradeResourceState_Request{
+		TypeName: resourceType,
+		Version:  version,
+		RawState: &tfplugin6.RawState{Json: rawState.JSON, Flatmap: rawState.Flatmap},
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetUpgradedState()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) planResourceChange(ctx context.Context, req *providerops.PlanManagedResourceChangeRequest) (plannedChange, types.Diagnostics, error) {
+	priorState, err := dynamicValue6(req.PriorState)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid prior state: %w", err)
+	}
+	config, err := dynamicValue6(req.Config)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	proposedNewState, err := dynamicValue6(req.ProposedNewState)
+	if err != nil {
+		return plannedChange{}, nil, fmt.Errorf("invalid proposed new state: %w", err)
+	}
+
+	resp, err := c.client.PlanResourceChange(ctx, &tfplugin6.PlanResourceChange_Request{
+		TypeName:         req.ResourceType,
+		PriorState:       priorState,
+		Config:           config,
+		ProposedNewState: proposedNewState,
+		PriorPrivate:     req.PriorProviderInternal,
+	})
+	if err != nil {
+		return plannedChange{}, nil, c.observe(err)
+	}
+
+	requiresReplace := make([]cty.Path, 0, len(resp.RequiresReplace))
+	for _, path := range resp.RequiresReplace {
+		requiresReplace = append(requiresReplace, attributePath(path.ProtoReflect()))
+	}
+
+	return plannedChange{
+		plannedState:    newRawValue(resp.GetPlannedState()),
+		plannedPrivate:  resp.GetPlannedPrivate(),
+		requiresReplace: requiresReplace,
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) configureProvider(ctx context.Context, req *providerops.ConfigureProviderRequest) (types.Diagnostics, error) {
+	config, err := dynamicValue6(req.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ConfigureProvider(ctx, &tfplugin6.ConfigureProvider_Request{
+		TerraformVersion: req.TerraformVersion,
+		Config:           config,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) applyResourceChange(ctx context.Context, req *providerops.ApplyManagedResourceChangeRequest) (appliedChange, types.Diagnostics, error) {
+	priorState, err := dynamicValue6(req.PriorState)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid prior state: %w", err)
+	}
+	plannedState, err := dynamicValue6(req.PlannedNewState)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid planned state: %w", err)
+	}
+	config, err := dynamicValue6(req.Config)
+	if err != nil {
+		return appliedChange{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.ApplyResourceChange(ctx, &tfplugin6.ApplyResourceChange_Request{
+		TypeName:       req.ResourceType,
+		PriorState:     priorState,
+		PlannedState:   plannedState,
+		Config:         config,
+		PlannedPrivate: req.PlannedProviderInternal,
+	})
+	if err != nil {
+		return appliedChange{}, nil, c.observe(err)
+	}
+
+	return appliedChange{
+		newState: newRawValue(resp.GetNewState()),
+		private:  resp.GetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) readResource(ctx context.Context, req *providerops.ReadManagedResourceRequest) (currentResource, types.Diagnostics, error) {
+	currentState, err := dynamicValue6(req.CurrentState)
+	if err != nil {
+		return currentResource{}, nil, fmt.Errorf("invalid current state: %w", err)
+	}
+
+	resp, err := c.client.ReadResource(ctx, &tfplugin6.ReadResource_Request{
+		TypeName:     req.ResourceType,
+		CurrentState: currentState,
+		Private:      req.ProviderInternal,
+	})
+	if err != nil {
+		return currentResource{}, nil, c.observe(err)
+	}
+
+	return currentResource{
+		state:   newRawValue(resp.GetNewState()),
+		private: resp.GetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) readDataSource(ctx context.Context, req *providerops.ReadDataResourceRequest) (rawValue, types.Diagnostics, error) {
+	config, err := dynamicValue6(req.Config)
+	if err != nil {
+		return rawValue{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	providerMeta, err := dynamicValue6(req.ProviderMeta)
+	if err != nil {
+		return rawValue{}, nil, fmt.Errorf("invalid provider meta: %w", err)
+	}
+
+	resp, err := c.client.ReadDataSource(ctx, &tfplugin6.ReadDataSource_Request{
+		TypeName:     req.ResourceType,
+		Config:       config,
+		ProviderMeta: providerMeta,
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetState()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+// dynamicValue6 encodes a value for protocol 6
+func dynamicValue6(value providerschema.DynamicValueIn) (*tfplugin6.DynamicValue, error) {
+	encoded, err := encodeDynamicValue(value)
+	if encoded == nil || err != nil {
+		return nil, err
+	}
+	return &tfplugin6.DynamicValue{Msgpack: encoded}, nil
+}
+
+func (c rawClient6) openEphemeralResource(ctx context.Context, ephemeralType string, config providerschema.DynamicValueIn) (openedEphemeral, types.Diagnostics, error) {
+	encoded, err := dynamicValue6(config)
+	if err != nil {
+		return openedEphemeral{}, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	resp, err := c.client.OpenEphemeralResource(ctx, &tfplugin6.OpenEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Config:   encoded,
+	})
+	if err != nil {
+		return openedEphemeral{}, nil, c.observe(err)
+	}
+
+	return openedEphemeral{
+		result:  newRawValue(resp.GetResult()),
+		private: resp.GetPrivate(),
+		renewAt: renewAt(resp.GetRenewAt()),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) renewEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (renewedEphemeral, types.Diagnostics, error) {
+	resp, err := c.client.RenewEphemeralResource(ctx, &tfplugin6.RenewEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Private:  private,
+	})
+	if err != nil {
+		return renewedEphemeral{}, nil, c.observe(err)
+	}
+
+	return renewedEphemeral{
+		private: resp.GetPrivate(),
+		renewAt: renewAt(resp.GetRenewAt()),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) closeEphemeralResource(ctx context.Context, ephemeralType string, private []byte) (types.Diagnostics, error) {
+	resp, err := c.client.CloseEphemeralResource(ctx, &tfplugin6.CloseEphemeralResource_Request{
+		TypeName: ephemeralType,
+		Private:  private,
+	})
+	if err != nil {
+		return nil, c.observe(err)
+	}
+
+	return protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) moveResourceState(ctx context.Context, req moveRequest) (movedResource, types.Diagnostics, error) {
+	resp, err := c.client.MoveResourceState(ctx, &tfplugin6.MoveResourceState_Request{
+		SourceProviderAddress: req.sourceProviderAddress,
+		SourceTypeName:        req.sourceType,
+		SourceSchemaVersion:   req.sourceSchemaVersion,
+		SourceState:           &tfplugin6.RawState{Json: req.sourceStateJSON},
+		SourcePrivate:         req.sourcePrivate,
+		TargetTypeName:        req.targetType,
+	})
+	if err != nil {
+		return movedResource{}, nil, c.observe(err)
+	}
+
+	return movedResource{
+		state:   newRawValue(resp.GetTargetState()),
+		private: resp.GetTargetPrivate(),
+	}, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) identitySchemas(ctx context.Context) (map[string]identitySchema, types.Diagnostics, error) {
+	resp, err := c.client.GetResourceIdentitySchemas(ctx, &tfplugin6.GetResourceIdentitySchemas_Request{})
+	if err != nil {
+		return nil, nil, c.observe(err)
+	}
+
+	schemas := make(map[string]identitySchema, len(resp.IdentitySchemas))
+	for resourceType, schema := range resp.IdentitySchemas {
+		attributes, err := identityAttributes(resourceType, schema.IdentityAttributes)
+		if err != nil {
+			return nil, nil, err
+		}
+		schemas[resourceType] = identitySchema{version: schema.Version, attributes: attributes}
+	}
+
+	return schemas, protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) upgradeResourceIdentity(ctx context.Context, resourceType string, version int64, rawIdentityJSON []byte) (rawValue, types.Diagnostics, error) {
+	resp, err := c.client.UpgradeResourceIdentity(ctx, &tfplugin6.UpgradeResourceIdentity_Request{
+		TypeName:    resourceType,
+		Version:     version,
+		RawIdentity: &tfplugin6.RawState{Json: rawIdentityJSON},
+	})
+	if err != nil {
+		return rawValue{}, nil, c.observe(err)
+	}
+
+	return newRawValue(resp.GetUpgradedIdentity().GetIdentityData()), protocolDiagnostics(resp.Diagnostics), nil
+}
+
+func (c rawClient6) importResourceState(ctx context.Context, resourceType, id string, identity providerschema.DynamicValueIn) ([]importedResource, types.Diagnostics, error) {
+	req := &tfplugin6.ImportResourceState_Request{
+		TypeName: resourceType,
+		Id:       id,
+	}
+	if identity != providerschema.NoDynamicValue {
+		identityData, err := dynamicValue6(identity)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid identity: %w", err)
+		}
+		req.Identity = &tfplugin6.ResourceIdentityData{IdentityData: identityData}
+	}
+
+	resp, err := c.client.ImportResourceState(ctx, req)
+	if err != nil {
+		return nil, nil, c.observe(err)
+	}
+
+	imported := make([]importedResource, 0, len(resp.ImportedResources))
+	for _, resource := range resp.ImportedResources {
+		result := importedResource{
+			resourceType: resource.GetTypeName(),
+			state:        newRawValue(resource.GetState()),
+			private:      resource.GetPrivate(),
+		}
+		if identityData := resource.GetIdentity().GetIdentityData(); identityData != nil {
+			identity := newRawValue(identityData)
+			result.identity = &identity
+		}
+		imported = append(imported, result)
+	}
+
+	return imported, protocolDiagnostics(resp.Diagnostics), nil
+}
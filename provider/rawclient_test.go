@@ -0,0 +1,157 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin5"
+	"github.com/opentofu/provider-client/tofuprovider/grpc/tfplugin6"
+	"github.com/opentofu/provider-client/tofuprovider/providerops"
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/grpc"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestRawDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("protocol 6", func(t *testing.T) {
+		t.Parallel()
+
+		diags := protocolDiagnostics([]*tfplugin6.Diagnostic{
+			{
+				Severity: tfplugin6.Diagnostic_ERROR,
+				Summary:  "Invalid port",
+				Detail:   "Ports must be between 1 and 65535.",
+				Attribute: &tfplugin6.AttributePath{Steps: []*tfplugin6.AttributePath_Step{
+					{Selector: &tfplugin6.AttributePath_Step_AttributeName{AttributeName: "rule"}},
+					{Selector: &tfplugin6.AttributePath_Step_ElementKeyInt{ElementKeyInt: 0}},
+					{Selector: &tfplugin6.AttributePath_Step_AttributeName{AttributeName: "port"}},
+				}},
+			},
+			{Severity: tfplugin6.Diagnostic_WARNING, Summary: "Deprecated"},
+			{Severity: tfplugin6.Diagnostic_INVALID, Summary: "Ignored"},
+		})
+
+		assert.Equal(t, types.Diagnostics{
+			{Severity: "error", Summary: "Invalid port", Detail: "Ports must be between 1 and 65535.", Attribute: "rule[0].port"},
+			{Severity: "warning", Summary: "Deprecated"},
+		}, diags)
+	})
+
+	t.Run("protocol 5", func(t *testing.T) {
+		t.Parallel()
+
+		diags := protocolDiagnostics([]*tfplugin5.Diagnostic{{
+			Severity: tfplugin5.Diagnostic_ERROR,
+			Summary:  "Invalid tag",
+			Attribute: &tfplugin5.AttributePath{Steps: []*tfplugin5.AttributePath_Step{
+				{Selector: &tfplugin5.AttributePath_Step_AttributeName{AttributeName: "tags"}},
+				{Selector: &tfplugin5.AttributePath_Step_ElementKeyString{ElementKeyString: "Name"}},
+			}},
+		}})
+
+		assert.Equal(t, types.Diagnostics{{Severity: "error", Summary: "Invalid tag", Attribute: `tags["Name"]`}}, diags)
+	})
+}
+
+// fakePlanClient6 is a protocol 6 plugin that plans every change as replacing the resource
+type fakePlanClient6 struct {
+	tfplugin6.ProviderClient
+	requests []*tfplugin6.PlanResourceChange_Request
+}
+
+func (c *fakePlanClient6) PlanResourceChange(_ context.Context, req *tfplugin6.PlanResourceChange_Request, _ ...grpc.CallOption) (*tfplugin6.PlanResourceChange_Response, error) {
+	c.requests = append(c.requests, req)
+	return &tfplugin6.PlanResourceChange_Response{
+		PlannedState:   req.ProposedNewState,
+		PlannedPrivate: []byte("planned"),
+		RequiresReplace: []*tfplugin6.AttributePath{{Steps: []*tfplugin6.AttributePath_Step{
+			{Selector: &tfplugin6.AttributePath_Step_AttributeName{AttributeName: "name"}},
+		}}},
+		Diagnostics: []*tfplugin6.Diagnostic{{Severity: tfplugin6.Diagnostic_WARNING, Summary: "Renaming recreates the bucket"}},
+	}, nil
+}
+
+func TestRawPlanResourceChange(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{"name": cty.String})
+	prior := providerschema.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("logs")}), ty)
+	config := providerschema.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("archive")}), ty)
+
+	client := &fakePlanClient6{}
+	planned, diags, err := rawClient6{client: client, observe: func(err error) error { return err }}.planResourceChange(context.Background(), &providerops.PlanManagedResourceChangeRequest{
+		ResourceType:          "aws_s3_bucket",
+		PriorState:            prior,
+		Config:                config,
+		ProposedNewState:      config,
+		PriorProviderInternal: []byte("prior"),
+	})
+	require.NoError(t, err)
+	require.Len(t, client.requests, 1, "the change is planned once")
+	assert.Equal(t, []byte("prior"), client.requests[0].PriorPrivate)
+
+	plannedState, err := planned.plannedState.AsCtyValue(ty)
+	require.NoError(t, err)
+	assert.True(t, plannedState.RawEquals(config.Value()))
+	assert.Equal(t, []byte("planned"), planned.plannedPrivate)
+	assert.Equal(t, []cty.Path{cty.GetAttrPath("name")}, planned.requiresReplace)
+	assert.Equal(t, types.Diagnostics{{Severity: "warning", Summary: "Renaming recreates the bucket"}}, diags)
+
+	priorState, err := msgpack.Unmarshal(client.requests[0].PriorState.Msgpack, ty)
+	require.NoError(t, err)
+	assert.True(t, priorState.RawEquals(prior.Value()))
+}
+
+// fakeReadClient5 is a protocol 5 plugin whose resources have drifted
+type fakeReadClient5 struct {
+	tfplugin5.ProviderClient
+	requests []*tfplugin5.ReadResource_Request
+}
+
+func (c *fakeReadClient5) ReadResource(_ context.Context, req *tfplugin5.ReadResource_Request, _ ...grpc.CallOption) (*tfplugin5.ReadResource_Response, error) {
+	c.requests = append(c.requests, req)
+	return &tfplugin5.ReadResource_Response{
+		NewState: req.CurrentState,
+		Private:  []byte("read"),
+		Diagnostics: []*tfplugin5.Diagnostic{{
+			Severity: tfplugin5.Diagnostic_WARNING,
+			Summary:  "Argument is deprecated",
+			Attribute: &tfplugin5.AttributePath{Steps: []*tfplugin5.AttributePath_Step{
+				{Selector: &tfplugin5.AttributePath_Step_AttributeName{AttributeName: "acl"}},
+			}},
+		}},
+	}, nil
+}
+
+func TestRawReadResource(t *testing.T) {
+	t.Parallel()
+
+	ty := cty.Object(map[string]cty.Type{"acl": cty.String})
+	current := providerschema.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{"acl": cty.StringVal("private")}), ty)
+
+	client := &fakeReadClient5{}
+	read, diags, err := rawClient5{client: client, observe: func(err error) error { return err }}.readResource(context.Background(), &providerops.ReadManagedResourceRequest{
+		ResourceType:     "aws_s3_bucket",
+		CurrentState:     current,
+		ProviderInternal: []byte("current"),
+	})
+	require.NoError(t, err)
+	require.Len(t, client.requests, 1)
+	assert.Equal(t, []byte("current"), client.requests[0].Private)
+
+	state, err := read.state.AsCtyValue(ty)
+	require.NoError(t, err)
+	assert.True(t, state.RawEquals(current.Value()))
+	assert.Equal(t, []byte("read"), read.private)
+	assert.Equal(t, types.Diagnostics{{Severity: "warning", Summary: "Argument is deprecated", Attribute: "acl"}}, diags)
+}
@@ -5,7 +5,9 @@ package provider
 
 import (
 	"fmt"
+	"iter"
 	"maps"
+	"slices"
 
 	"github.com/opentofu/provider-client/tofuprovider/providerschema"
 	"github.com/zclconf/go-cty/cty"
@@ -22,30 +24,7 @@ func (sc *SchemaConverter) opentofuSchemaToObjectType(schema providerschema.Sche
 
 	// Add attributes
 	for attrName, attr := range maps.Collect(schema.Attributes()) {
-		if nestedType := attr.NestedType(); nestedType != nil {
-			// Handle nested object types properly
-			nestedObjectType := sc.nestedObjectTypeToObjectType(nestedType)
-
-			switch nestedType.Nesting() {
-			case providerschema.NestingList:
-				attrTypes[attrName] = cty.List(nestedObjectType)
-			case providerschema.NestingSet:
-				attrTypes[attrName] = cty.Set(nestedObjectType)
-			case providerschema.NestingMap:
-				attrTypes[attrName] = cty.Map(nestedObjectType)
-			case providerschema.NestingSingle:
-				attrTypes[attrName] = nestedObjectType
-			default:
-				attrTypes[attrName] = cty.DynamicPseudoType
-			}
-		} else {
-			ctyType, err := attr.Type().AsCtyType()
-			if err != nil {
-				attrTypes[attrName] = cty.DynamicPseudoType
-			} else {
-				attrTypes[attrName] = ctyType
-			}
-		}
+		attrTypes[attrName] = sc.attributeType(attr)
 	}
 
 	// Add nested block types - these are typically lists or sets of objects
@@ -75,40 +54,132 @@ func (sc *SchemaConverter) opentofuSchemaToObjectType(schema providerschema.Sche
 	return cty.Object(attrTypes)
 }
 
-// nestedObjectTypeToObjectType converts a nested object type to cty.Type
-func (sc *SchemaConverter) nestedObjectTypeToObjectType(nestedType providerschema.ObjectType) cty.Type {
-	attrTypes := make(map[string]cty.Type)
+// blockSchema is implemented by both a schema and its nested block types
+type blockSchema interface {
+	NestedBlockTypes() iter.Seq2[string, providerschema.NestedBlockType]
+}
 
-	for attrName, attr := range maps.Collect(nestedType.Attributes()) {
-		if innerNestedType := attr.NestedType(); innerNestedType != nil {
-			// Recursively handle nested types
-			innerObjectType := sc.nestedObjectTypeToObjectType(innerNestedType)
-
-			switch innerNestedType.Nesting() {
-			case providerschema.NestingList:
-				attrTypes[attrName] = cty.List(innerObjectType)
-			case providerschema.NestingSet:
-				attrTypes[attrName] = cty.Set(innerObjectType)
-			case providerschema.NestingMap:
-				attrTypes[attrName] = cty.Map(innerObjectType)
-			case providerschema.NestingSingle:
-				attrTypes[attrName] = innerObjectType
-			default:
-				attrTypes[attrName] = cty.DynamicPseudoType
-			}
-		} else {
-			ctyType, err := attr.Type().AsCtyType()
-			if err != nil {
-				attrTypes[attrName] = cty.DynamicPseudoType
-			} else {
-				attrTypes[attrName] = ctyType
-			}
+// fillNestedBlocks fills absent nested blocks of a configuration value the way OpenTofu decodes configuration:
+// list, set and map blocks become empty collections and group blocks an object of nulls, recursively.
+// Absent attributes are already typed nulls after conversion, so only blocks the user sets are required.
+func (sc *SchemaConverter) fillNestedBlocks(block blockSchema, val cty.Value) cty.Value {
+	if val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() {
+		return val
+	}
+
+	attrs := val.AsValueMap()
+	if attrs == nil {
+		return val
+	}
+
+	for blockName, blockType := range block.NestedBlockTypes() {
+		blockVal, ok := attrs[blockName]
+		if !ok || !blockVal.IsKnown() {
+			continue
 		}
+
+		attrs[blockName] = sc.fillNestedBlock(blockType, blockVal)
 	}
 
-	if len(attrTypes) == 0 {
+	return cty.ObjectVal(attrs)
+}
+
+// fillNestedBlock fills a single nested block value, see fillNestedBlocks
+func (sc *SchemaConverter) fillNestedBlock(blockType providerschema.NestedBlockType, val cty.Value) cty.Value {
+	ty := val.Type()
+
+	if val.IsNull() {
+		switch {
+		case blockType.Nesting() == providerschema.NestingGroup && ty.IsObjectType():
+			return sc.fillNestedBlocks(blockType, cty.ObjectVal(nullAttributes(ty)))
+		case ty.IsListType():
+			return cty.ListValEmpty(ty.ElementType())
+		case ty.IsSetType():
+			return cty.SetValEmpty(ty.ElementType())
+		case ty.IsMapType():
+			return cty.MapValEmpty(ty.ElementType())
+		default:
+			return val
+		}
+	}
+
+	switch {
+	case ty.IsObjectType():
+		return sc.fillNestedBlocks(blockType, val)
+	case ty.IsListType(), ty.IsSetType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elems := make([]cty.Value, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elems = append(elems, sc.fillNestedBlocks(blockType, elem))
+		}
+		if ty.IsListType() {
+			return cty.ListVal(elems)
+		}
+		return cty.SetVal(elems)
+	case ty.IsMapType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elems := make(map[string]cty.Value, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			elems[key.AsString()] = sc.fillNestedBlocks(blockType, elem)
+		}
+		return cty.MapVal(elems)
+	default:
+		return val
+	}
+}
+
+// nullAttributes returns a typed null for every attribute of an object type
+func nullAttributes(ty cty.Type) map[string]cty.Value {
+	attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+	for name, attrType := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(attrType)
+	}
+	return attrs
+}
+
+// attributeType converts an attribute to cty.Type. With plugin protocol 6 attributes can have a nested
+// object type instead of a plain type, which is a collection of objects according to its nesting.
+func (sc *SchemaConverter) attributeType(attr providerschema.Attribute) cty.Type {
+	nestedType := attr.NestedType()
+	if nestedType == nil {
+		if attr.Type() == nil {
+			return cty.DynamicPseudoType
+		}
+		ctyType, err := attr.Type().AsCtyType()
+		if err != nil {
+			return cty.DynamicPseudoType
+		}
+		return ctyType
+	}
+
+	objectType := sc.nestedObjectTypeToObjectType(nestedType)
+	switch nestedType.Nesting() {
+	case providerschema.NestingList:
+		return cty.List(objectType)
+	case providerschema.NestingSet:
+		return cty.Set(objectType)
+	case providerschema.NestingMap:
+		return cty.Map(objectType)
+	case providerschema.NestingSingle:
+		return objectType
+	default:
 		return cty.DynamicPseudoType
 	}
+}
+
+// nestedObjectTypeToObjectType converts a nested object type to cty.Type
+func (sc *SchemaConverter) nestedObjectTypeToObjectType(nestedType providerschema.ObjectType) cty.Type {
+	attrTypes := make(map[string]cty.Type)
+
+	for attrName, attr := range maps.Collect(nestedType.Attributes()) {
+		attrTypes[attrName] = sc.attributeType(attr)
+	}
 
 	return cty.Object(attrTypes)
 }
@@ -119,30 +190,7 @@ func (sc *SchemaConverter) blockTypeToObjectType(blockType providerschema.Nested
 
 	// Handle attributes in the block type
 	for attrName, attr := range maps.Collect(blockType.Attributes()) {
-		if nestedType := attr.NestedType(); nestedType != nil {
-			// Recursively handle nested types
-			nestedObjectType := sc.nestedObjectTypeToObjectType(nestedType)
-
-			switch nestedType.Nesting() {
-			case providerschema.NestingList:
-				attrTypes[attrName] = cty.List(nestedObjectType)
-			case providerschema.NestingSet:
-				attrTypes[attrName] = cty.Set(nestedObjectType)
-			case providerschema.NestingMap:
-				attrTypes[attrName] = cty.Map(nestedObjectType)
-			case providerschema.NestingSingle:
-				attrTypes[attrName] = nestedObjectType
-			default:
-				attrTypes[attrName] = cty.DynamicPseudoType
-			}
-		} else {
-			ctyType, err := attr.Type().AsCtyType()
-			if err != nil {
-				attrTypes[attrName] = cty.DynamicPseudoType
-			} else {
-				attrTypes[attrName] = ctyType
-			}
-		}
+		attrTypes[attrName] = sc.attributeType(attr)
 	}
 
 	// Handle nested block types recursively
@@ -177,27 +225,7 @@ func (sc *SchemaConverter) processBlockTypeInfo(blockType providerschema.NestedB
 	}
 
 	// Get nesting mode
-	nesting := blockType.Nesting()
-	switch nesting {
-	case providerschema.NestingList:
-		blockInfo["nesting"] = "list"
-		blockInfo["type"] = "list"
-	case providerschema.NestingSet:
-		blockInfo["nesting"] = "set"
-		blockInfo["type"] = "set"
-	case providerschema.NestingMap:
-		blockInfo["nesting"] = "map"
-		blockInfo["type"] = "map"
-	case providerschema.NestingSingle:
-		blockInfo["nesting"] = "single"
-		blockInfo["type"] = "object"
-	case providerschema.NestingGroup:
-		blockInfo["nesting"] = "group"
-		blockInfo["type"] = "object"
-	default:
-		blockInfo["nesting"] = "unknown"
-		blockInfo["type"] = "unknown"
-	}
+	blockInfo["nesting"], blockInfo["type"] = nestingNames(blockType.Nesting())
 
 	// Get min and max items constraints
 	minItems, maxItems := blockType.ItemLimits()
@@ -205,61 +233,7 @@ func (sc *SchemaConverter) processBlockTypeInfo(blockType providerschema.NestedB
 	blockInfo["max_items"] = maxItems
 
 	// Extract nested attributes from the block
-	nestedProperties := make(map[string]any)
-	nestedRequired := []string{}
-
-	for nestedAttrName, nestedAttr := range maps.Collect(blockType.Attributes()) {
-		nestedAttrInfo := map[string]any{
-			"type": "string", // Default type
-		}
-
-		// Get the attribute type
-		if attrType := nestedAttr.Type(); attrType != nil {
-			if ctyType, err := attrType.AsCtyType(); err == nil {
-				nestedAttrInfo["type"] = sc.ctyTypeToString(ctyType)
-			}
-		}
-
-		// Check for nested types within the block attribute
-		if nestedType := nestedAttr.NestedType(); nestedType != nil {
-			nestedAttrInfo["nested"] = true
-		}
-
-		// Check if attribute is required
-		usage := nestedAttr.Usage()
-		if usage == providerschema.AttributeRequired {
-			nestedRequired = append(nestedRequired, nestedAttrName)
-			nestedAttrInfo["required"] = true
-		} else {
-			nestedAttrInfo["required"] = false
-		}
-
-		// Add usage information
-		switch usage {
-		case providerschema.AttributeRequired:
-			nestedAttrInfo["usage"] = "required"
-		case providerschema.AttributeOptional:
-			nestedAttrInfo["usage"] = "optional"
-		case providerschema.AttributeOptionalComputed:
-			nestedAttrInfo["usage"] = "optional_computed"
-		case providerschema.AttributeComputed:
-			nestedAttrInfo["usage"] = "computed"
-		default:
-			nestedAttrInfo["usage"] = "unsupported"
-		}
-
-		// Add sensitive and deprecated flags
-		nestedAttrInfo["sensitive"] = nestedAttr.IsSensitive()
-		nestedAttrInfo["deprecated"] = nestedAttr.IsDeprecated()
-		nestedAttrInfo["write_only"] = nestedAttr.IsWriteOnly()
-
-		// Add description if available
-		if desc, _ := nestedAttr.DocDescription(); desc != "" {
-			nestedAttrInfo["description"] = desc
-		}
-
-		nestedProperties[nestedAttrName] = nestedAttrInfo
-	}
+	nestedProperties, nestedRequired := sc.attributesInfo(blockType.Attributes())
 
 	// Add nested properties to block info
 	if len(nestedProperties) > 0 {
@@ -281,65 +255,121 @@ func (sc *SchemaConverter) processBlockTypeInfo(blockType providerschema.NestedB
 	return blockInfo
 }
 
-// convertSchemaToTypeDescription converts a providerschema.Schema to types.TypeDescription
-func (sc *SchemaConverter) convertSchemaToTypeDescription(providerName, typeName string, schema providerschema.Schema, schemaType string) *types.TypeDescription {
+// nestingNames returns the names of a nesting mode and of the type it makes
+func nestingNames(nesting providerschema.NestingMode) (string, string) {
+	switch nesting {
+	case providerschema.NestingList:
+		return "list", "list"
+	case providerschema.NestingSet:
+		return "set", "set"
+	case providerschema.NestingMap:
+		return "map", "map"
+	case providerschema.NestingSingle:
+		return "single", "object"
+	case providerschema.NestingGroup:
+		return "group", "object"
+	default:
+		return "unknown", "unknown"
+	}
+}
+
+// attributesInfo describes attributes and returns the names of the required ones
+func (sc *SchemaConverter) attributesInfo(attrs iter.Seq2[string, providerschema.Attribute]) (map[string]any, []string) {
 	properties := make(map[string]any)
 	required := []string{}
 
-	// Extract attributes from schema
-	for attrName, attr := range maps.Collect(schema.Attributes()) {
-		attrInfo := map[string]any{
-			"type": "string", // Default type
+	for attrName, attr := range attrs {
+		properties[attrName] = sc.attributeInfo(attr)
+		if attr.Usage() == providerschema.AttributeRequired {
+			required = append(required, attrName)
 		}
+	}
+	slices.Sort(required)
 
-		// Get the attribute type
-		if attrType := attr.Type(); attrType != nil {
-			if ctyType, err := attrType.AsCtyType(); err == nil {
-				attrInfo["type"] = sc.ctyTypeToString(ctyType)
-			}
-		}
+	return properties, required
+}
 
-		// Check for nested types
-		if nestedType := attr.NestedType(); nestedType != nil {
-			attrInfo["nested"] = true
-		}
+// attributeInfo describes an attribute. Nested attributes, which plugin protocol 6 providers use instead
+// of blocks, are described like blocks: their nesting and the properties of the nested objects.
+func (sc *SchemaConverter) attributeInfo(attr providerschema.Attribute) map[string]any {
+	attrInfo := map[string]any{
+		"type": "string", // Default type
+	}
 
-		// Check if attribute is required
-		usage := attr.Usage()
-		if usage == providerschema.AttributeRequired {
-			required = append(required, attrName)
-			attrInfo["required"] = true
-		} else {
-			attrInfo["required"] = false
+	// Get the attribute type
+	if attrType := attr.Type(); attrType != nil {
+		if ctyType, err := attrType.AsCtyType(); err == nil {
+			attrInfo["type"] = sc.ctyTypeToString(ctyType)
 		}
+	}
 
-		// Add usage information
-		switch usage {
-		case providerschema.AttributeRequired:
-			attrInfo["usage"] = "required"
-		case providerschema.AttributeOptional:
-			attrInfo["usage"] = "optional"
-		case providerschema.AttributeOptionalComputed:
-			attrInfo["usage"] = "optional_computed"
-		case providerschema.AttributeComputed:
-			attrInfo["usage"] = "computed"
-		default:
-			attrInfo["usage"] = "unsupported"
+	// Describe the objects of nested attributes
+	if nestedType := attr.NestedType(); nestedType != nil {
+		attrInfo["nested"] = true
+		attrInfo["nesting"], attrInfo["type"] = nestingNames(nestedType.Nesting())
+
+		nestedProperties, nestedRequired := sc.attributesInfo(nestedType.Attributes())
+		if len(nestedProperties) > 0 {
+			attrInfo["properties"] = nestedProperties
 		}
+		if len(nestedRequired) > 0 {
+			attrInfo["required_properties"] = nestedRequired
+		}
+	}
 
-		// Add sensitive and deprecated flags
-		attrInfo["sensitive"] = attr.IsSensitive()
-		attrInfo["deprecated"] = attr.IsDeprecated()
-		attrInfo["write_only"] = attr.IsWriteOnly()
+	// Check if attribute is required
+	usage := attr.Usage()
+	attrInfo["required"] = usage == providerschema.AttributeRequired
+
+	// Add usage information
+	switch usage {
+	case providerschema.AttributeRequired:
+		attrInfo["usage"] = "required"
+	case providerschema.AttributeOptional:
+		attrInfo["usage"] = "optional"
+	case providerschema.AttributeOptionalComputed:
+		attrInfo["usage"] = "optional_computed"
+	case providerschema.AttributeComputed:
+		attrInfo["usage"] = "computed"
+	default:
+		attrInfo["usage"] = "unsupported"
+	}
 
-		// Add description if available
-		if desc, _ := attr.DocDescription(); desc != "" {
-			attrInfo["description"] = desc
-		}
+	// Add sensitive and deprecated flags
+	attrInfo["sensitive"] = attr.IsSensitive()
+	attrInfo["deprecated"] = attr.IsDeprecated()
+	attrInfo["write_only"] = attr.IsWriteOnly()
+
+	// Add description if available
+	if desc, _ := attr.DocDescription(); desc != "" {
+		attrInfo["description"] = desc
+	}
+
+	return attrInfo
+}
 
-		properties[attrName] = attrInfo
+// isSensitive reports whether an attribute, or any attribute nested in it, is sensitive
+func isSensitive(attr providerschema.Attribute) bool {
+	if attr.IsSensitive() {
+		return true
 	}
 
+	if nestedType := attr.NestedType(); nestedType != nil {
+		for _, nestedAttr := range nestedType.Attributes() {
+			if isSensitive(nestedAttr) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// convertSchemaToTypeDescription converts a providerschema.Schema to types.TypeDescription
+func (sc *SchemaConverter) convertSchemaToTypeDescription(providerName, typeName string, schema providerschema.Schema, schemaType string) *types.TypeDescription {
+	// Extract attributes from schema
+	properties, required := sc.attributesInfo(schema.Attributes())
+
 	// Extract nested block types from schema (recursively)
 	for blockName, blockType := range maps.Collect(schema.NestedBlockTypes()) {
 		properties[blockName] = sc.processBlockTypeInfo(blockType)
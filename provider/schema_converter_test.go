@@ -4,8 +4,11 @@
 package provider
 
 import (
+	"iter"
+	"maps"
 	"testing"
 
+	"github.com/opentofu/provider-client/tofuprovider/providerschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -421,3 +424,147 @@ func TestSchemaConverter_IntegrationTest(t *testing.T) {
 		assert.Equal(t, "object", converter.ctyTypeToString(userType))
 	})
 }
+
+// fakeBlock is a nested block schema with only its nesting mode, item limits, attributes and nested blocks
+type fakeBlock struct {
+	providerschema.NestedBlockType
+
+	nesting  providerschema.NestingMode
+	minItems int64
+	maxItems int64
+	attrs    map[string]providerschema.Attribute
+	blocks   map[string]providerschema.NestedBlockType
+}
+
+func (b fakeBlock) Nesting() providerschema.NestingMode { return b.nesting }
+
+func (b fakeBlock) ItemLimits() (int64, int64) { return b.minItems, b.maxItems }
+
+func (b fakeBlock) Attributes() iter.Seq2[string, providerschema.Attribute] {
+	return maps.All(b.attrs)
+}
+
+func (b fakeBlock) NestedBlockTypes() iter.Seq2[string, providerschema.NestedBlockType] {
+	return maps.All(b.blocks)
+}
+
+func TestSchemaConverter_FillNestedBlocks(t *testing.T) {
+	converter := &SchemaConverter{}
+
+	ruleType := cty.Object(map[string]cty.Type{
+		"port":   cty.Number,
+		"target": cty.List(cty.Object(map[string]cty.Type{"cidr": cty.String})),
+	})
+	timeoutsType := cty.Object(map[string]cty.Type{"create": cty.String})
+	resourceType := cty.Object(map[string]cty.Type{
+		"name":     cty.String,
+		"rule":     cty.List(ruleType),
+		"tags":     cty.Set(cty.Object(map[string]cty.Type{"key": cty.String})),
+		"labels":   cty.Map(cty.Object(map[string]cty.Type{"value": cty.String})),
+		"timeouts": timeoutsType,
+		"logging":  cty.Object(map[string]cty.Type{"level": cty.String}),
+	})
+
+	schema := fakeBlock{blocks: map[string]providerschema.NestedBlockType{
+		"rule": fakeBlock{nesting: providerschema.NestingList, blocks: map[string]providerschema.NestedBlockType{
+			"target": fakeBlock{nesting: providerschema.NestingList},
+		}},
+		"tags":     fakeBlock{nesting: providerschema.NestingSet},
+		"labels":   fakeBlock{nesting: providerschema.NestingMap},
+		"timeouts": fakeBlock{nesting: providerschema.NestingSingle},
+		"logging":  fakeBlock{nesting: providerschema.NestingGroup},
+	}}
+
+	config, err := (&CtyConverter{}).MapToCtyValue(map[string]any{
+		"name": "web",
+		"rule": []any{map[string]any{"port": 80}},
+	}, resourceType)
+	assert.NoError(t, err)
+
+	filled := converter.fillNestedBlocks(schema, config)
+
+	assert.True(t, filled.Type().Equals(resourceType))
+	assert.Equal(t, cty.StringVal("web"), filled.GetAttr("name"))
+	assert.True(t, filled.GetAttr("tags").RawEquals(cty.SetValEmpty(resourceType.AttributeType("tags").ElementType())))
+	assert.True(t, filled.GetAttr("labels").RawEquals(cty.MapValEmpty(resourceType.AttributeType("labels").ElementType())))
+	assert.True(t, filled.GetAttr("timeouts").RawEquals(cty.NullVal(timeoutsType)))
+	assert.True(t, filled.GetAttr("logging").RawEquals(cty.ObjectVal(map[string]cty.Value{"level": cty.NullVal(cty.String)})))
+
+	rule := filled.GetAttr("rule").Index(cty.NumberIntVal(0))
+	assert.True(t, rule.GetAttr("port").RawEquals(cty.NumberIntVal(80)))
+	assert.True(t, rule.GetAttr("target").RawEquals(cty.ListValEmpty(ruleType.AttributeType("target").ElementType())))
+
+	// Null and unknown values are left alone
+	assert.True(t, converter.fillNestedBlocks(schema, cty.NullVal(resourceType)).RawEquals(cty.NullVal(resourceType)))
+	assert.True(t, converter.fillNestedBlocks(schema, cty.UnknownVal(resourceType)).RawEquals(cty.UnknownVal(resourceType)))
+}
+
+// fakeObjectType is the object type of a nested attribute
+type fakeObjectType struct {
+	providerschema.ObjectType // unexported methods of the sealed interface
+	nesting                   providerschema.NestingMode
+	attrs                     map[string]providerschema.Attribute
+}
+
+func (o fakeObjectType) Nesting() providerschema.NestingMode { return o.nesting }
+
+func (o fakeObjectType) Attributes() iter.Seq2[string, providerschema.Attribute] {
+	return maps.All(o.attrs)
+}
+
+func TestSchemaConverter_NestedAttributes(t *testing.T) {
+	converter := &SchemaConverter{}
+
+	ruleAttrs := map[string]providerschema.Attribute{
+		"port":     fakeAttribute{ty: cty.Number, usage: providerschema.AttributeRequired},
+		"protocol": fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptional},
+		"secret":   fakeAttribute{ty: cty.String, usage: providerschema.AttributeOptional, sensitive: true},
+	}
+	ruleType := cty.Object(map[string]cty.Type{"port": cty.Number, "protocol": cty.String, "secret": cty.String})
+
+	schema := fakeSchema{attrs: map[string]providerschema.Attribute{
+		"name":     fakeAttribute{ty: cty.String, usage: providerschema.AttributeRequired},
+		"rules":    fakeAttribute{usage: providerschema.AttributeOptional, nestedType: fakeObjectType{nesting: providerschema.NestingList, attrs: ruleAttrs}},
+		"tags":     fakeAttribute{usage: providerschema.AttributeOptional, nestedType: fakeObjectType{nesting: providerschema.NestingSet, attrs: ruleAttrs}},
+		"backends": fakeAttribute{usage: providerschema.AttributeOptional, nestedType: fakeObjectType{nesting: providerschema.NestingMap, attrs: ruleAttrs}},
+		"settings": fakeAttribute{usage: providerschema.AttributeRequired, nestedType: fakeObjectType{nesting: providerschema.NestingSingle, attrs: map[string]providerschema.Attribute{
+			"enabled": fakeAttribute{ty: cty.Bool, usage: providerschema.AttributeOptional},
+		}}},
+	}}
+
+	t.Run("types", func(t *testing.T) {
+		assert.True(t, converter.attributeType(schema.attrs["rules"]).Equals(cty.List(ruleType)))
+		assert.True(t, converter.attributeType(schema.attrs["tags"]).Equals(cty.Set(ruleType)))
+		assert.True(t, converter.attributeType(schema.attrs["backends"]).Equals(cty.Map(ruleType)))
+		assert.True(t, converter.attributeType(schema.attrs["settings"]).Equals(cty.Object(map[string]cty.Type{"enabled": cty.Bool})))
+		assert.True(t, converter.attributeType(schema.attrs["name"]).Equals(cty.String))
+	})
+
+	t.Run("descriptions", func(t *testing.T) {
+		properties, required := converter.attributesInfo(schema.Attributes())
+
+		assert.Equal(t, []string{"name", "settings"}, required)
+		assert.Equal(t, "string", properties["name"].(map[string]any)["type"])
+		assert.NotContains(t, properties["name"], "nested")
+
+		rules := properties["rules"].(map[string]any)
+		assert.Equal(t, true, rules["nested"])
+		assert.Equal(t, "list", rules["nesting"])
+		assert.Equal(t, "list", rules["type"])
+		assert.Equal(t, []string{"port"}, rules["required_properties"])
+		assert.Equal(t, "number", rules["properties"].(map[string]any)["port"].(map[string]any)["type"])
+		assert.Equal(t, true, rules["properties"].(map[string]any)["secret"].(map[string]any)["sensitive"])
+
+		settings := properties["settings"].(map[string]any)
+		assert.Equal(t, "single", settings["nesting"])
+		assert.Equal(t, "object", settings["type"])
+		assert.Equal(t, true, settings["required"])
+		assert.NotContains(t, settings, "required_properties")
+	})
+
+	t.Run("sensitive", func(t *testing.T) {
+		assert.True(t, isSensitive(schema.attrs["rules"]))
+		assert.False(t, isSensitive(schema.attrs["settings"]))
+		assert.False(t, isSensitive(schema.attrs["name"]))
+	})
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// verificationFile records, next to an extracted provider, the hashes verified when it was downloaded
+const verificationFile = "verification.json"
+
+// providerVerification is the record of a verified provider download
+type providerVerification struct {
+	ZipSHA256    string `json:"zip_sha256"`    // Hash of the downloaded zip, as listed in the signed checksums
+	BinarySHA256 string `json:"binary_sha256"` // Hash of the extracted binary, checked before reusing it
+	SigningKeyID string `json:"signing_key_id"`
+}
+
+// verifiedShasum downloads the checksums of a provider release, verifies their signature against the
+// registry's signing keys and returns the checksum of the provider zip together with the ID of the key
+// that signed it. The checksum must match the one the registry returned for the download.
+func (a *OpenTofuAdapter) verifiedShasum(ctx context.Context, info *types.DownloadInfo) (string, string, error) {
+	if info.ShasumsURL == "" || info.ShasumsSignatureURL == "" {
+		return "", "", errors.New("the registry did not return signed checksums for the provider")
+	}
+
+	shasums, err := a.downloadBytes(ctx, info.ShasumsURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	signature, err := a.downloadBytes(ctx, info.ShasumsSignatureURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download checksums signature: %w", err)
+	}
+
+	keyID, err := verifySignature(shasums, signature, info.SigningKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	shasum, err := findShasum(shasums, info.Filename)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !strings.EqualFold(shasum, info.Shasum) {
+		return "", "", fmt.Errorf("checksum mismatch: the registry returned %s for %s but the signed checksums list %s", info.Shasum, info.Filename, shasum)
+	}
+
+	return strings.ToLower(shasum), keyID, nil
+}
+
+// verifyMirrorPackage checks a package installed from a mirror against the hashes the mirror published.
+// Packages from filesystem mirrors come without hashes and are trusted like any other local file.
+func verifyMirrorPackage(zipPath string, zipShasum string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	for _, hash := range hashes {
+		scheme, value, _ := strings.Cut(hash, ":")
+		switch scheme {
+		case "zh":
+			if strings.EqualFold(value, zipShasum) {
+				return nil
+			}
+		case "h1":
+			h1, err := zipHashH1(zipPath)
+			if err != nil {
+				return fmt.Errorf("failed to hash package: %w", err)
+			}
+			if hash == h1 {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("the package does not match any of the hashes published by the mirror (%s)", strings.Join(hashes, ", "))
+}
+
+// zipHashH1 returns the h1: hash of the contents of a zip package, as recorded in OpenTofu lock files
+func zipHashH1(zipPath string) (string, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	files := slices.SortedFunc(slices.Values(r.File), func(a, b *zip.File) int { return strings.Compare(a.Name, b.Name) })
+
+	summary := sha256.New()
+	for _, f := range files {
+		if strings.Contains(f.Name, "\n") {
+			return "", fmt.Errorf("invalid file name %q", f.Name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(summary, "%x  %s\n", hash.Sum(nil), f.Name)
+	}
+
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
+
+// downloadBytes downloads a small file, such as checksums, into memory
+func (a *OpenTofuAdapter) downloadBytes(ctx context.Context, url string) ([]byte, error) {
+	resp, err := a.registry.Download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	return io.ReadAll(resp)
+}
+
+// verifySignature checks a detached GPG signature of the checksums against the signing keys and returns
+// the ID of the key that made it
+func verifySignature(shasums, signature []byte, keys []types.SigningKey) (string, error) {
+	if len(keys) == 0 {
+		return "", errors.New("the registry did not return any signing keys for the provider")
+	}
+
+	var keyring openpgp.EntityList
+	for _, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.ASCIIArmor))
+		if err != nil {
+			return "", fmt.Errorf("invalid signing key %s: %w", key.KeyID, err)
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(shasums), bytes.NewReader(signature), nil)
+	if err != nil {
+		return "", fmt.Errorf("checksums signature verification failed: %w", err)
+	}
+
+	return strings.ToUpper(signer.PrimaryKey.KeyIdString()), nil
+}
+
+// findShasum returns the checksum of a file from a SHA256SUMS file
+func findShasum(shasums []byte, filename string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(shasums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == filename {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("the signed checksums do not list %s", filename)
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 hash of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readVerification reads the verification record of a provider directory, nil if there is none
+func readVerification(dir string) (*providerVerification, error) {
+	data, err := os.ReadFile(filepath.Join(dir, verificationFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var verification providerVerification
+	if err := json.Unmarshal(data, &verification); err != nil {
+		return nil, fmt.Errorf("invalid verification record: %w", err)
+	}
+	return &verification, nil
+}
+
+// writeVerification records the hashes verified for a provider directory
+func writeVerification(dir string, verification providerVerification) error {
+	data, err := json.MarshalIndent(verification, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, verificationFile), data, 0644)
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// newSigningKey generates a GPG key and returns it together with its armored public key
+func newSigningKey(t *testing.T) (*openpgp.Entity, types.SigningKey) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("Provider Publisher", "", "publisher@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	return entity, types.SigningKey{KeyID: entity.PrimaryKey.KeyIdString(), ASCIIArmor: armored.String()}
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	signer, signingKey := newSigningKey(t)
+	_, otherKey := newSigningKey(t)
+
+	shasums := []byte("3a8b1f  terraform-provider-random_3.6.0_linux_amd64.zip\n")
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, signer, bytes.NewReader(shasums), nil))
+
+	t.Run("accepts checksums signed by a registry key", func(t *testing.T) {
+		t.Parallel()
+
+		keyID, err := verifySignature(shasums, signature.Bytes(), []types.SigningKey{otherKey, signingKey})
+		require.NoError(t, err)
+		assert.Equal(t, strings.ToUpper(signingKey.KeyID), keyID)
+	})
+
+	t.Run("rejects modified checksums", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := bytes.Replace(shasums, []byte("3a8b1f"), []byte("000000"), 1)
+		_, err := verifySignature(tampered, signature.Bytes(), []types.SigningKey{signingKey})
+		assert.ErrorContains(t, err, "checksums signature verification failed")
+	})
+
+	t.Run("rejects checksums signed by another key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := verifySignature(shasums, signature.Bytes(), []types.SigningKey{otherKey})
+		assert.ErrorContains(t, err, "checksums signature verification failed")
+	})
+
+	t.Run("requires signing keys", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := verifySignature(shasums, signature.Bytes(), nil)
+		assert.ErrorContains(t, err, "did not return any signing keys")
+	})
+}
+
+func TestFindShasum(t *testing.T) {
+	t.Parallel()
+
+	shasums := []byte("3a8b1f  terraform-provider-random_3.6.0_linux_amd64.zip\n" +
+		"9c4d2e  terraform-provider-random_3.6.0_darwin_arm64.zip\n")
+
+	shasum, err := findShasum(shasums, "terraform-provider-random_3.6.0_darwin_arm64.zip")
+	require.NoError(t, err)
+	assert.Equal(t, "9c4d2e", shasum)
+
+	_, err = findShasum(shasums, "terraform-provider-random_3.6.0_windows_amd64.zip")
+	assert.EqualError(t, err, "the signed checksums do not list terraform-provider-random_3.6.0_windows_amd64.zip")
+}
+
+func TestCachedProviderBinary(t *testing.T) {
+	t.Parallel()
+
+	adapter := &OpenTofuAdapter{}
+
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, extractedDir), 0755))
+		binaryPath := filepath.Join(dir, extractedDir, "terraform-provider-random_v3.6.0_x5")
+		require.NoError(t, os.WriteFile(binaryPath, []byte("provider"), 0755))
+		return dir, binaryPath
+	}
+
+	t.Run("reuses a verified binary", func(t *testing.T) {
+		t.Parallel()
+
+		dir, binaryPath := setup(t)
+		shasum, err := fileSHA256(binaryPath)
+		require.NoError(t, err)
+		require.NoError(t, writeVerification(dir, providerVerification{BinarySHA256: shasum}))
+
+		cached, err := adapter.cachedProviderBinary(dir)
+		require.NoError(t, err)
+		assert.Equal(t, binaryPath, cached)
+	})
+
+	t.Run("downloads binaries extracted without verification again", func(t *testing.T) {
+		t.Parallel()
+
+		dir, _ := setup(t)
+
+		cached, err := adapter.cachedProviderBinary(dir)
+		require.NoError(t, err)
+		assert.Empty(t, cached)
+	})
+
+	t.Run("refuses modified binaries", func(t *testing.T) {
+		t.Parallel()
+
+		dir, binaryPath := setup(t)
+		require.NoError(t, writeVerification(dir, providerVerification{BinarySHA256: "0000"}))
+
+		_, err := adapter.cachedProviderBinary(dir)
+		assert.ErrorContains(t, err, "cached provider binary "+binaryPath+" has SHA-256")
+	})
+}
+
+func TestVerifyMirrorPackage(t *testing.T) {
+	t.Parallel()
+
+	zipPath := writeZip(t,
+		zipEntry{name: "terraform-provider-random_v3.6.0_x5", mode: 0755, content: "provider"},
+		zipEntry{name: "LICENSE", mode: 0644, content: "MPL-2.0"},
+	)
+	zipShasum, err := fileSHA256(zipPath)
+	require.NoError(t, err)
+
+	// Hash of the file hashes listed by file name, as in OpenTofu lock files
+	h1, err := zipHashH1(zipPath)
+	require.NoError(t, err)
+	assert.Equal(t, "h1:NMDcdO0a0tyIlKR//4xr0pwwvlVXXK+b1srjq7QL+5Y=", h1)
+
+	assert.NoError(t, verifyMirrorPackage(zipPath, zipShasum, nil))
+	assert.NoError(t, verifyMirrorPackage(zipPath, zipShasum, []string{"zh:" + strings.ToUpper(zipShasum)}))
+	assert.NoError(t, verifyMirrorPackage(zipPath, zipShasum, []string{"zh:0000", h1}))
+
+	err = verifyMirrorPackage(zipPath, zipShasum, []string{"zh:0000", "h1:AAAA"})
+	assert.ErrorContains(t, err, "the package does not match any of the hashes published by the mirror")
+}
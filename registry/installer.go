@@ -0,0 +1,294 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// InstallationMethod is a source of providers, such as a mirror or the registry itself, together with
+// the providers it is used for. Patterns are provider addresses with optional wildcards, like
+// registry.opentofu.org/hashicorp/* or hashicorp/*.
+type InstallationMethod struct {
+	Source  types.RegistryClient
+	Include []string // Only use the method for these providers, all providers if empty
+	Exclude []string // Never use the method for these providers
+}
+
+// installer installs providers from the first installation method that has them, like the
+// provider_installation block of the OpenTofu CLI configuration
+type installer struct {
+	methods []InstallationMethod
+}
+
+// NewInstaller creates a client installing providers from a list of installation methods, in order
+func NewInstaller(methods []InstallationMethod) types.RegistryClient {
+	return &installer{methods: methods}
+}
+
+// GetProviderVersions lists the versions available from any installation method of the provider
+func (i *installer) GetProviderVersions(ctx context.Context, provider types.ProviderConfig) ([]types.ProviderVersionInfo, error) {
+	methods, err := i.matching(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []types.ProviderVersionInfo
+	var errs []error
+	seen := make(map[string]bool)
+	for _, method := range methods {
+		methodVersions, err := method.Source.GetProviderVersions(ctx, provider)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, version := range methodVersions {
+			if !seen[version.Version] {
+				seen[version.Version] = true
+				versions = append(versions, version)
+			}
+		}
+	}
+
+	if len(versions) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return versions, nil
+}
+
+// GetProviderDownload gets the provider package from the first installation method that has it
+func (i *installer) GetProviderDownload(ctx context.Context, provider types.ProviderConfig) (*types.DownloadInfo, error) {
+	methods, err := i.matching(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, method := range methods {
+		info, err := method.Source.GetProviderDownload(ctx, provider)
+		if err == nil {
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// Download downloads a file from the installation method it belongs to. file:// URLs are only opened
+// from filesystem mirrors, and only for files inside the mirror directory.
+func (i *installer) Download(ctx context.Context, url string) (io.ReadCloser, error) {
+	isFile := strings.HasPrefix(url, "file://")
+
+	for _, method := range i.methods {
+		mirror, isMirror := method.Source.(*filesystemMirror)
+		switch {
+		case isFile && isMirror && mirror.contains(url):
+			return mirror.Download(ctx, url)
+		case !isFile && !isMirror:
+			return method.Source.Download(ctx, url)
+		}
+	}
+
+	return nil, fmt.Errorf("no provider installation method can download %s", url)
+}
+
+// SearchProviders searches the providers of every installation method
+func (i *installer) SearchProviders(ctx context.Context, query string) ([]types.ProviderSearchResult, error) {
+	var results []types.ProviderSearchResult
+	var errs []error
+	seen := make(map[string]bool)
+	for _, method := range i.methods {
+		methodResults, err := method.Source.SearchProviders(ctx, query)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, result := range methodResults {
+			if !seen[result.Addr] && method.matches(result.Addr) {
+				seen[result.Addr] = true
+				results = append(results, result)
+			}
+		}
+	}
+
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
+
+// FindProvider finds the most popular provider of any installation method
+func (i *installer) FindProvider(ctx context.Context, query string) (*types.ProviderSearchResult, error) {
+	return findProvider(ctx, i, query)
+}
+
+// matching returns the installation methods used for a provider
+func (i *installer) matching(provider types.ProviderConfig) ([]InstallationMethod, error) {
+	var methods []InstallationMethod
+	for _, method := range i.methods {
+		if method.matches(provider.Name) {
+			methods = append(methods, method)
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no provider installation method is configured for provider %s", provider.Name)
+	}
+	return methods, nil
+}
+
+// matches reports whether the method is used for a provider, given as namespace/type
+func (m InstallationMethod) matches(provider string) bool {
+	addr := providerHostname + "/" + provider
+	if len(m.Include) > 0 && !matchesAny(m.Include, addr) {
+		return false
+	}
+	return !matchesAny(m.Exclude, addr)
+}
+
+func matchesAny(patterns []string, addr string) bool {
+	for _, pattern := range patterns {
+		if strings.Count(pattern, "/") == 1 {
+			pattern = providerHostname + "/" + pattern
+		}
+		if ok, _ := path.Match(pattern, addr); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// installationConfig is a provider installation config file. It follows the provider_installation block
+// of the OpenTofu CLI configuration, as a list of methods to keep their order, and its dev_overrides:
+//
+//	{"provider_installation": [
+//	  {"filesystem_mirror": {"path": "/usr/share/opentofu/providers", "include": ["hashicorp/*"]}},
+//	  {"network_mirror": {"url": "https://mirror.example.com/providers/"}},
+//	  {"direct": {"exclude": ["hashicorp/*"]}}
+//	],
+//	"dev_overrides": {"acme/internal": "/home/developer/go/bin"}}
+type installationConfig struct {
+	DevOverrides         types.DevOverrides `json:"dev_overrides"`
+	ProviderInstallation []struct {
+		FilesystemMirror *struct {
+			Path string `json:"path"`
+			installationPatterns
+		} `json:"filesystem_mirror"`
+		NetworkMirror *struct {
+			URL string `json:"url"`
+			installationPatterns
+		} `json:"network_mirror"`
+		Direct *installationPatterns `json:"direct"`
+	} `json:"provider_installation"`
+}
+
+type installationPatterns struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// LoadInstallationConfig reads the installation methods and development overrides of a provider
+// installation config file, direct is the registry client used for direct installation. Without
+// installation methods providers are installed directly.
+func LoadInstallationConfig(configPath string, direct types.RegistryClient) ([]InstallationMethod, types.DevOverrides, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read provider installation config: %w", err)
+	}
+
+	var config installationConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("invalid provider installation config %s: %w", configPath, err)
+	}
+
+	if len(config.ProviderInstallation) == 0 && len(config.DevOverrides) == 0 {
+		return nil, nil, fmt.Errorf("invalid provider installation config %s: no installation methods or dev_overrides", configPath)
+	}
+
+	for provider, override := range config.DevOverrides {
+		if strings.Count(provider, "/") != 1 || override == "" {
+			return nil, nil, fmt.Errorf("invalid provider installation config %s: dev_overrides must map namespace/type to a path, got %q: %q", configPath, provider, override)
+		}
+	}
+
+	if len(config.ProviderInstallation) == 0 {
+		return []InstallationMethod{{Source: direct}}, config.DevOverrides, nil
+	}
+
+	methods := make([]InstallationMethod, 0, len(config.ProviderInstallation))
+	for n, entry := range config.ProviderInstallation {
+		var method InstallationMethod
+		var kinds int
+
+		if mirror := entry.FilesystemMirror; mirror != nil {
+			if mirror.Path == "" {
+				return nil, nil, fmt.Errorf("invalid provider installation config %s: filesystem_mirror %d has no path", configPath, n)
+			}
+			method = InstallationMethod{Source: NewFilesystemMirror(mirror.Path), Include: mirror.Include, Exclude: mirror.Exclude}
+			kinds++
+		}
+		if mirror := entry.NetworkMirror; mirror != nil {
+			source, err := NewNetworkMirror(mirror.URL)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid provider installation config %s: %w", configPath, err)
+			}
+			method = InstallationMethod{Source: source, Include: mirror.Include, Exclude: mirror.Exclude}
+			kinds++
+		}
+		if patterns := entry.Direct; patterns != nil {
+			method = InstallationMethod{Source: direct, Include: patterns.Include, Exclude: patterns.Exclude}
+			kinds++
+		}
+
+		if kinds != 1 {
+			return nil, nil, fmt.Errorf("invalid provider installation config %s: entry %d must have exactly one of filesystem_mirror, network_mirror or direct", configPath, n)
+		}
+		methods = append(methods, method)
+	}
+
+	return methods, config.DevOverrides, nil
+}
+
+// compareVersions compares two semantic versions, pre-releases sort before their release
+func compareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	aParts, bParts := strings.Split(aCore, "."), strings.Split(bCore, ".")
+	for n := 0; n < max(len(aParts), len(bParts)); n++ {
+		var aNum, bNum int
+		if n < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[n])
+		}
+		if n < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[n])
+		}
+		if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return strings.Compare(aPre, bPre)
+}
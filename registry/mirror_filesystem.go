@@ -0,0 +1,221 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// providerHostname is the hostname of provider addresses in mirrors, providers are installed from the
+// OpenTofu registry
+const providerHostname = "registry.opentofu.org"
+
+// filesystemMirror installs providers from a local directory laid out like an OpenTofu filesystem mirror,
+// as created by `tofu providers mirror`. Both layouts are supported:
+//   - packed: HOSTNAME/NAMESPACE/TYPE/terraform-provider-TYPE_VERSION_OS_ARCH.zip
+//   - unpacked: HOSTNAME/NAMESPACE/TYPE/VERSION/OS_ARCH/, containing the provider binary
+type filesystemMirror struct {
+	dir string
+}
+
+// NewFilesystemMirror creates a client installing providers from a filesystem mirror directory
+func NewFilesystemMirror(dir string) types.RegistryClient {
+	return &filesystemMirror{dir: dir}
+}
+
+func (m *filesystemMirror) String() string {
+	return "filesystem mirror " + m.dir
+}
+
+// GetProviderVersions lists the versions of a provider available in the mirror for the current platform
+func (m *filesystemMirror) GetProviderVersions(_ context.Context, provider types.ProviderConfig) ([]types.ProviderVersionInfo, error) {
+	namespace, name, err := provider.NamespacedName()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := m.versions(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("provider %s not found in %s", provider.Name, m)
+	}
+
+	result := make([]types.ProviderVersionInfo, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, types.ProviderVersionInfo{
+			Version:   version,
+			Platforms: []types.ProviderPlatform{{OS: runtime.GOOS, Arch: runtime.GOARCH}},
+		})
+	}
+	return result, nil
+}
+
+// GetProviderDownload finds a provider package in the mirror, preferring an unpacked one
+func (m *filesystemMirror) GetProviderDownload(_ context.Context, provider types.ProviderConfig) (*types.DownloadInfo, error) {
+	namespace, name, version, err := provider.Parse()
+	if err != nil {
+		return nil, err
+	}
+	version = strings.TrimPrefix(version, "v")
+
+	providerDir := filepath.Join(m.dir, providerHostname, namespace, name)
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+
+	unpackedDir := filepath.Join(providerDir, version, platform)
+	if binaryPath, err := findBinary(unpackedDir, name); err == nil {
+		return &types.DownloadInfo{Version: version, Mirror: m.String(), BinaryPath: binaryPath}, nil
+	}
+
+	filename := fmt.Sprintf("terraform-provider-%s_%s_%s.zip", name, version, platform)
+	packagePath := filepath.Join(providerDir, filename)
+	if _, err := os.Stat(packagePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("provider %s@%s for %s not found in %s", provider.Name, version, platform, m)
+		}
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DownloadInfo{
+		DownloadURL: "file://" + filepath.ToSlash(absPath),
+		Filename:    filename,
+		Version:     version,
+		Mirror:      m.String(),
+	}, nil
+}
+
+// Download opens a package of the mirror
+func (m *filesystemMirror) Download(_ context.Context, url string) (io.ReadCloser, error) {
+	if !m.contains(url) {
+		return nil, fmt.Errorf("%s cannot open %s", m, url)
+	}
+	return os.Open(filepath.FromSlash(strings.TrimPrefix(url, "file://")))
+}
+
+// contains reports whether a URL is the file:// URL of a file in the mirror
+func (m *filesystemMirror) contains(url string) bool {
+	path, ok := strings.CutPrefix(url, "file://")
+	if !ok {
+		return false
+	}
+
+	dir, err := filepath.Abs(m.dir)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(dir, filepath.FromSlash(path))
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// SearchProviders finds the providers of the mirror whose address contains the query
+func (m *filesystemMirror) SearchProviders(_ context.Context, query string) ([]types.ProviderSearchResult, error) {
+	namespaces, err := os.ReadDir(filepath.Join(m.dir, providerHostname))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var results []types.ProviderSearchResult
+	for _, namespace := range namespaces {
+		names, err := os.ReadDir(filepath.Join(m.dir, providerHostname, namespace.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, name := range names {
+			addr := namespace.Name() + "/" + name.Name()
+			if !strings.Contains(addr, query) {
+				continue
+			}
+
+			versions, err := m.versions(namespace.Name(), name.Name())
+			if err != nil || len(versions) == 0 {
+				continue
+			}
+
+			results = append(results, types.ProviderSearchResult{
+				ID:      addr,
+				Type:    "provider",
+				Addr:    addr,
+				Version: slices.MaxFunc(versions, compareVersions),
+				Title:   name.Name(),
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// FindProvider finds a provider of the mirror
+func (m *filesystemMirror) FindProvider(ctx context.Context, query string) (*types.ProviderSearchResult, error) {
+	return findProvider(ctx, m, query)
+}
+
+// versions lists the versions of a provider available in the mirror for the current platform
+func (m *filesystemMirror) versions(namespace, name string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.dir, providerHostname, namespace, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	prefix := "terraform-provider-" + name + "_"
+	suffix := "_" + platform + ".zip"
+
+	var versions []string
+	for _, entry := range entries {
+		var version string
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(m.dir, providerHostname, namespace, name, entry.Name(), platform)); err != nil {
+				continue
+			}
+			version = entry.Name()
+		} else if strings.HasPrefix(entry.Name(), prefix) && strings.HasSuffix(entry.Name(), suffix) {
+			version = strings.TrimSuffix(strings.TrimPrefix(entry.Name(), prefix), suffix)
+		}
+
+		if version != "" && !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, nil
+}
+
+// findBinary finds the provider binary of an unpacked package
+func findBinary(dir, name string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasPrefix(entry.Name(), "terraform-provider-"+name) {
+			return filepath.Abs(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return "", fmt.Errorf("no provider binary in %s", dir)
+}
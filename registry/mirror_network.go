@@ -0,0 +1,155 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// networkMirror installs providers from a server implementing the OpenTofu provider network mirror protocol
+type networkMirror struct {
+	client  *http.Client
+	baseURL *url.URL
+}
+
+// NewNetworkMirror creates a client installing providers from a provider network mirror
+func NewNetworkMirror(baseURL string) (types.RegistryClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network mirror URL: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("invalid network mirror URL %s: must be an http or https URL", baseURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	return &networkMirror{client: &http.Client{}, baseURL: u}, nil
+}
+
+func (m *networkMirror) String() string {
+	return "network mirror " + m.baseURL.String()
+}
+
+// GetProviderVersions lists the versions of a provider available in the mirror
+func (m *networkMirror) GetProviderVersions(ctx context.Context, provider types.ProviderConfig) ([]types.ProviderVersionInfo, error) {
+	namespace, name, err := provider.NamespacedName()
+	if err != nil {
+		return nil, err
+	}
+
+	var index mirrorIndexResponse
+	indexURL := m.providerURL(namespace, name, "index.json")
+	if err := m.get(ctx, indexURL, &index); err != nil {
+		return nil, fmt.Errorf("failed to get provider %s versions from %s: %w", provider.Name, m, err)
+	}
+
+	if len(index.Versions) == 0 {
+		return nil, fmt.Errorf("no versions of provider %s available in %s", provider.Name, m)
+	}
+
+	versions := make([]types.ProviderVersionInfo, 0, len(index.Versions))
+	for version := range index.Versions {
+		versions = append(versions, types.ProviderVersionInfo{Version: version})
+	}
+	return versions, nil
+}
+
+// GetProviderDownload gets the package of a provider version for the current platform
+func (m *networkMirror) GetProviderDownload(ctx context.Context, provider types.ProviderConfig) (*types.DownloadInfo, error) {
+	namespace, name, version, err := provider.Parse()
+	if err != nil {
+		return nil, err
+	}
+	version = strings.TrimPrefix(version, "v")
+
+	var archives mirrorVersionResponse
+	versionURL := m.providerURL(namespace, name, version+".json")
+	if err := m.get(ctx, versionURL, &archives); err != nil {
+		return nil, fmt.Errorf("failed to get provider %s@%s from %s: %w", provider.Name, version, m, err)
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	archive, ok := archives.Archives[platform]
+	if !ok {
+		return nil, fmt.Errorf("provider %s@%s for %s not available in %s", provider.Name, version, platform, m)
+	}
+
+	// Without hashes nothing guarantees the package is the one that was mirrored
+	if len(archive.Hashes) == 0 {
+		return nil, fmt.Errorf("%s did not return any hashes for provider %s@%s", m, provider.Name, version)
+	}
+
+	// Archive URLs are relative to the version document
+	downloadURL, err := versionURL.Parse(archive.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid package URL %s from %s: %w", archive.URL, m, err)
+	}
+
+	return &types.DownloadInfo{
+		DownloadURL: downloadURL.String(),
+		Filename:    path.Base(downloadURL.Path),
+		Version:     version,
+		Mirror:      m.String(),
+		Hashes:      archive.Hashes,
+	}, nil
+}
+
+// Download downloads a package of the mirror
+func (m *networkMirror) Download(ctx context.Context, url string) (io.ReadCloser, error) {
+	return download(ctx, m.client, url)
+}
+
+// SearchProviders returns no results, the network mirror protocol has no way of listing providers
+func (m *networkMirror) SearchProviders(context.Context, string) ([]types.ProviderSearchResult, error) {
+	return nil, nil
+}
+
+// FindProvider returns no provider, the network mirror protocol has no way of listing providers
+func (m *networkMirror) FindProvider(context.Context, string) (*types.ProviderSearchResult, error) {
+	return nil, nil
+}
+
+// providerURL returns the URL of a document of a provider, e.g. index.json
+func (m *networkMirror) providerURL(namespace, name, document string) *url.URL {
+	return m.baseURL.JoinPath(providerHostname, namespace, name, document)
+}
+
+// get fetches and decodes a JSON document of the mirror
+func (m *networkMirror) get(ctx context.Context, u *url.URL, v any) error {
+	body, err := download(ctx, m.client, u.String())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", u, err)
+	}
+	return nil
+}
+
+// mirrorIndexResponse represents the network mirror response listing the versions of a provider
+type mirrorIndexResponse struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+// mirrorVersionResponse represents the network mirror response listing the packages of a provider version
+type mirrorVersionResponse struct {
+	Archives map[string]struct {
+		URL    string   `json:"url"`
+		Hashes []string `json:"hashes"`
+	} `json:"archives"`
+}
@@ -0,0 +1,333 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+var platform = runtime.GOOS + "_" + runtime.GOARCH
+
+// writeMirrorFile creates a file of a filesystem mirror
+func writeMirrorFile(t *testing.T, dir string, elem ...string) string {
+	t.Helper()
+
+	path := filepath.Join(append([]string{dir, providerHostname}, elem...)...)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("package"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFilesystemMirror_Packed(t *testing.T) {
+	dir := t.TempDir()
+	writeMirrorFile(t, dir, "hashicorp", "random", "terraform-provider-random_3.6.0_"+platform+".zip")
+	writeMirrorFile(t, dir, "hashicorp", "random", "terraform-provider-random_3.5.0_plan9_mips.zip")
+
+	mirror := NewFilesystemMirror(dir)
+	ctx := context.Background()
+
+	versions, err := mirror.GetProviderVersions(ctx, types.ProviderConfig{Name: "hashicorp/random"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != "3.6.0" {
+		t.Errorf("expected only version 3.6.0 for the current platform, got %+v", versions)
+	}
+
+	info, err := mirror.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "3.6.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.BinaryPath != "" {
+		t.Errorf("expected a packed package, got binary %q", info.BinaryPath)
+	}
+	if !strings.HasPrefix(info.DownloadURL, "file://") || info.Mirror == "" {
+		t.Errorf("unexpected download info %+v", info)
+	}
+
+	body, err := mirror.Download(ctx, info.DownloadURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer body.Close()
+	if content, _ := io.ReadAll(body); string(content) != "package" {
+		t.Errorf("unexpected package content %q", content)
+	}
+
+	if _, err := mirror.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "3.5.0"}); err == nil {
+		t.Error("expected an error for a version not mirrored for the current platform")
+	}
+}
+
+func TestFilesystemMirror_Unpacked(t *testing.T) {
+	dir := t.TempDir()
+	binary := writeMirrorFile(t, dir, "hashicorp", "random", "3.6.0", platform, "terraform-provider-random_v3.6.0_x5")
+
+	mirror := NewFilesystemMirror(dir)
+
+	info, err := mirror.GetProviderDownload(context.Background(), types.ProviderConfig{Name: "hashicorp/random", Version: "v3.6.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.BinaryPath != binary {
+		t.Errorf("expected binary %q, got %q", binary, info.BinaryPath)
+	}
+
+	results, err := mirror.SearchProviders(context.Background(), "random")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Addr != "hashicorp/random" || results[0].Version != "3.6.0" {
+		t.Errorf("unexpected search results %+v", results)
+	}
+}
+
+func TestFilesystemMirror_DownloadOutsideMirror(t *testing.T) {
+	dir := t.TempDir()
+	outside := writeMirrorFile(t, t.TempDir(), "hashicorp", "random", "terraform-provider-random_3.6.0_"+platform+".zip")
+
+	mirror := NewFilesystemMirror(dir)
+	for _, url := range []string{"file://" + filepath.ToSlash(outside), "file://" + filepath.ToSlash(dir) + "/../secret", "https://example.com/provider.zip"} {
+		if _, err := mirror.Download(context.Background(), url); err == nil {
+			t.Errorf("expected an error opening %s", url)
+		}
+	}
+}
+
+func TestNetworkMirror(t *testing.T) {
+	var hashes string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/providers/registry.opentofu.org/hashicorp/random/index.json":
+			w.Write([]byte(`{"versions": {"3.5.0": {}, "3.6.0": {}}}`))
+		case "/providers/registry.opentofu.org/hashicorp/random/3.6.0.json":
+			fmt.Fprintf(w, `{"archives": {%q: {"url": "terraform-provider-random_3.6.0_%s.zip", "hashes": %s}}}`, platform, platform, hashes)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mirror, err := NewNetworkMirror(server.URL + "/providers")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	versions, err := mirror.GetProviderVersions(ctx, types.ProviderConfig{Name: "hashicorp/random"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("expected 2 versions, got %+v", versions)
+	}
+
+	hashes = `["h1:AAAA", "zh:abcd"]`
+	info, err := mirror.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "3.6.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedURL := server.URL + "/providers/registry.opentofu.org/hashicorp/random/terraform-provider-random_3.6.0_" + platform + ".zip"
+	if info.DownloadURL != expectedURL {
+		t.Errorf("expected download URL %q, got %q", expectedURL, info.DownloadURL)
+	}
+	if len(info.Hashes) != 2 || info.Mirror == "" {
+		t.Errorf("unexpected download info %+v", info)
+	}
+
+	hashes = `[]`
+	if _, err := mirror.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "3.6.0"}); err == nil {
+		t.Error("expected an error for a package without hashes")
+	}
+
+	if _, err := mirror.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "3.5.0"}); err == nil {
+		t.Error("expected an error for a version the mirror does not serve")
+	}
+
+	if _, err := NewNetworkMirror("file:///usr/share/providers"); err == nil {
+		t.Error("expected an error for a non-http mirror URL")
+	}
+}
+
+// fakeSource is an installation method serving fixed versions
+type fakeSource struct {
+	types.RegistryClient
+	name     string
+	versions []string
+}
+
+func (f *fakeSource) GetProviderVersions(context.Context, types.ProviderConfig) ([]types.ProviderVersionInfo, error) {
+	if len(f.versions) == 0 {
+		return nil, fmt.Errorf("not found in %s", f.name)
+	}
+
+	var versions []types.ProviderVersionInfo
+	for _, version := range f.versions {
+		versions = append(versions, types.ProviderVersionInfo{Version: version})
+	}
+	return versions, nil
+}
+
+func (f *fakeSource) GetProviderDownload(_ context.Context, provider types.ProviderConfig) (*types.DownloadInfo, error) {
+	for _, version := range f.versions {
+		if version == provider.Version {
+			return &types.DownloadInfo{Version: version, Mirror: f.name}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s@%s not found in %s", provider.Name, provider.Version, f.name)
+}
+
+func (f *fakeSource) Download(context.Context, string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.name)), nil
+}
+
+func TestInstaller(t *testing.T) {
+	mirror := &fakeSource{name: "mirror", versions: []string{"3.6.0"}}
+	direct := &fakeSource{name: "direct", versions: []string{"3.5.0", "3.6.0"}}
+	installer := NewInstaller([]InstallationMethod{
+		{Source: mirror, Include: []string{"hashicorp/*"}, Exclude: []string{"registry.opentofu.org/hashicorp/aws"}},
+		{Source: direct},
+	})
+	ctx := context.Background()
+
+	versions, err := installer.GetProviderVersions(ctx, types.ProviderConfig{Name: "hashicorp/random"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("expected the versions of both methods, got %+v", versions)
+	}
+
+	cases := []struct {
+		provider types.ProviderConfig
+		method   string
+	}{
+		{types.ProviderConfig{Name: "hashicorp/random", Version: "3.6.0"}, "mirror"},
+		{types.ProviderConfig{Name: "hashicorp/random", Version: "3.5.0"}, "direct"},
+		{types.ProviderConfig{Name: "hashicorp/aws", Version: "3.6.0"}, "direct"},
+		{types.ProviderConfig{Name: "integrations/github", Version: "3.6.0"}, "direct"},
+	}
+	for _, tc := range cases {
+		info, err := installer.GetProviderDownload(ctx, tc.provider)
+		if err != nil {
+			t.Fatalf("unexpected error for %s@%s: %v", tc.provider.Name, tc.provider.Version, err)
+		}
+		if info.Mirror != tc.method {
+			t.Errorf("expected %s@%s from %s, got %s", tc.provider.Name, tc.provider.Version, tc.method, info.Mirror)
+		}
+	}
+
+	if _, err := installer.GetProviderDownload(ctx, types.ProviderConfig{Name: "hashicorp/random", Version: "1.0.0"}); err == nil {
+		t.Error("expected an error for a version no method has")
+	}
+}
+
+func TestInstaller_Download(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMirrorFile(t, dir, "hashicorp", "random", "terraform-provider-random_3.6.0_"+platform+".zip")
+
+	installer := NewInstaller([]InstallationMethod{
+		{Source: NewFilesystemMirror(dir)},
+		{Source: &fakeSource{name: "direct"}},
+	})
+
+	cases := map[string]string{
+		"file://" + filepath.ToSlash(path): "package",
+		"https://example.com/provider.zip": "direct",
+	}
+	for url, expected := range cases {
+		body, err := installer.Download(context.Background(), url)
+		if err != nil {
+			t.Fatalf("unexpected error downloading %s: %v", url, err)
+		}
+		content, _ := io.ReadAll(body)
+		body.Close()
+		if string(content) != expected {
+			t.Errorf("expected %s to be downloaded from %q, got %q", url, expected, content)
+		}
+	}
+
+	if _, err := installer.Download(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("expected an error for a file outside of the filesystem mirrors")
+	}
+}
+
+func TestLoadInstallationConfig(t *testing.T) {
+	dir := t.TempDir()
+	direct := &fakeSource{name: "direct"}
+
+	write := func(content string) string {
+		path := filepath.Join(dir, "installation.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	methods, overrides, err := LoadInstallationConfig(write(`{"provider_installation": [
+		{"filesystem_mirror": {"path": "/usr/share/opentofu/providers", "include": ["hashicorp/*"]}},
+		{"network_mirror": {"url": "https://mirror.example.com/providers/"}},
+		{"direct": {"exclude": ["hashicorp/*"]}}
+	]}`), direct)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(methods) != 3 {
+		t.Fatalf("expected 3 methods, got %d", len(methods))
+	}
+	if _, ok := methods[0].Source.(*filesystemMirror); !ok || len(methods[0].Include) != 1 {
+		t.Errorf("unexpected first method %+v", methods[0])
+	}
+	if _, ok := methods[1].Source.(*networkMirror); !ok {
+		t.Errorf("unexpected second method %+v", methods[1])
+	}
+	if methods[2].Source != direct || len(methods[2].Exclude) != 1 {
+		t.Errorf("unexpected third method %+v", methods[2])
+	}
+	if len(overrides) != 0 {
+		t.Errorf("expected no dev overrides, got %v", overrides)
+	}
+
+	// Development overrides alone install every other provider directly
+	methods, overrides, err = LoadInstallationConfig(write(`{"dev_overrides": {"acme/internal": "/home/developer/go/bin"}}`), direct)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(methods) != 1 || methods[0].Source != direct {
+		t.Errorf("expected direct installation, got %+v", methods)
+	}
+	if overrides["acme/internal"] != "/home/developer/go/bin" {
+		t.Errorf("unexpected dev overrides %v", overrides)
+	}
+
+	invalid := []string{
+		`{"provider_installation": []}`,
+		`{"provider_installation": [{}]}`,
+		`{"provider_installation": [{"filesystem_mirror": {}}]}`,
+		`{"provider_installation": [{"network_mirror": {"url": "ftp://mirror.example.com"}}]}`,
+		`{"provider_installation": [{"direct": {}, "filesystem_mirror": {"path": "/tmp"}}]}`,
+		`{"dev_overrides": {"internal": "/home/developer/go/bin"}}`,
+		`{"dev_overrides": {"acme/internal": ""}}`,
+	}
+	for _, content := range invalid {
+		if _, _, err := LoadInstallationConfig(write(content), direct); err == nil {
+			t.Errorf("expected an error for %s", content)
+		}
+	}
+}
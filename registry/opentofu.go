@@ -98,10 +98,15 @@ func (c *openTofuClient) SearchProviders(ctx context.Context, query string) ([]t
 
 // FindProvider finds the most popular provider for a given query
 func (c *openTofuClient) FindProvider(ctx context.Context, query string) (*types.ProviderSearchResult, error) {
+	return findProvider(ctx, c, query)
+}
+
+// findProvider finds the most popular provider a client finds for a given query
+func findProvider(ctx context.Context, client types.RegistryClient, query string) (*types.ProviderSearchResult, error) {
 	// TODO: Implement more sophisticated provider ranking beyond just popularity
 	// TODO: Add caching for provider search results to improve performance
 	// TODO: Consider fuzzy matching for provider names
-	results, err := c.SearchProviders(ctx, query)
+	results, err := client.SearchProviders(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search providers: %w", err)
 	}
@@ -175,32 +180,24 @@ func (c *openTofuClient) GetProviderDownload(ctx context.Context, provider types
 		return nil, fmt.Errorf("failed to decode download response: %w", err)
 	}
 
-	return &types.DownloadInfo{
-		DownloadURL: download.DownloadURL,
-		Shasum:      download.Shasum,
-		Version:     version,
-	}, nil
-}
-
-// Download downloads a file from the given URL
-func (c *openTofuClient) Download(ctx context.Context, url string) (io.ReadCloser, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create download request: %w", err)
+	info := &types.DownloadInfo{
+		DownloadURL:         download.DownloadURL,
+		Filename:            download.Filename,
+		Shasum:              download.Shasum,
+		ShasumsURL:          download.ShasumsURL,
+		ShasumsSignatureURL: download.ShasumsSignatureURL,
+		Version:             version,
 	}
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download: %w", err)
+	for _, key := range download.SigningKeys.GPGPublicKeys {
+		info.SigningKeys = append(info.SigningKeys, types.SigningKey{KeyID: key.KeyID, ASCIIArmor: key.ASCIIArmor})
 	}
 
-	if resp.StatusCode != 200 {
-		resp.Body.Close()
-		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
+	return info, nil
+}
 
-	return resp.Body, nil
+// Download downloads a file from the given URL
+func (c *openTofuClient) Download(ctx context.Context, url string) (io.ReadCloser, error) {
+	return download(ctx, c.client, url)
 }
 
 // getProviderVersions gets available versions for a provider
@@ -240,6 +237,15 @@ type versionsResponse struct {
 
 // downloadResponse represents the registry response for download information
 type downloadResponse struct {
-	DownloadURL string `json:"download_url"`
-	Shasum      string `json:"shasum"`
+	DownloadURL         string `json:"download_url"`
+	Filename            string `json:"filename"`
+	Shasum              string `json:"shasum"`
+	ShasumsURL          string `json:"shasums_url"`
+	ShasumsSignatureURL string `json:"shasums_signature_url"`
+	SigningKeys         struct {
+		GPGPublicKeys []struct {
+			KeyID      string `json:"key_id"`
+			ASCIIArmor string `json:"ascii_armor"`
+		} `json:"gpg_public_keys"`
+	} `json:"signing_keys"`
 }
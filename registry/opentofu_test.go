@@ -4,8 +4,13 @@
 package registry
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+
+	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 func TestNewOpenTofuClient_DefaultURLs(t *testing.T) {
@@ -144,3 +149,34 @@ func TestNewOpenTofuClient_EmptyEnvVars(t *testing.T) {
 		t.Errorf("expected versionsURLTemplate %q, got %q", expectedVersionsURL, otfClient.versionsURLTemplate)
 	}
 }
+
+func TestGetProviderDownload_SigningKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"download_url": "https://example.com/terraform-provider-random_3.6.0_linux_amd64.zip",
+			"filename": "terraform-provider-random_3.6.0_linux_amd64.zip",
+			"shasum": "3a8b1f",
+			"shasums_url": "https://example.com/terraform-provider-random_3.6.0_SHA256SUMS",
+			"shasums_signature_url": "https://example.com/terraform-provider-random_3.6.0_SHA256SUMS.sig",
+			"signing_keys": {"gpg_public_keys": [{"key_id": "34365D9472D7468F", "ascii_armor": "-----BEGIN PGP PUBLIC KEY BLOCK-----"}]}
+		}`))
+	}))
+	defer server.Close()
+
+	client := &openTofuClient{client: server.Client(), downloadURLTemplate: server.URL + downloadTemplate}
+
+	info, err := client.GetProviderDownload(context.Background(), types.ProviderConfig{Name: "hashicorp/random", Version: "3.6.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Filename != "terraform-provider-random_3.6.0_linux_amd64.zip" {
+		t.Errorf("unexpected filename %q", info.Filename)
+	}
+	if info.ShasumsSignatureURL != "https://example.com/terraform-provider-random_3.6.0_SHA256SUMS.sig" {
+		t.Errorf("unexpected shasums signature URL %q", info.ShasumsSignatureURL)
+	}
+	if len(info.SigningKeys) != 1 || info.SigningKeys[0].KeyID != "34365D9472D7468F" {
+		t.Errorf("unexpected signing keys %+v", info.SigningKeys)
+	}
+}
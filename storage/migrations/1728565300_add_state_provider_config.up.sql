@@ -0,0 +1 @@
+ALTER TABLE state_records ADD COLUMN provider_config TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_state_provider_alias;
+
+ALTER TABLE state_records DROP COLUMN provider_alias;
+
+DROP TABLE IF EXISTS provider_configs;
@@ -0,0 +1,12 @@
+-- Named provider configurations table
+CREATE TABLE IF NOT EXISTS provider_configs (
+	alias TEXT PRIMARY KEY,
+	provider TEXT NOT NULL,
+	config TEXT NOT NULL DEFAULT '{}',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE state_records ADD COLUMN provider_alias TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_state_provider_alias ON state_records(provider_alias);
@@ -0,0 +1 @@
+ALTER TABLE state_records DROP COLUMN private;
@@ -0,0 +1 @@
+ALTER TABLE state_records ADD COLUMN private BLOB;
@@ -0,0 +1,3 @@
+ALTER TABLE operations DROP COLUMN previous_provider_version;
+
+ALTER TABLE timeline_events DROP COLUMN details;
@@ -0,0 +1,3 @@
+ALTER TABLE timeline_events ADD COLUMN details TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE operations ADD COLUMN previous_provider_version TEXT NOT NULL DEFAULT '';
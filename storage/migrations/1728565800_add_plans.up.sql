@@ -0,0 +1,19 @@
+-- Saved plans applied by create, update and delete via plan_id
+CREATE TABLE IF NOT EXISTS plans (
+	id TEXT PRIMARY KEY,
+	resource_id TEXT NOT NULL,
+	operation TEXT NOT NULL,
+	provider TEXT NOT NULL,
+	provider_version TEXT NOT NULL,
+	resource_type TEXT NOT NULL,
+	provider_config TEXT NOT NULL DEFAULT '',
+	provider_alias TEXT NOT NULL DEFAULT '',
+	config TEXT NOT NULL DEFAULT '',
+	prior_state_hash TEXT NOT NULL DEFAULT '',
+	planned_state TEXT NOT NULL DEFAULT '',
+	planned_private BLOB,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_plans_expires_at ON plans(expires_at);
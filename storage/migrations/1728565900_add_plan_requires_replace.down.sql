@@ -0,0 +1 @@
+ALTER TABLE plans DROP COLUMN requires_replace;
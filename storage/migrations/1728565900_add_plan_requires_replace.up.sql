@@ -0,0 +1 @@
+ALTER TABLE plans ADD COLUMN requires_replace TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE operations DROP COLUMN previous_provider;
+ALTER TABLE operations DROP COLUMN previous_resource_type;
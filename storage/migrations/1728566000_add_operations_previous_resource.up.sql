@@ -0,0 +1,2 @@
+ALTER TABLE operations ADD COLUMN previous_resource_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE operations ADD COLUMN previous_provider TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE state_records ADD COLUMN dev_override BOOLEAN NOT NULL DEFAULT FALSE;
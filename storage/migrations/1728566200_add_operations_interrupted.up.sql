@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN interrupted BOOLEAN NOT NULL DEFAULT FALSE;
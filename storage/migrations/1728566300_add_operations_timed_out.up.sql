@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN timed_out BOOLEAN NOT NULL DEFAULT FALSE;
@@ -49,13 +49,33 @@ func (s *SQLiteStorage) SaveState(ctx context.Context, record types.StateRecord)
 		return fmt.Errorf("failed to serialize state: %w", err)
 	}
 
-	// Save the state
+	// Serialize provider config to JSON, empty when the default configuration is used
+	var providerConfigJSON []byte
+	if len(record.ProviderConfig) > 0 {
+		providerConfigJSON, err = json.Marshal(record.ProviderConfig)
+		if err != nil {
+			return fmt.Errorf("failed to serialize provider config: %w", err)
+		}
+	}
+
+	// Save the state. An upsert rather than INSERT OR REPLACE keeps the original creation time and does
+	// not delete the row, which would cascade to the dependency edges of the resource.
 	query := `
-	INSERT OR REPLACE INTO state_records (id, provider, provider_version, resource_type, state, created_at)
-	VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	INSERT INTO state_records (id, provider, provider_version, resource_type, state, provider_config, provider_alias, private, schema_version, dev_override, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	ON CONFLICT(id) DO UPDATE SET
+		provider = excluded.provider,
+		provider_version = excluded.provider_version,
+		resource_type = excluded.resource_type,
+		state = excluded.state,
+		provider_config = excluded.provider_config,
+		provider_alias = excluded.provider_alias,
+		private = excluded.private,
+		schema_version = excluded.schema_version,
+		dev_override = excluded.dev_override
 	`
 
-	_, err = s.db.ExecContext(ctx, query, record.ResourceID, record.Provider, record.ProviderVersion, record.ResourceType, string(stateJSON))
+	_, err = s.db.ExecContext(ctx, query, record.ResourceID, record.Provider, record.ProviderVersion, record.ResourceType, string(stateJSON), string(providerConfigJSON), record.ProviderAlias, record.Private, record.SchemaVersion, record.DevOverride)
 	if err != nil {
 		return err
 	}
@@ -70,6 +90,10 @@ func (s *SQLiteStorage) SaveState(ctx context.Context, record types.StateRecord)
 				CreatedAt:  s.getCurrentTimestamp(),
 			}
 
+			if details, ok := ctx.Value(types.DetailsContextKey).(map[string]any); ok {
+				event.Details = details
+			}
+
 			s.addTimelineEvent(ctx, event) // Use internal method to avoid circular calls
 		}
 	}
@@ -80,16 +104,14 @@ func (s *SQLiteStorage) SaveState(ctx context.Context, record types.StateRecord)
 // GetState retrieves a state record by ID
 func (s *SQLiteStorage) GetState(ctx context.Context, id string) (*types.StateRecord, error) {
 	query := `
-	SELECT id, provider, provider_version, resource_type, state, created_at
+	SELECT id, provider, provider_version, resource_type, state, provider_config, provider_alias, private, schema_version, dev_override, created_at
 	FROM state_records
 	WHERE id = ?
 	`
 
 	row := s.db.QueryRowContext(ctx, query, id)
 
-	var record types.StateRecord
-	var stateJSON string
-	err := row.Scan(&record.ResourceID, &record.Provider, &record.ProviderVersion, &record.ResourceType, &stateJSON, &record.CreatedAt)
+	record, err := scanStateRecord(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -97,21 +119,13 @@ func (s *SQLiteStorage) GetState(ctx context.Context, id string) (*types.StateRe
 		return nil, err
 	}
 
-	// Deserialize state from JSON
-	if stateJSON != "" {
-		err = json.Unmarshal([]byte(stateJSON), &record.State)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize state: %w", err)
-		}
-	}
-
-	return &record, nil
+	return record, nil
 }
 
 // ListStates returns all state records
 func (s *SQLiteStorage) ListStates(ctx context.Context) ([]types.StateRecord, error) {
 	query := `
-	SELECT id, provider, provider_version, resource_type, state, created_at
+	SELECT id, provider, provider_version, resource_type, state, provider_config, provider_alias, private, schema_version, dev_override, created_at
 	FROM state_records
 	ORDER BY created_at DESC
 	`
@@ -124,30 +138,56 @@ func (s *SQLiteStorage) ListStates(ctx context.Context) ([]types.StateRecord, er
 
 	var records []types.StateRecord
 	for rows.Next() {
-		var record types.StateRecord
-		var stateJSON string
-		err := rows.Scan(&record.ResourceID, &record.Provider, &record.ProviderVersion, &record.ResourceType, &stateJSON, &record.CreatedAt)
+		record, err := scanStateRecord(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		// Deserialize state from JSON
-		if stateJSON != "" {
-			err = json.Unmarshal([]byte(stateJSON), &record.State)
-			if err != nil {
-				return nil, fmt.Errorf("failed to deserialize state: %w", err)
-			}
+		records = append(records, *record)
+	}
+
+	return records, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanStateRecord reads a state record from a row selected with the state_records column list
+func scanStateRecord(row rowScanner) (*types.StateRecord, error) {
+	var record types.StateRecord
+	var stateJSON, providerConfigJSON string
+	var schemaVersion sql.NullInt64
+	err := row.Scan(&record.ResourceID, &record.Provider, &record.ProviderVersion, &record.ResourceType, &stateJSON, &providerConfigJSON, &record.ProviderAlias, &record.Private, &schemaVersion, &record.DevOverride, &record.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if schemaVersion.Valid {
+		record.SchemaVersion = &schemaVersion.Int64
+	}
+
+	// Deserialize state from JSON
+	if stateJSON != "" {
+		if err := json.Unmarshal([]byte(stateJSON), &record.State); err != nil {
+			return nil, fmt.Errorf("failed to deserialize state: %w", err)
 		}
+	}
 
-		records = append(records, record)
+	// Deserialize provider config from JSON
+	if providerConfigJSON != "" {
+		if err := json.Unmarshal([]byte(providerConfigJSON), &record.ProviderConfig); err != nil {
+			return nil, fmt.Errorf("failed to deserialize provider config: %w", err)
+		}
 	}
 
-	return records, rows.Err()
+	return &record, nil
 }
 
 // UpdateState updates an existing state record
 func (s *SQLiteStorage) UpdateState(ctx context.Context, record types.StateRecord) error {
-	// This is essentially the same as SaveState since we use INSERT OR REPLACE
+	// This is essentially the same as SaveState since it upserts
 	return s.SaveState(ctx, record)
 }
 
@@ -170,6 +210,10 @@ func (s *SQLiteStorage) DeleteState(ctx context.Context, id string) error {
 				CreatedAt:  s.getCurrentTimestamp(),
 			}
 
+			if details, ok := ctx.Value(types.DetailsContextKey).(map[string]any); ok {
+				event.Details = details
+			}
+
 			s.addTimelineEvent(ctx, event) // Use internal method to avoid circular calls
 		}
 	}
@@ -305,11 +349,19 @@ func (s *SQLiteStorage) addTimelineEvent(ctx context.Context, event types.Timeli
 	}
 	event.ID = id.String()
 
+	var detailsJSON []byte
+	if len(event.Details) > 0 {
+		detailsJSON, err = json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to serialize event details: %w", err)
+		}
+	}
+
 	query := `
-	INSERT INTO timeline_events (id, resource_id, operation, changed_by, created_at)
-	VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	INSERT INTO timeline_events (id, resource_id, operation, changed_by, details, created_at)
+	VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	`
-	_, err = s.db.ExecContext(ctx, query, event.ID, event.ResourceID, event.Operation, event.ChangedBy)
+	_, err = s.db.ExecContext(ctx, query, event.ID, event.ResourceID, event.Operation, event.ChangedBy, string(detailsJSON))
 	return err
 }
 
@@ -358,7 +410,7 @@ func (s *SQLiteStorage) GetTimeline(ctx context.Context, query types.TimelineQue
 	}
 
 	// Get the events
-	eventsQuery := "SELECT id, resource_id, operation, changed_by, created_at " +
+	eventsQuery := "SELECT id, resource_id, operation, changed_by, details, created_at " +
 		baseQuery + " ORDER BY created_at DESC LIMIT ? OFFSET ?"
 
 	args = append(args, query.Limit, query.Offset)
@@ -371,10 +423,17 @@ func (s *SQLiteStorage) GetTimeline(ctx context.Context, query types.TimelineQue
 	var events []types.TimelineEvent
 	for rows.Next() {
 		var event types.TimelineEvent
-		err := rows.Scan(&event.ID, &event.ResourceID, &event.Operation, &event.ChangedBy, &event.CreatedAt)
+		var detailsJSON string
+		err := rows.Scan(&event.ID, &event.ResourceID, &event.Operation, &event.ChangedBy, &detailsJSON, &event.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan timeline event: %w", err)
 		}
+
+		if detailsJSON != "" {
+			if err := json.Unmarshal([]byte(detailsJSON), &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to deserialize event details: %w", err)
+			}
+		}
 		events = append(events, event)
 	}
 
@@ -392,6 +451,180 @@ func (s *SQLiteStorage) GetTimeline(ctx context.Context, query types.TimelineQue
 	}, nil
 }
 
+// SaveProviderConfig creates or replaces a named provider configuration
+func (s *SQLiteStorage) SaveProviderConfig(ctx context.Context, config types.NamedProviderConfig) error {
+	configJSON, err := json.Marshal(config.Config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize provider config: %w", err)
+	}
+
+	query := `
+	INSERT INTO provider_configs (alias, provider, config, created_at, updated_at)
+	VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	ON CONFLICT(alias) DO UPDATE SET
+		provider = excluded.provider,
+		config = excluded.config,
+		updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err = s.db.ExecContext(ctx, query, config.Alias, config.Provider, string(configJSON))
+	return err
+}
+
+// GetProviderConfig retrieves a named provider configuration by alias
+func (s *SQLiteStorage) GetProviderConfig(ctx context.Context, alias string) (*types.NamedProviderConfig, error) {
+	query := `
+	SELECT alias, provider, config, created_at, updated_at
+	FROM provider_configs
+	WHERE alias = ?
+	`
+
+	config, err := scanNamedProviderConfig(s.db.QueryRowContext(ctx, query, alias))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ListProviderConfigs returns all named provider configurations
+func (s *SQLiteStorage) ListProviderConfigs(ctx context.Context) ([]types.NamedProviderConfig, error) {
+	query := `
+	SELECT alias, provider, config, created_at, updated_at
+	FROM provider_configs
+	ORDER BY alias
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var configs []types.NamedProviderConfig
+	for rows.Next() {
+		config, err := scanNamedProviderConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		configs = append(configs, *config)
+	}
+
+	return configs, rows.Err()
+}
+
+// DeleteProviderConfig removes a named provider configuration by alias
+func (s *SQLiteStorage) DeleteProviderConfig(ctx context.Context, alias string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM provider_configs WHERE alias = ?`, alias)
+	return err
+}
+
+// scanNamedProviderConfig reads a named provider configuration from a row
+func scanNamedProviderConfig(row rowScanner) (*types.NamedProviderConfig, error) {
+	var config types.NamedProviderConfig
+	var configJSON string
+	if err := row.Scan(&config.Alias, &config.Provider, &configJSON, &config.CreatedAt, &config.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(configJSON), &config.Config); err != nil {
+		return nil, fmt.Errorf("failed to deserialize provider config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// SavePlan stores a plan and removes plans that have already expired
+func (s *SQLiteStorage) SavePlan(ctx context.Context, plan types.PlanRecord) error {
+	encoded := map[string]string{}
+	for column, value := range map[string]any{
+		"provider_config":  plan.ProviderConfig,
+		"config":           plan.Config,
+		"planned_state":    plan.PlannedState,
+		"requires_replace": plan.RequiresReplace,
+	} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to serialize plan %s: %w", column, err)
+		}
+		if string(data) != "null" {
+			encoded[column] = string(data)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM plans WHERE expires_at < ?`, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to remove expired plans: %w", err)
+	}
+
+	query := `
+	INSERT INTO plans (id, resource_id, operation, provider, provider_version, resource_type, provider_config, provider_alias, config, prior_state_hash, planned_state, planned_private, requires_replace, created_at, expires_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, query, plan.ID, plan.ResourceID, plan.Operation, plan.Provider, plan.ProviderVersion, plan.ResourceType,
+		encoded["provider_config"], plan.ProviderAlias, encoded["config"], plan.PriorStateHash, encoded["planned_state"], plan.PlannedPrivate, encoded["requires_replace"], plan.ExpiresAt.Unix())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPlan retrieves a plan by ID
+func (s *SQLiteStorage) GetPlan(ctx context.Context, id string) (*types.PlanRecord, error) {
+	query := `
+	SELECT id, resource_id, operation, provider, provider_version, resource_type, provider_config, provider_alias, config, prior_state_hash, planned_state, planned_private, requires_replace, created_at, expires_at
+	FROM plans
+	WHERE id = ?
+	`
+
+	var plan types.PlanRecord
+	var providerConfigJSON, configJSON, plannedStateJSON, requiresReplaceJSON string
+	var expiresAt int64
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&plan.ID, &plan.ResourceID, &plan.Operation, &plan.Provider, &plan.ProviderVersion, &plan.ResourceType,
+		&providerConfigJSON, &plan.ProviderAlias, &configJSON, &plan.PriorStateHash, &plannedStateJSON, &plan.PlannedPrivate, &requiresReplaceJSON, &plan.CreatedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	plan.ExpiresAt = time.Unix(expiresAt, 0).UTC()
+
+	for target, data := range map[any]string{
+		&plan.ProviderConfig:  providerConfigJSON,
+		&plan.Config:          configJSON,
+		&plan.PlannedState:    plannedStateJSON,
+		&plan.RequiresReplace: requiresReplaceJSON,
+	} {
+		if data == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(data), target); err != nil {
+			return nil, fmt.Errorf("failed to deserialize plan: %w", err)
+		}
+	}
+
+	return &plan, nil
+}
+
+// DeletePlan removes a plan, e.g. once it has been applied
+func (s *SQLiteStorage) DeletePlan(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM plans WHERE id = ?`, id)
+	return err
+}
+
 // getCurrentTimestamp returns the current timestamp in RFC3339 format
 func (s *SQLiteStorage) getCurrentTimestamp() string {
 	return time.Now().UTC().Format(time.RFC3339)
@@ -406,8 +639,8 @@ type ResourceReference struct {
 
 func (s *SQLiteStorage) SaveResourceOperation(ctx context.Context, operation types.ResourceOperation) error {
 	query := `
-	INSERT INTO operations (id, resource_id, resource_type, provider, provider_version, operation, current_state, proposed_state, created_at, failed)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, ?)
+	INSERT INTO operations (id, resource_id, resource_type, provider, provider_version, previous_provider_version, previous_resource_type, previous_provider, operation, current_state, proposed_state, created_at, failed, interrupted, timed_out, diagnostics)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, ?, ?, ?, ?)
 	`
 
 	currentState, err := json.Marshal(operation.CurrentState)
@@ -423,6 +656,15 @@ func (s *SQLiteStorage) SaveResourceOperation(ctx context.Context, operation typ
 		failed.String = *operation.Failed
 		failed.Valid = true
 	}
+	diagnostics := sql.NullString{}
+	if len(operation.Diagnostics) > 0 {
+		diagnosticsJSON, err := json.Marshal(operation.Diagnostics)
+		if err != nil {
+			return fmt.Errorf("failed to serialize diagnostics: %w", err)
+		}
+		diagnostics.String = string(diagnosticsJSON)
+		diagnostics.Valid = true
+	}
 
 	_, err = s.db.ExecContext(ctx, query,
 		operation.ID,
@@ -430,17 +672,23 @@ func (s *SQLiteStorage) SaveResourceOperation(ctx context.Context, operation typ
 		operation.ResourceType,
 		operation.Provider,
 		operation.ProviderVersion,
+		operation.PreviousProviderVersion,
+		operation.PreviousResourceType,
+		operation.PreviousProvider,
 		operation.Operation,
 		string(currentState),
 		string(proposedState),
 		failed,
+		operation.Interrupted,
+		operation.TimedOut,
+		diagnostics,
 	)
 	return err
 }
 
 func (s *SQLiteStorage) ListResourceOperations(ctx context.Context, args types.ResourceOperationsArgs) ([]types.ResourceOperation, error) {
 	query := `
-	SELECT id, resource_id, resource_type, provider, provider_version, operation, current_state, proposed_state, created_at, failed
+	SELECT id, resource_id, resource_type, provider, provider_version, previous_provider_version, previous_resource_type, previous_provider, operation, current_state, proposed_state, created_at, failed, interrupted, timed_out, diagnostics
 	FROM operations 
 	WHERE 1=1
 	`
@@ -489,18 +737,24 @@ func (s *SQLiteStorage) ListResourceOperations(ctx context.Context, args types.R
 	for rows.Next() {
 		var operation types.ResourceOperation
 		var currentStateJSON, proposedStateJSON string
-		var failed sql.NullString
+		var failed, diagnostics sql.NullString
 
 		err := rows.Scan(&operation.ID,
 			&operation.ResourceID,
 			&operation.ResourceType,
 			&operation.Provider,
 			&operation.ProviderVersion,
+			&operation.PreviousProviderVersion,
+			&operation.PreviousResourceType,
+			&operation.PreviousProvider,
 			&operation.Operation,
 			&currentStateJSON,
 			&proposedStateJSON,
 			&operation.CreatedAt,
-			&failed)
+			&failed,
+			&operation.Interrupted,
+			&operation.TimedOut,
+			&diagnostics)
 		if err != nil {
 			return nil, err
 		}
@@ -514,6 +768,11 @@ func (s *SQLiteStorage) ListResourceOperations(ctx context.Context, args types.R
 		if failed.Valid && failed.String != "" {
 			operation.Failed = &failed.String
 		}
+		if diagnostics.Valid {
+			if err = json.Unmarshal([]byte(diagnostics.String), &operation.Diagnostics); err != nil {
+				return nil, fmt.Errorf("failed to deserialize diagnostics: %w", err)
+			}
+		}
 
 		operations = append(operations, operation)
 	}
@@ -523,7 +782,7 @@ func (s *SQLiteStorage) ListResourceOperations(ctx context.Context, args types.R
 
 func (s *SQLiteStorage) GetResourceOperation(ctx context.Context, resourceID string) (*types.ResourceOperation, error) {
 	query := `
-	SELECT id, resource_id, resource_type, provider, provider_version, operation, current_state, proposed_state, created_at, failed
+	SELECT id, resource_id, resource_type, provider, provider_version, previous_provider_version, previous_resource_type, previous_provider, operation, current_state, proposed_state, created_at, failed, interrupted, timed_out, diagnostics
 	FROM operations 
 	WHERE resource_id = ?
 	ORDER BY created_at DESC
@@ -534,18 +793,24 @@ func (s *SQLiteStorage) GetResourceOperation(ctx context.Context, resourceID str
 
 	var operation types.ResourceOperation
 	var currentStateJSON, proposedStateJSON string
-	var failed sql.NullString
+	var failed, diagnostics sql.NullString
 
 	err := row.Scan(&operation.ID,
 		&operation.ResourceID,
 		&operation.ResourceType,
 		&operation.Provider,
 		&operation.ProviderVersion,
+		&operation.PreviousProviderVersion,
+		&operation.PreviousResourceType,
+		&operation.PreviousProvider,
 		&operation.Operation,
 		&currentStateJSON,
 		&proposedStateJSON,
 		&operation.CreatedAt,
-		&failed)
+		&failed,
+		&operation.Interrupted,
+		&operation.TimedOut,
+		&diagnostics)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -562,6 +827,11 @@ func (s *SQLiteStorage) GetResourceOperation(ctx context.Context, resourceID str
 	if failed.Valid && failed.String != "" {
 		operation.Failed = &failed.String
 	}
+	if diagnostics.Valid {
+		if err = json.Unmarshal([]byte(diagnostics.String), &operation.Diagnostics); err != nil {
+			return nil, fmt.Errorf("failed to deserialize diagnostics: %w", err)
+		}
+	}
 
 	return &operation, nil
 }
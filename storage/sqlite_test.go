@@ -159,7 +159,10 @@ func TestSQLiteListResourceOperations(t *testing.T) {
 					CurrentState:    map[string]any{"status": "current-2"},
 					ProposedState:   map[string]any{"status": "next-2"},
 				},
-				ResourceOperationResult: types.ResourceOperationResult{Failed: &failedMsg},
+				ResourceOperationResult: types.ResourceOperationResult{Failed: &failedMsg, Interrupted: true, TimedOut: true, Diagnostics: types.Diagnostics{
+					{Severity: "error", Summary: "Bucket not empty", Attribute: "force_destroy"},
+					{Severity: "warning", Summary: "Argument is deprecated"},
+				}},
 			},
 			createdAt: baseTime.Add(2 * time.Minute),
 		},
@@ -205,6 +208,11 @@ func TestSQLiteListResourceOperations(t *testing.T) {
 		require.Nil(t, opList[0].Failed)
 		require.NotNil(t, opList[1].Failed)
 		require.Equal(t, failedMsg, *opList[1].Failed)
+		require.False(t, opList[0].Interrupted)
+		require.True(t, opList[1].Interrupted)
+		require.True(t, opList[1].TimedOut)
+		require.Nil(t, opList[0].Diagnostics)
+		require.Equal(t, records[2].op.Diagnostics, opList[1].Diagnostics)
 	})
 
 	t.Run("applies limit offset and filters by resource id", func(t *testing.T) {
@@ -242,3 +250,301 @@ func TestSQLiteListResourceOperations(t *testing.T) {
 		require.Contains(t, err.Error(), "limit must be non-negative")
 	})
 }
+
+func TestSQLiteStateProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	withConfig := types.StateRecord{
+		ResourceID:      "bucket-eu",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_s3_bucket",
+		State:           map[string]any{"bucket": "eu-bucket"},
+		ProviderConfig:  map[string]any{"region": "eu-west-1"},
+	}
+	withoutConfig := types.StateRecord{
+		ResourceID:      "bucket-default",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_s3_bucket",
+		State:           map[string]any{"bucket": "default-bucket"},
+		ProviderAlias:   "aws.us",
+	}
+
+	require.NoError(t, store.SaveState(ctx, withConfig))
+	require.NoError(t, store.SaveState(ctx, withoutConfig))
+
+	got, err := store.GetState(ctx, "bucket-eu")
+	require.NoError(t, err)
+	require.Equal(t, withConfig.ProviderConfig, got.ProviderConfig)
+	require.Equal(t, withConfig.State, got.State)
+
+	got, err = store.GetState(ctx, "bucket-default")
+	require.NoError(t, err)
+	require.Nil(t, got.ProviderConfig)
+	require.Equal(t, "aws.us", got.ProviderAlias)
+
+	records, err := store.ListStates(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		if record.ResourceID == "bucket-eu" {
+			require.Equal(t, withConfig.ProviderConfig, record.ProviderConfig)
+		}
+	}
+}
+
+func TestSQLiteProviderConfigs(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	got, err := store.GetProviderConfig(ctx, "aws.eu")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	require.NoError(t, store.SaveProviderConfig(ctx, types.NamedProviderConfig{
+		Alias:    "aws.eu",
+		Provider: "hashicorp/aws",
+		Config:   map[string]any{"region": "eu-west-1"},
+	}))
+	require.NoError(t, store.SaveProviderConfig(ctx, types.NamedProviderConfig{
+		Alias:    "aws.us",
+		Provider: "hashicorp/aws",
+		Config:   map[string]any{"region": "us-east-1"},
+	}))
+
+	// Saving an existing alias replaces its configuration
+	require.NoError(t, store.SaveProviderConfig(ctx, types.NamedProviderConfig{
+		Alias:    "aws.eu",
+		Provider: "hashicorp/aws",
+		Config:   map[string]any{"region": "eu-central-1"},
+	}))
+
+	got, err = store.GetProviderConfig(ctx, "aws.eu")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "hashicorp/aws", got.Provider)
+	require.Equal(t, map[string]any{"region": "eu-central-1"}, got.Config)
+
+	configs, err := store.ListProviderConfigs(ctx)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	require.Equal(t, "aws.eu", configs[0].Alias)
+	require.Equal(t, "aws.us", configs[1].Alias)
+
+	require.NoError(t, store.DeleteProviderConfig(ctx, "aws.eu"))
+
+	got, err = store.GetProviderConfig(ctx, "aws.eu")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestSQLiteStatePrivateAndSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	schemaVersion := int64(1)
+	private := []byte(`{"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0":{"create":600000000000}}`)
+	require.NoError(t, store.SaveState(ctx, types.StateRecord{
+		ResourceID:      "instance",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_instance",
+		State:           map[string]any{"id": "i-123"},
+		Private:         private,
+		SchemaVersion:   &schemaVersion,
+	}))
+	require.NoError(t, store.SaveState(ctx, types.StateRecord{
+		ResourceID:      "password",
+		Provider:        "hashicorp/random",
+		ProviderVersion: "3.6.0",
+		ResourceType:    "random_password",
+		State:           map[string]any{"id": "none"},
+	}))
+
+	got, err := store.GetState(ctx, "instance")
+	require.NoError(t, err)
+	require.Equal(t, private, got.Private)
+	require.NotNil(t, got.SchemaVersion)
+	require.Equal(t, schemaVersion, *got.SchemaVersion)
+
+	got, err = store.GetState(ctx, "password")
+	require.NoError(t, err)
+	require.Empty(t, got.Private)
+	require.Nil(t, got.SchemaVersion)
+}
+
+func TestSQLiteStateDevOverride(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	record := types.StateRecord{
+		ResourceID:      "widget",
+		Provider:        "acme/internal",
+		ProviderVersion: "0.1.0",
+		ResourceType:    "internal_widget",
+		State:           map[string]any{"id": "w-1"},
+		DevOverride:     true,
+	}
+	require.NoError(t, store.SaveState(ctx, record))
+
+	got, err := store.GetState(ctx, "widget")
+	require.NoError(t, err)
+	require.True(t, got.DevOverride)
+
+	// Saving with a released provider clears the mark
+	record.DevOverride = false
+	require.NoError(t, store.SaveState(ctx, record))
+
+	states, err := store.ListStates(ctx)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.False(t, states[0].DevOverride)
+}
+
+func TestSQLiteTimelineDetails(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	ctx = context.WithValue(ctx, types.OperationContextKey, "upgrade-provider")
+	ctx = context.WithValue(ctx, types.ChangedByContextKey, "tester")
+	ctx = context.WithValue(ctx, types.DetailsContextKey, map[string]any{
+		"from_version": "5.0.0",
+		"to_version":   "6.2.0",
+	})
+
+	require.NoError(t, store.SaveState(ctx, types.StateRecord{
+		ResourceID:      "bucket",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_s3_bucket",
+		State:           map[string]any{"bucket": "bucket"},
+	}))
+
+	require.NoError(t, store.SaveResourceOperation(ctx, types.ResourceOperation{
+		ID: "operation",
+		ResourceOperationInput: types.ResourceOperationInput{
+			ResourceID:              "bucket",
+			ResourceType:            "aws_s3_bucket",
+			Provider:                "hashicorp/aws",
+			ProviderVersion:         "6.2.0",
+			PreviousProviderVersion: "5.0.0",
+			Operation:               "upgrade-provider",
+		},
+	}))
+
+	timeline, err := store.GetTimeline(ctx, types.TimelineQuery{ResourceID: "bucket"})
+	require.NoError(t, err)
+	require.Len(t, timeline.Events, 1)
+	require.Equal(t, "upgrade-provider", timeline.Events[0].Operation)
+	require.Equal(t, map[string]any{"from_version": "5.0.0", "to_version": "6.2.0"}, timeline.Events[0].Details)
+
+	operation, err := store.GetResourceOperation(ctx, "bucket")
+	require.NoError(t, err)
+	require.Equal(t, "5.0.0", operation.PreviousProviderVersion)
+}
+
+func TestSQLitePlans(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	got, err := store.GetPlan(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	require.NoError(t, store.SavePlan(ctx, types.PlanRecord{
+		ID:              "plan-1",
+		ResourceID:      "web",
+		Operation:       "update",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_instance",
+		ProviderConfig:  map[string]any{"region": "eu-west-1"},
+		Config:          map[string]any{"instance_type": "t3.small"},
+		PriorStateHash:  "abc",
+		PlannedState:    map[string]any{"id": "i-123", "instance_type": "t3.small"},
+		PlannedPrivate:  []byte("private"),
+		ExpiresAt:       expiresAt,
+	}))
+
+	got, err = store.GetPlan(ctx, "plan-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "web", got.ResourceID)
+	require.Equal(t, "update", got.Operation)
+	require.Equal(t, map[string]any{"region": "eu-west-1"}, got.ProviderConfig)
+	require.Equal(t, map[string]any{"instance_type": "t3.small"}, got.Config)
+	require.Equal(t, map[string]any{"id": "i-123", "instance_type": "t3.small"}, got.PlannedState)
+	require.Equal(t, []byte("private"), got.PlannedPrivate)
+	require.Equal(t, "abc", got.PriorStateHash)
+	require.True(t, expiresAt.Equal(got.ExpiresAt))
+
+	// Saving a plan removes plans that have already expired
+	require.NoError(t, store.SavePlan(ctx, types.PlanRecord{
+		ID:        "plan-expired",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}))
+	require.NoError(t, store.SavePlan(ctx, types.PlanRecord{
+		ID:        "plan-2",
+		ExpiresAt: expiresAt,
+	}))
+
+	got, err = store.GetPlan(ctx, "plan-expired")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	require.NoError(t, store.DeletePlan(ctx, "plan-1"))
+
+	got, err = store.GetPlan(ctx, "plan-1")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestSQLiteSaveStateKeepsDependencies(t *testing.T) {
+	t.Parallel()
+
+	store, ctx := newTestSQLiteStorage(t)
+
+	for _, id := range []string{"vpc", "subnet"} {
+		require.NoError(t, store.SaveState(ctx, types.StateRecord{
+			ResourceID:   id,
+			Provider:     "hashicorp/aws",
+			ResourceType: "aws_" + id,
+			State:        map[string]any{"id": id + "-1"},
+		}))
+	}
+	require.NoError(t, store.AddDependency(ctx, types.DependencyEdge{
+		FromResourceID: "subnet",
+		ToResourceID:   "vpc",
+		DependencyType: "explicit",
+	}))
+
+	before, err := store.GetState(ctx, "vpc")
+	require.NoError(t, err)
+
+	// Saving a replaced resource under the same ID must not drop its dependency edges
+	require.NoError(t, store.SaveState(ctx, types.StateRecord{
+		ResourceID:   "vpc",
+		Provider:     "hashicorp/aws",
+		ResourceType: "aws_vpc",
+		State:        map[string]any{"id": "vpc-2"},
+	}))
+
+	after, err := store.GetState(ctx, "vpc")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"id": "vpc-2"}, after.State)
+	require.Equal(t, before.CreatedAt, after.CreatedAt)
+
+	dependents, err := store.GetDependents(ctx, "vpc")
+	require.NoError(t, err)
+	require.Len(t, dependents, 1)
+	require.Equal(t, "subnet", dependents[0].FromResourceID)
+}
@@ -31,14 +31,25 @@ func TestServerInitialization(t *testing.T) {
 		"provider-search",
 		"provider-describe",
 		"provider-resources-describe",
+		"lifecycle-resources-validate",
+		"lifecycle-resources-plan",
 		"lifecycle-resources-create",
 		"lifecycle-resources-update",
 		"lifecycle-resources-delete",
 		"lifecycle-resources-refresh",
 		"lifecycle-resources-import",
+		"lifecycle-resources-move",
+		"lifecycle-resources-upgrade-provider",
 		"lifecycle-resources-operations",
 		"lifecycle-datasources-read",
 		"provider-datasources-describe",
+		"provider-ephemeral-resources-describe",
+		"ephemeral-resources-open",
+		"provider-functions-list",
+		"provider-functions-call",
+		"provider-configs-set",
+		"provider-configs-list",
+		"provider-configs-delete",
 		"state-get",
 		"state-list",
 		"state-timeline",
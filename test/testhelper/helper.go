@@ -22,6 +22,12 @@ import (
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
+// testApplyPolicy keeps plans optional so tests can call mutating tools directly
+var testApplyPolicy = types.ApplyPolicy{
+	TTL:        15 * time.Minute,
+	SigningKey: []byte("test-plan-signing-key"),
+}
+
 // TestHelper encapsulates test setup and utilities
 type TestHelper struct {
 	t       *testing.T
@@ -59,10 +65,10 @@ func NewTestHelper(t *testing.T, optionalDirs ...string) *TestHelper {
 	registryClient := registry.NewOpenTofuClient()
 
 	// Initialize provider manager
-	providerManager := provider.NewOpenTofuAdapter(tempDir, registryClient)
+	providerManager := provider.NewOpenTofuAdapter(tempDir, registryClient, types.PluginPolicy{}, nil)
 
 	// Create tool handlers
-	toolHandlers := tools.New(registryClient, providerManager, store)
+	toolHandlers := tools.New(registryClient, providerManager, store, testApplyPolicy)
 
 	// Create test server
 	server := mcp.NewServer(&mcp.Implementation{Name: t.Name(), Version: "1.0.0"}, nil)
@@ -125,10 +131,10 @@ func NewTestHelperWithTimeout(t *testing.T, timeout time.Duration, optionalDirs
 	registryClient := registry.NewOpenTofuClient()
 
 	// Initialize provider manager
-	providerManager := provider.NewOpenTofuAdapter(tempDir, registryClient)
+	providerManager := provider.NewOpenTofuAdapter(tempDir, registryClient, types.PluginPolicy{}, nil)
 
 	// Create tool handlers
-	toolHandlers := tools.New(registryClient, providerManager, stor)
+	toolHandlers := tools.New(registryClient, providerManager, stor, testApplyPolicy)
 
 	// Create test server
 	server := mcp.NewServer(&mcp.Implementation{Name: t.Name(), Version: "1.0.0"}, nil)
@@ -6,10 +6,14 @@ package tools
 import (
 	"github.com/spacelift-io/spacelift-intent/tools/internal"
 	datasourceLifecycle "github.com/spacelift-io/spacelift-intent/tools/lifecycle/datasources"
+	ephemeralLifecycle "github.com/spacelift-io/spacelift-intent/tools/lifecycle/ephemeral"
 	resourceLifecycle "github.com/spacelift-io/spacelift-intent/tools/lifecycle/resources"
 	"github.com/spacelift-io/spacelift-intent/tools/lifecycle/resources/dependencies"
 	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	datasourceSchema "github.com/spacelift-io/spacelift-intent/tools/provider/datasources"
+	ephemeralSchema "github.com/spacelift-io/spacelift-intent/tools/provider/ephemeral"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/functions"
 	resourceSchema "github.com/spacelift-io/spacelift-intent/tools/provider/resources"
 	"github.com/spacelift-io/spacelift-intent/tools/state"
 	"github.com/spacelift-io/spacelift-intent/types"
@@ -20,14 +24,16 @@ type ToolHandlers struct {
 	registryClient  types.RegistryClient
 	providerManager types.ProviderManager
 	storage         types.Storage
+	applyPolicy     types.ApplyPolicy
 }
 
 // New creates new tool handlers
-func New(registryClient types.RegistryClient, providerManager types.ProviderManager, storage types.Storage) *ToolHandlers {
+func New(registryClient types.RegistryClient, providerManager types.ProviderManager, storage types.Storage, applyPolicy types.ApplyPolicy) *ToolHandlers {
 	return &ToolHandlers{
 		registryClient:  registryClient,
 		providerManager: providerManager,
 		storage:         storage,
+		applyPolicy:     applyPolicy,
 	}
 }
 
@@ -40,14 +46,32 @@ func (th *ToolHandlers) Tools() []internal.Tool {
 	// Register describe provider tool
 	tools = append(tools, provider.Describe(th.providerManager))
 
+	// Register provider function tools
+	tools = append(tools, functions.List(th.providerManager))
+
+	tools = append(tools, functions.Call(th.providerManager))
+
+	// Register provider configuration tools
+	tools = append(tools, configs.Set(th.storage))
+
+	tools = append(tools, configs.List(th.storage))
+
+	tools = append(tools, configs.Delete(th.storage))
+
 	// Register describe resource tool
 	tools = append(tools, resourceSchema.Describe(th.providerManager))
 
+	// Register validate configuration tool
+	tools = append(tools, resourceLifecycle.Validate(th.storage, th.providerManager, th.registryClient))
+
+	// Register plan resource tool
+	tools = append(tools, resourceLifecycle.Plan(th.storage, th.providerManager, th.registryClient, th.applyPolicy))
+
 	// Register create resource tool
-	tools = append(tools, resourceLifecycle.Create(th.storage, th.providerManager))
+	tools = append(tools, resourceLifecycle.Create(th.storage, th.providerManager, th.applyPolicy))
 
 	// Register update resource tool
-	tools = append(tools, resourceLifecycle.Update(th.storage, th.providerManager, th.registryClient))
+	tools = append(tools, resourceLifecycle.Update(th.storage, th.providerManager, th.registryClient, th.applyPolicy))
 
 	// Register operations resource tool
 	tools = append(tools, resourceLifecycle.Operations(th.storage))
@@ -56,7 +80,13 @@ func (th *ToolHandlers) Tools() []internal.Tool {
 	tools = append(tools, datasourceSchema.Describe(th.providerManager))
 
 	// Register read data source tool
-	tools = append(tools, datasourceLifecycle.Read(th.providerManager))
+	tools = append(tools, datasourceLifecycle.Read(th.storage, th.providerManager))
+
+	// Register describe ephemeral resource tool
+	tools = append(tools, ephemeralSchema.Describe(th.providerManager))
+
+	// Register open ephemeral resource tool
+	tools = append(tools, ephemeralLifecycle.Open(th.storage, th.providerManager))
 
 	// Register get state tool
 	tools = append(tools, state.Get(th.storage))
@@ -65,11 +95,17 @@ func (th *ToolHandlers) Tools() []internal.Tool {
 	tools = append(tools, state.List(th.storage))
 
 	// Register delete resource tool
-	tools = append(tools, resourceLifecycle.Delete(th.storage, th.providerManager, th.registryClient))
+	tools = append(tools, resourceLifecycle.Delete(th.storage, th.providerManager, th.registryClient, th.applyPolicy))
 
 	// Register refresh resource tool
 	tools = append(tools, resourceLifecycle.Refresh(th.storage, th.providerManager, th.registryClient))
 
+	// Register upgrade provider version tool
+	tools = append(tools, resourceLifecycle.UpgradeProvider(th.storage, th.providerManager))
+
+	// Register move resource tool
+	tools = append(tools, resourceLifecycle.Move(th.storage, th.providerManager, th.registryClient))
+
 	// Register import resource tool
 	tools = append(tools, resourceLifecycle.Import(th.storage, th.providerManager))
 
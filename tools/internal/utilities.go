@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 // RespondJSON marshals the input to JSON and returns it as a text content result.
@@ -35,5 +37,90 @@ func NewToolResultError(text string) *mcp.CallToolResult {
 	}
 }
 
+// RespondJSONError marshals the input to JSON and returns it as an error result.
+func RespondJSONError(input any) (*mcp.CallToolResult, error) {
+	result, err := json.Marshal(input)
+	if err != nil {
+		return NewToolResultError(fmt.Sprintf("Failed to marshal result: %v", err)), nil
+	}
+
+	return NewToolResultError(string(result)), nil
+}
+
+// WithDiagnostics adds the diagnostics providers reported to a tool result: to its JSON object, appended
+// to the diagnostics that reports itself, otherwise as JSON content of their own.
+func WithDiagnostics(result *mcp.CallToolResult, diagnostics types.Diagnostics) *mcp.CallToolResult {
+	if result == nil || len(diagnostics) == 0 {
+		return result
+	}
+
+	if len(result.Content) == 1 {
+		if text, ok := result.Content[0].(*mcp.TextContent); ok && mergeDiagnostics(text, diagnostics) {
+			return result
+		}
+	}
+
+	if content, err := json.Marshal(map[string]any{"diagnostics": diagnostics}); err == nil {
+		result.Content = append(result.Content, &mcp.TextContent{Text: string(content)})
+	}
+
+	return result
+}
+
+// mergeDiagnostics adds diagnostics to a JSON object text content, reporting whether the content is one.
+// Diagnostics the content already reports come first, those it reports already are not repeated.
+func mergeDiagnostics(text *mcp.TextContent, diagnostics types.Diagnostics) bool {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text.Text), &object); err != nil || object == nil {
+		return false
+	}
+
+	var merged []json.RawMessage
+	if reported, ok := object["diagnostics"]; ok {
+		if err := json.Unmarshal(reported, &merged); err != nil {
+			return false
+		}
+	}
+
+	seen := make(map[string]bool, len(merged)+len(diagnostics))
+	for _, diag := range merged {
+		seen[string(diag)] = true
+	}
+	for _, diag := range diagnostics {
+		encoded, err := json.Marshal(diag)
+		if err != nil {
+			return false
+		}
+		if seen[string(encoded)] {
+			continue
+		}
+		seen[string(encoded)] = true
+		merged = append(merged, encoded)
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return false
+	}
+	object["diagnostics"] = encoded
+
+	result, err := json.Marshal(object)
+	if err != nil {
+		return false
+	}
+	text.Text = string(result)
+
+	return true
+}
+
+// MarkDevOverride marks a response about a provider loaded from a locally built binary, so its results
+// are not mistaken for those of a released provider version.
+func MarkDevOverride(response map[string]any, providerManager types.ProviderManager, providerName string) map[string]any {
+	if providerManager.IsDevOverride(providerName) {
+		response["dev_override"] = true
+	}
+	return response
+}
+
 // PtrTo returns a pointer to the given value.
 func PtrTo[T any](v T) *T { return &v }
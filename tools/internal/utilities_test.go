@@ -0,0 +1,67 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestWithDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	deprecated := types.Diagnostic{Severity: "warning", Summary: "Argument is deprecated", Attribute: "acl"}
+
+	texts := func(result *mcp.CallToolResult) []string {
+		var texts []string
+		for _, content := range result.Content {
+			texts = append(texts, content.(*mcp.TextContent).Text)
+		}
+		return texts
+	}
+
+	t.Run("added to JSON results", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := RespondJSON(map[string]any{"status": "created"})
+		require.NoError(t, err)
+
+		result = WithDiagnostics(result, types.Diagnostics{deprecated})
+		assert.Equal(t, []string{`{"diagnostics":[{"severity":"warning","summary":"Argument is deprecated","attribute":"acl"}],"status":"created"}`}, texts(result))
+	})
+
+	t.Run("added as content of their own to other results", func(t *testing.T) {
+		t.Parallel()
+
+		result := WithDiagnostics(NewToolResultError("Failed to delete resource: Bucket not empty"), types.Diagnostics{{Severity: "error", Summary: "Bucket not empty"}})
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, []string{
+			"Failed to delete resource: Bucket not empty",
+			`{"diagnostics":[{"severity":"error","summary":"Bucket not empty"}]}`,
+		}, texts(result))
+	})
+
+	t.Run("appended to the diagnostics results report", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := RespondJSONError(map[string]any{"status": "invalid", "diagnostics": types.Diagnostics{deprecated}})
+		require.NoError(t, err)
+
+		result = WithDiagnostics(result, types.Diagnostics{deprecated, {Severity: "error", Summary: "Invalid ACL"}})
+		assert.Equal(t, []string{`{"diagnostics":[{"severity":"warning","summary":"Argument is deprecated","attribute":"acl"},{"severity":"error","summary":"Invalid ACL"}],"status":"invalid"}`}, texts(result))
+	})
+
+	t.Run("results without diagnostics are unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		result := WithDiagnostics(NewToolResultText("{}"), nil)
+		assert.Equal(t, []string{"{}"}, texts(result))
+	})
+}
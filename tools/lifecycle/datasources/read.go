@@ -10,6 +10,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
@@ -19,17 +20,19 @@ type readArgs struct {
 	Config          map[string]any `json:"config"`
 	ProviderVersion string         `json:"provider_version"`
 	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
 }
 
 func (args readArgs) GetProvider() *types.ProviderConfig {
 	return &types.ProviderConfig{
 		Name:    args.ProviderName,
 		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
 		Config:  args.ProviderConfig,
 	}
 }
 
-func Read(providerManager types.ProviderManager) i.Tool {
+func Read(storage types.Storage, providerManager types.ProviderManager) i.Tool {
 	return i.Tool{Tool: mcp.Tool{
 		Name: string("lifecycle-datasources-read"),
 		Description: "Read data from a data source of any type from any provider. " +
@@ -39,9 +42,8 @@ func Read(providerManager types.ProviderManager) i.Tool {
 			"Use this to retrieve existing infrastructure information, configuration values, " +
 			"and external references needed for resource configuration. Does not modify state - " +
 			"purely informational data retrieval through provider APIs. " +
-			"\n\nArgument Handling: Set unknown/optional arguments to appropriate defaults: " +
-			"strings to null or '', booleans to null or false, numbers to null or 0, arrays " +
-			"to null or [], objects to null or {}. Ensure ALL required arguments are provided.",
+			"\n\nArgument Handling: Ensure ALL required arguments are provided. Omitted optional " +
+			"arguments and nested blocks are filled from the schema with nulls and empty blocks.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Read data from a data source", i.Readonly|i.Idempotent|i.OpenWorld)),
 		InputSchema: i.ToolInputSchema{
 			Type: "object",
@@ -62,29 +64,49 @@ func Read(providerManager types.ProviderManager) i.Tool {
 					"type":        "string",
 					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
 				},
+				"provider_config": map[string]any{
+					"type": "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). " +
+						"Use the same configuration as the resources this data source relates to. " +
+						"Do not put credentials here - use environment variables instead.",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set (e.g., 'aws.eu'). Mutually exclusive with provider_config",
+				},
 			},
 			Required: []string{"provider", "data_source_type", "config", "provider_version"},
 		},
-	}, Handler: read(providerManager)}
+	}, Handler: read(storage, providerManager)}
 }
 
-func read(providerManager types.ProviderManager) i.ToolHandler {
+func read(storage types.Storage, providerManager types.ProviderManager) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args readArgs) (*mcp.CallToolResult, error) {
+		providerConfig := args.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
 		// Read data source using provider manager
-		stateResult, err := providerManager.ReadDataSource(ctx, args.GetProvider(), args.DataSourceType, args.Config)
+		stateResult, diagnostics, err := providerManager.ReadDataSource(ctx, providerConfig, args.DataSourceType, args.Config)
 		if err != nil {
-			return i.NewToolResultError(fmt.Sprintf("Failed to read data source: %v", err)), nil
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to read data source: %v", err)), diagnostics), nil
 		}
 
 		// Handle empty state case
 		if len(stateResult) == 0 {
-			return i.NewToolResultText("{}"), nil
+			return i.WithDiagnostics(i.NewToolResultText("{}"), diagnostics), nil
 		}
 
-		return i.RespondJSON(map[string]any{
+		response := map[string]any{
 			"provider":         args.GetProvider().Name,
 			"provider_version": args.GetProvider().Version,
 			"result":           stateResult,
-		})
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, args.GetProvider().Name))
 	})
 }
@@ -0,0 +1,109 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ephemeral
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type openArgs struct {
+	ProviderName    string         `json:"provider"`
+	EphemeralType   string         `json:"ephemeral_resource_type"`
+	Config          map[string]any `json:"config"`
+	ProviderVersion string         `json:"provider_version"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
+}
+
+func (args openArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.ProviderName,
+		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
+		Config:  args.ProviderConfig,
+	}
+}
+
+// Open creates a tool for opening ephemeral resources
+func Open(storage types.Storage, providerManager types.ProviderManager) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("ephemeral-resources-open"),
+		Description: "Open an ephemeral resource, such as short-lived credentials or a secret, and keep its values " +
+			"in server memory for this session. Its values are never written to state or operations: the server " +
+			"renews the resource when the provider asks for it and closes it when the session ends. " +
+			"\n\nMANDATORY PREREQUISITE: Call provider-ephemeral-resources-describe first to get the arguments " +
+			"of the ephemeral resource type. " +
+			"\n\nReturns an id, the values with sensitive ones redacted, and a reference for each value, e.g. " +
+			"'${ephemeral.<id>.secret_string}'. Use a reference as the whole value of a write-only attribute " +
+			"(e.g. 'password_wo') in lifecycle-resources-create or lifecycle-resources-update, or of any attribute " +
+			"of another ephemeral resource - the server substitutes the value without storing it. References are " +
+			"rejected in other attributes, because their values would be stored in the state. References stop " +
+			"working once the server restarts or renewing the resource fails - open it again then. " +
+			"\n\nLOW risk operation, it does not change managed infrastructure. " +
+			"\n\nPresentation: Never show redacted values, refer to them by their reference.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Open an ephemeral resource", i.OpenWorld)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws', 'hashicorp/random')",
+				},
+				"ephemeral_resource_type": map[string]any{
+					"type":        "string",
+					"description": "The ephemeral resource type to open (e.g., 'random_password', 'aws_secretsmanager_secret_version')",
+				},
+				"config": map[string]any{
+					"type":        "object",
+					"description": "Configuration parameters for the ephemeral resource",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
+				},
+				"provider_config": map[string]any{
+					"type":        "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). Do not put credentials here - use environment variables instead.",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set (e.g., 'aws.eu'). Mutually exclusive with provider_config",
+				},
+			},
+			Required: []string{"provider", "ephemeral_resource_type", "config", "provider_version"},
+		},
+	}, Handler: open(storage, providerManager)}
+}
+
+func open(storage types.Storage, providerManager types.ProviderManager) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args openArgs) (*mcp.CallToolResult, error) {
+		providerConfig := args.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		resource, diagnostics, err := providerManager.OpenEphemeralResource(ctx, providerConfig, args.EphemeralType, args.Config)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to open ephemeral resource: %v", err)), diagnostics), nil
+		}
+
+		response := map[string]any{
+			"provider":         args.ProviderName,
+			"provider_version": args.ProviderVersion,
+			"result":           resource,
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, args.ProviderName))
+	})
+}
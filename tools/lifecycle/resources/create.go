@@ -10,27 +10,32 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 type createArgs struct {
-	ResourceID      string         `json:"resource_id"`
-	Provider        string         `json:"provider"`
-	ResourceType    string         `json:"resource_type"`
-	Config          map[string]any `json:"config"`
-	ProviderVersion string         `json:"provider_version"`
-	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ResourceID      string            `json:"resource_id"`
+	Provider        string            `json:"provider"`
+	ResourceType    string            `json:"resource_type"`
+	Config          map[string]any    `json:"config"`
+	ProviderVersion string            `json:"provider_version"`
+	ProviderConfig  map[string]any    `json:"provider_config,omitempty"`
+	ProviderAlias   string            `json:"provider_alias,omitempty"`
+	PlanID          string            `json:"plan_id,omitempty"`
+	Timeouts        map[string]string `json:"timeouts,omitempty"`
 }
 
 func (args createArgs) GetProvider() *types.ProviderConfig {
 	return &types.ProviderConfig{
 		Name:    args.Provider,
 		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
 		Config:  args.ProviderConfig,
 	}
 }
 
-func Create(storage types.Storage, providerManager types.ProviderManager) i.Tool {
+func Create(storage types.Storage, providerManager types.ProviderManager, policy types.ApplyPolicy) i.Tool {
 	return i.Tool{Tool: mcp.Tool{
 		Name: string("lifecycle-resources-create"),
 		Description: "Create a new managed resource of any type from any provider with required ID, " +
@@ -39,15 +44,16 @@ func Create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 			"Do not assume provider names or versions - always search first. " +
 			"\n\nCore tool for the Execution Phase - handles internal " +
 			"planning and application automatically through the MCP abstraction layer. " +
-			"Validates configuration, enforces policies, and manages state persistence. " +
-			"\n\nArgument Handling: Ensure ALL required arguments are provided in config to " +
-			"prevent 'expected X arguments, got Y' errors. Set unknown/optional arguments to " +
-			"appropriate defaults: strings to null or '', booleans to null or false, numbers " +
-			"to null or 0, arrays to null or [], objects to null or {}. " +
+			"Validates the configuration with the provider first (invalid configurations are rejected with diagnostics pointing to the attribute, without changing anything), enforces policies, and manages state persistence. " +
+			"\n\nArgument Handling: Ensure ALL required arguments are provided in config. Only send the " +
+			"optional arguments you want to set - omitted attributes and nested blocks are filled from the " +
+			"schema with nulls and empty blocks. " +
 			"\n\nPresentation: Present successful results using Infrastructure Configuration " +
 			"Analysis format with CREATE section, risk assessment, and next steps. For provider " +
 			"argument mismatches, use Provider Argument Count Mismatch format with auto-resolution " +
-			"strategy. \n\nHint: may want to add a dependency if applicable.",
+			"strategy. \n\nPlan first: pass the plan_id returned by lifecycle-resources-plan with the same arguments to " +
+			"apply exactly the reviewed plan. The server may be configured to require it. " +
+			"\n\nHint: may want to add a dependency if applicable.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Create a new managed resource", i.OpenWorld)),
 		InputSchema: i.ToolInputSchema{
 			Type: "object",
@@ -72,14 +78,34 @@ func Create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 					"type":        "string",
 					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
 				},
+				"provider_config": map[string]any{
+					"type": "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). " +
+						"Stored with the resource and reused for later updates, refreshes and deletes. " +
+						"Do not put credentials here - use environment variables instead.",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set (e.g., 'aws.eu'). Mutually exclusive with provider_config",
+				},
+				"plan_id": map[string]any{
+					"type":        "string",
+					"description": "Plan returned by lifecycle-resources-plan for this create. The arguments must match the plan",
+				},
+				"timeouts": timeoutsSchema,
 			},
 			Required: []string{"resource_id", "provider", "resource_type", "config", "provider_version"},
 		},
-	}, Handler: create(storage, providerManager)}
+	}, Handler: create(storage, providerManager, policy)}
 }
 
-func create(storage types.Storage, providerManager types.ProviderManager) i.ToolHandler {
+func create(storage types.Storage, providerManager types.ProviderManager, policy types.ApplyPolicy) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args createArgs) (*mcp.CallToolResult, error) {
+		providerConfig := args.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
 		// Check if ID already exists
 		existingState, err := storage.GetState(ctx, args.ResourceID)
 		if err != nil {
@@ -89,6 +115,32 @@ func create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 			return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' already exists", args.ResourceID)), nil
 		}
 
+		config, err := withTimeouts(ctx, providerManager, providerConfig, args.ResourceType, "create", args.Config, args.Timeouts, policy)
+		if err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+		args.Config = config
+
+		// Validate the configuration before anything is recorded or created
+		warnings, invalid := validateConfig(ctx, providerManager, providerConfig, args.ResourceType, nil, args.Config)
+		if invalid != nil {
+			return invalid, nil
+		}
+
+		plan, err := loadPlan(ctx, storage, policy, args.PlanID, types.PlanRecord{
+			ResourceID:      args.ResourceID,
+			Operation:       "create",
+			Provider:        args.Provider,
+			ProviderVersion: args.ProviderVersion,
+			ResourceType:    args.ResourceType,
+			ProviderConfig:  args.ProviderConfig,
+			ProviderAlias:   args.ProviderAlias,
+			Config:          args.Config,
+		})
+		if err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
 		input := types.ResourceOperationInput{
 			Operation:       "create",
 			ResourceID:      args.ResourceID,
@@ -101,25 +153,47 @@ func create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 
 		operation, err := newResourceOperation(input)
 
+		var diagnostics types.Diagnostics
 		defer func() {
 			if err != nil {
 				errMessage := err.Error()
 				operation.Failed = &errMessage
 			}
+			operation.Diagnostics = diagnostics
 			storage.SaveResourceOperation(ctx, operation)
 		}()
 
-		// Create resource using provider manager
-		state, createErr := providerManager.CreateResource(ctx, args.GetProvider(), args.ResourceType, args.Config)
-		if createErr != nil && len(state) == 0 {
+		// Create resource using provider manager, applying the saved plan if there is one
+		var newState *types.ResourceState
+		createErr := withOperationTimeout(ctx, policy, func(ctx context.Context) (err error) {
+			if plan != nil {
+				newState, diagnostics, err = providerManager.ApplyResource(ctx, providerConfig, args.ResourceType, nil, args.Config, &types.ResourceState{
+					State:   plan.PlannedState,
+					Private: plan.PlannedPrivate,
+				})
+			} else {
+				newState, diagnostics, err = providerManager.CreateResource(ctx, providerConfig, args.ResourceType, args.Config)
+			}
+			return err
+		})
+
+		// An interrupted or timed out apply may have created the resource already, its partial state is saved
+		ctx, interrupted := markInterrupted(ctx, createErr, &operation)
+		if interrupted != nil {
+			newState = interrupted.State
+		}
+
+		if createErr != nil && newState.IsEmpty() {
 			err = fmt.Errorf("failed to create resource: %w", createErr)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
-		if len(state) == 0 {
-			return i.NewToolResultText("{}"), nil
+		if newState.IsEmpty() {
+			return i.WithDiagnostics(i.NewToolResultText("{}"), diagnostics), nil
 		}
 
+		state := newState.State
+
 		// Persist state to database
 		record := types.StateRecord{
 			ResourceID:      args.ResourceID,
@@ -127,6 +201,11 @@ func create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 			ResourceType:    args.ResourceType,
 			ProviderVersion: args.ProviderVersion,
 			State:           state,
+			ProviderConfig:  args.ProviderConfig,
+			ProviderAlias:   args.ProviderAlias,
+			Private:         newState.Private,
+			SchemaVersion:   i.PtrTo(newState.SchemaVersion),
+			DevOverride:     providerManager.IsDevOverride(args.Provider),
 		}
 
 		// Add operation context for automatic history tracking
@@ -135,22 +214,34 @@ func create(storage types.Storage, providerManager types.ProviderManager) i.Tool
 
 		if err = storage.SaveState(ctx, record); err != nil {
 			err = fmt.Errorf("failed to save state: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
 		if createErr != nil {
 			err = fmt.Errorf("failed to create resource: %w", createErr)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
 		operation.Failed = nil
 
-		return i.RespondJSON(map[string]any{
+		if plan != nil {
+			storage.DeletePlan(ctx, plan.ID)
+		}
+
+		response := map[string]any{
 			"resource_id":      args.ResourceID,
 			"provider":         args.GetProvider().Name,
 			"provider_version": args.GetProvider().Version,
 			"result":           state,
 			"status":           "created",
-		})
+		}
+		if len(warnings) > 0 {
+			response["warnings"] = warnings
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, args.GetProvider().Name))
 	})
 }
@@ -11,14 +11,17 @@ import (
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
 	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 type deleteArgs struct {
-	ResourceID string `json:"resource_id"`
+	ResourceID string            `json:"resource_id"`
+	PlanID     string            `json:"plan_id,omitempty"`
+	Timeouts   map[string]string `json:"timeouts,omitempty"`
 }
 
-func Delete(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.Tool {
+func Delete(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.Tool {
 	return i.Tool{Tool: mcp.Tool{
 		Name: string("lifecycle-resources-delete"),
 		Description: "Delete an existing resource by its ID and remove it from the state. " +
@@ -28,7 +31,9 @@ func Delete(storage types.Storage, providerManager types.ProviderManager, regist
 			"HIGH RISK OPERATION format with potential impact, estimated downtime, and rollback " +
 			"complexity analysis. Require explicit 'CONFIRM' response before proceeding. " +
 			"\n\nPresentation: Present results using Infrastructure Configuration Analysis " +
-			"format with REMOVE section.",
+			"format with REMOVE section. " +
+			"\n\nPlan first: pass the plan_id returned by lifecycle-resources-plan with destroy=true. " +
+			"The server may be configured to require it.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Delete a managed resource", i.Destructive|i.Idempotent|i.OpenWorld)),
 		InputSchema: i.ToolInputSchema{
 			Type: "object",
@@ -37,13 +42,18 @@ func Delete(storage types.Storage, providerManager types.ProviderManager, regist
 					"type":        "string",
 					"description": "The unique identifier of the resource to delete",
 				},
+				"plan_id": map[string]any{
+					"type":        "string",
+					"description": "Plan returned by lifecycle-resources-plan with destroy=true for this resource",
+				},
+				"timeouts": timeoutsSchema,
 			},
 			Required: []string{"resource_id"},
 		},
-	}, Handler: deleteResource(storage, providerManager, registryClient)}
+	}, Handler: deleteResource(storage, providerManager, registryClient, policy)}
 }
 
-func deleteResource(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.ToolHandler {
+func deleteResource(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args deleteArgs) (*mcp.CallToolResult, error) {
 		// Get the current state from database
 		record, err := storage.GetState(ctx, args.ResourceID)
@@ -64,6 +74,42 @@ func deleteResource(storage types.Storage, providerManager types.ProviderManager
 			record.ProviderVersion = providerResult.Provider.Version
 		}
 
+		providerConfig := record.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		// Upgrade state written with an older resource schema version
+		diagnostics, err := upgradeState(ctx, storage, providerManager, providerConfig, record)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		// The provider reads the delete timeout from the prior state, the stored state is left unchanged
+		priorState := record.GetResourceState()
+		if priorState.State, err = withTimeouts(ctx, providerManager, providerConfig, record.ResourceType, "delete", priorState.State, args.Timeouts, policy); err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		priorStateHash, err := stateHash(record)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		plan, err := loadPlan(ctx, storage, policy, args.PlanID, types.PlanRecord{
+			ResourceID:      args.ResourceID,
+			Operation:       "delete",
+			Provider:        record.Provider,
+			ProviderVersion: record.ProviderVersion,
+			ResourceType:    record.ResourceType,
+			ProviderConfig:  record.ProviderConfig,
+			ProviderAlias:   record.ProviderAlias,
+			PriorStateHash:  priorStateHash,
+		})
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
 		input := types.ResourceOperationInput{
 			ResourceID:      record.ResourceID,
 			ResourceType:    record.ResourceType,
@@ -76,22 +122,43 @@ func deleteResource(storage types.Storage, providerManager types.ProviderManager
 
 		operation, err := newResourceOperation(input)
 		if err != nil {
-			return i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), nil
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), diagnostics), nil
 		}
 
+		var deleteDiagnostics types.Diagnostics
 		defer func() {
 			if err != nil {
 				errMessage := fmt.Sprintf("Failed to delete resource: %v", err)
 				operation.Failed = &errMessage
 			}
+			operation.Diagnostics = deleteDiagnostics
 			storage.SaveResourceOperation(ctx, operation)
 		}()
 
 		// Delete the resource using the provider manager
-		err = providerManager.DeleteResource(ctx, record.GetProvider(), record.ResourceType, record.State)
+		err = withOperationTimeout(ctx, policy, func(ctx context.Context) (err error) {
+			deleteDiagnostics, err = providerManager.DeleteResource(ctx, providerConfig, record.ResourceType, priorState)
+			return err
+		})
+		diagnostics = append(diagnostics, deleteDiagnostics...)
 		if err != nil {
+			// An interrupted or timed out delete may leave the resource partly deleted, its partial state is saved
+			var interrupted *types.InterruptedError
+			ctx, interrupted = markInterrupted(ctx, err, &operation)
+			if interrupted != nil && !interrupted.State.IsEmpty() {
+				record.State = interrupted.State.State
+				record.Private = interrupted.State.Private
+				record.SchemaVersion = &interrupted.State.SchemaVersion
+
+				saveCtx := context.WithValue(ctx, types.OperationContextKey, "delete")
+				saveCtx = context.WithValue(saveCtx, types.ChangedByContextKey, "mcp-user")
+				if saveErr := storage.SaveState(saveCtx, *record); saveErr != nil {
+					err = fmt.Errorf("%w, and saving its partial state failed: %w", err, saveErr)
+				}
+			}
+
 			err = fmt.Errorf("failed to delete resource: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
 		// Add operation context for automatic history tracking
@@ -101,14 +168,23 @@ func deleteResource(storage types.Storage, providerManager types.ProviderManager
 		// Delete the state from database (dependencies automatically cleaned up via CASCADE)
 		if err = storage.DeleteState(ctx, args.ResourceID); err != nil {
 			err = fmt.Errorf("failed to delete state from database: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		if plan != nil {
+			storage.DeletePlan(ctx, plan.ID)
 		}
 
-		return i.RespondJSON(map[string]any{
+		response := map[string]any{
 			"provider":         record.GetProvider().Name,
 			"provider_version": record.GetProvider().Version,
 			"resource_id":      args.ResourceID,
 			"status":           "deleted",
-		})
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, record.GetProvider().Name))
 	})
 }
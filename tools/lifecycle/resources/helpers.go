@@ -4,10 +4,14 @@
 package resources
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
@@ -22,3 +26,139 @@ func newResourceOperation(input types.ResourceOperationInput) (types.ResourceOpe
 		ResourceOperationInput: input,
 	}, nil
 }
+
+// upgradeState upgrades stored state to the current resource schema version of the provider, which
+// like OpenTofu is done on every read. Must run before the state is handed to a refresh, update or
+// delete; a schema version change is recorded as a separate operation and persisted even if the
+// follow-up operation fails. The diagnostics the provider reported are returned, also when it fails.
+func upgradeState(ctx context.Context, storage types.Storage, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, record *types.StateRecord) (diagnostics types.Diagnostics, err error) {
+	input := types.ResourceOperationInput{
+		ResourceID:      record.ResourceID,
+		ResourceType:    record.ResourceType,
+		Provider:        record.Provider,
+		ProviderVersion: record.ProviderVersion,
+		Operation:       "upgrade",
+		CurrentState:    record.State,
+	}
+
+	changed, diagnostics, err := upgradeRecord(ctx, providerManager, providerConfig, record)
+	// State of the current version is still normalized by the provider, there is nothing to record
+	if !changed {
+		return diagnostics, err
+	}
+
+	operation, opErr := newResourceOperation(input)
+	if opErr != nil {
+		return diagnostics, opErr
+	}
+
+	defer func() {
+		if err != nil {
+			errMessage := err.Error()
+			operation.Failed = &errMessage
+		}
+		operation.Diagnostics = diagnostics
+		storage.SaveResourceOperation(ctx, operation)
+	}()
+
+	if err != nil {
+		return diagnostics, err
+	}
+
+	operation.ProposedState = record.State
+
+	// Add operation context for automatic history tracking
+	upgradeCtx := context.WithValue(ctx, types.OperationContextKey, "upgrade")
+	upgradeCtx = context.WithValue(upgradeCtx, types.ChangedByContextKey, "mcp-user")
+
+	if err = storage.SaveState(upgradeCtx, *record); err != nil {
+		return diagnostics, fmt.Errorf("failed to save upgraded state: %w", err)
+	}
+
+	return diagnostics, nil
+}
+
+// upgradeRecord upgrades the state of a record in memory to the current resource schema version of the
+// provider, without saving it. Plans read state this way, the operation applying the plan saves it.
+// It reports whether the schema version changed, also when the upgrade fails.
+func upgradeRecord(ctx context.Context, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, record *types.StateRecord) (bool, types.Diagnostics, error) {
+	description, err := providerManager.DescribeResource(ctx, providerConfig, record.ResourceType)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get resource schema version: %w", err)
+	}
+
+	// State saved before schema versions were tracked - assume it was written with the current schema
+	currentState := record.GetResourceState()
+	if record.SchemaVersion == nil {
+		currentState.SchemaVersion = description.SchemaVersion
+	}
+
+	// State of the current version is still normalized by the provider
+	if record.SchemaVersion != nil && *record.SchemaVersion == description.SchemaVersion {
+		upgraded, diagnostics, err := providerManager.UpgradeResourceState(ctx, providerConfig, record.ResourceType, currentState)
+		if err != nil {
+			return false, diagnostics, fmt.Errorf("failed to upgrade state: %w", err)
+		}
+		record.State = upgraded.State
+		return false, diagnostics, nil
+	}
+
+	upgraded, diagnostics, err := providerManager.UpgradeResourceState(ctx, providerConfig, record.ResourceType, currentState)
+	if err != nil {
+		return true, diagnostics, fmt.Errorf("failed to upgrade state from schema version %d to %d: %w", currentState.SchemaVersion, description.SchemaVersion, err)
+	}
+
+	record.State = upgraded.State
+	record.Private = upgraded.Private
+	record.SchemaVersion = &upgraded.SchemaVersion
+	record.DevOverride = providerManager.IsDevOverride(record.Provider)
+
+	return true, diagnostics, nil
+}
+
+// validateConfig validates a configuration with the provider before it is applied. It returns the
+// warnings to pass on, or the result to respond with when the configuration cannot be applied.
+func validateConfig(ctx context.Context, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, resourceType string, currentState *types.ResourceState, config map[string]any) (types.Diagnostics, *mcp.CallToolResult) {
+	diagnostics, err := providerManager.ValidateResource(ctx, providerConfig, resourceType, currentState, config)
+	if err != nil {
+		return nil, i.NewToolResultError(fmt.Sprintf("Failed to validate configuration: %v", err))
+	}
+
+	if diagnostics.HasErrors() {
+		result, _ := i.RespondJSONError(map[string]any{
+			"status":      "invalid",
+			"message":     "Configuration is invalid, nothing was changed. Fix the attributes listed in diagnostics and retry",
+			"diagnostics": diagnostics,
+		})
+		return nil, result
+	}
+
+	return diagnostics.Warnings(), nil
+}
+
+// markInterrupted marks an operation interrupted when the provider was stopped before finishing it, or
+// timed out when it ran out of the server operation timeout, and returns the interruption, which carries
+// the partial state to save. The tool call was most likely cancelled, so the returned context no longer
+// is, for the outcome to still be persisted.
+func markInterrupted(ctx context.Context, err error, operation *types.ResourceOperation) (context.Context, *types.InterruptedError) {
+	var interrupted *types.InterruptedError
+	isInterrupted := errors.As(err, &interrupted)
+	timedOut := errors.Is(err, errOperationTimeout)
+	if !isInterrupted && !timedOut {
+		return ctx, nil
+	}
+
+	operation.Interrupted = isInterrupted
+	operation.TimedOut = timedOut
+	ctx = context.WithoutCancel(ctx)
+	details := map[string]any{}
+	if isInterrupted {
+		details["interrupted"] = true
+	}
+	if timedOut {
+		details["timed_out"] = true
+	}
+	ctx = context.WithValue(ctx, types.DetailsContextKey, details)
+
+	return ctx, interrupted
+}
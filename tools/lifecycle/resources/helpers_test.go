@@ -0,0 +1,184 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/storage"
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// newTestStorage returns a migrated SQLite storage that is closed when the test ends
+func newTestStorage(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "sqlite.db"))
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate())
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+// fakeProviderManager answers provider calls with the functions set on it and records the creates and
+// deletes. Calls without a function fail the test with a nil function call.
+type fakeProviderManager struct {
+	types.ProviderManager
+
+	describe    func(providerConfig *types.ProviderConfig, resourceType string) (*types.TypeDescription, error)
+	validate    func(config map[string]any) (types.Diagnostics, error)
+	create      func(config map[string]any) (*types.ResourceState, types.Diagnostics, error)
+	refresh     func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error)
+	delete      func(ctx context.Context) (types.Diagnostics, error)
+	upgrade     func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error)
+	move        func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error)
+	devOverride bool
+
+	calls  []string       // "create" and "delete", in the order they were made
+	config map[string]any // the config of the last create
+}
+
+func (m *fakeProviderManager) DescribeResource(_ context.Context, providerConfig *types.ProviderConfig, resourceType string) (*types.TypeDescription, error) {
+	return m.describe(providerConfig, resourceType)
+}
+
+func (m *fakeProviderManager) ValidateResource(_ context.Context, _ *types.ProviderConfig, _ string, _ *types.ResourceState, config map[string]any) (types.Diagnostics, error) {
+	return m.validate(config)
+}
+
+func (m *fakeProviderManager) CreateResource(_ context.Context, _ *types.ProviderConfig, _ string, config map[string]any) (*types.ResourceState, types.Diagnostics, error) {
+	m.calls = append(m.calls, "create")
+	m.config = config
+	return m.create(config)
+}
+
+func (m *fakeProviderManager) RefreshResource(_ context.Context, _ *types.ProviderConfig, _ string, state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+	return m.refresh(state)
+}
+
+func (m *fakeProviderManager) DeleteResource(ctx context.Context, _ *types.ProviderConfig, _ string, _ *types.ResourceState) (types.Diagnostics, error) {
+	m.calls = append(m.calls, "delete")
+	return m.delete(ctx)
+}
+
+func (m *fakeProviderManager) UpgradeResourceState(_ context.Context, _ *types.ProviderConfig, _ string, state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+	return m.upgrade(state)
+}
+
+func (m *fakeProviderManager) MoveResourceState(_ context.Context, _ *types.ProviderConfig, _, _ string, state *types.ResourceState, _ string) (*types.ResourceState, types.Diagnostics, error) {
+	return m.move(state)
+}
+
+func (m *fakeProviderManager) IsDevOverride(string) bool { return m.devOverride }
+
+// upgradeProviderManager describes resources at schema version 2 and upgrades state by renaming an
+// attribute, recording the schema versions it upgraded from
+func upgradeProviderManager(upgradedFrom *[]int64) *fakeProviderManager {
+	return &fakeProviderManager{
+		describe: func(*types.ProviderConfig, string) (*types.TypeDescription, error) {
+			return &types.TypeDescription{SchemaVersion: 2}, nil
+		},
+		upgrade: func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+			*upgradedFrom = append(*upgradedFrom, state.SchemaVersion)
+			return &types.ResourceState{State: map[string]any{"name": state.State["legacy_name"]}, SchemaVersion: 2}, nil, nil
+		},
+	}
+}
+
+func TestUpgradeState(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		schemaVersion *int64
+		upgradedFrom  int64
+		recorded      bool
+	}{
+		"state saved before schema versions were tracked": {nil, 2, true},
+		"state of an older schema version":                {i.PtrTo(int64(1)), 1, true},
+		"state of the current schema version":             {i.PtrTo(int64(2)), 2, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store := newTestStorage(t)
+
+			record := &types.StateRecord{
+				ResourceID:    "db",
+				Provider:      "hashicorp/example",
+				ResourceType:  "example_database",
+				State:         map[string]any{"legacy_name": "db"},
+				SchemaVersion: tc.schemaVersion,
+			}
+			var upgradedFrom []int64
+
+			_, err := upgradeState(ctx, store, upgradeProviderManager(&upgradedFrom), &types.ProviderConfig{}, record)
+			require.NoError(t, err)
+			require.Equal(t, []int64{tc.upgradedFrom}, upgradedFrom, "the provider upgrades every read")
+			require.Equal(t, map[string]any{"name": "db"}, record.State)
+
+			ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &record.ResourceID})
+			require.NoError(t, err)
+			if !tc.recorded {
+				require.Empty(t, ops)
+				return
+			}
+
+			require.Len(t, ops, 1)
+			require.Equal(t, "upgrade", ops[0].Operation)
+
+			saved, err := store.GetState(ctx, record.ResourceID)
+			require.NoError(t, err)
+			require.Equal(t, i.PtrTo(int64(2)), saved.SchemaVersion)
+			require.Equal(t, map[string]any{"name": "db"}, saved.State)
+		})
+	}
+}
+
+func TestUpgradeRecord(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	stored := types.StateRecord{
+		ResourceID:    "db",
+		Provider:      "hashicorp/example",
+		ResourceType:  "example_database",
+		State:         map[string]any{"legacy_name": "db"},
+		SchemaVersion: i.PtrTo(int64(1)),
+	}
+	require.NoError(t, store.SaveState(ctx, stored))
+
+	planned := stored
+	var upgradedFrom []int64
+	changed, _, err := upgradeRecord(ctx, upgradeProviderManager(&upgradedFrom), &types.ProviderConfig{}, &planned)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, map[string]any{"name": "db"}, planned.State)
+	require.Equal(t, i.PtrTo(int64(2)), planned.SchemaVersion)
+
+	saved, err := store.GetState(ctx, stored.ResourceID)
+	require.NoError(t, err)
+	require.Equal(t, stored.State, saved.State, "a plan does not save the upgraded state")
+	ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &stored.ResourceID})
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	// The operation applying the plan upgrades the state the same way
+	applied := stored
+	_, err = upgradeState(ctx, store, upgradeProviderManager(&upgradedFrom), &types.ProviderConfig{}, &applied)
+	require.NoError(t, err)
+	plannedHash, err := stateHash(&planned)
+	require.NoError(t, err)
+	appliedHash, err := stateHash(&applied)
+	require.NoError(t, err)
+	require.Equal(t, plannedHash, appliedHash)
+}
@@ -6,26 +6,33 @@ package resources
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 type importArgs struct {
-	ImportID        string         `json:"import_id"`
+	ImportID        string         `json:"import_id,omitempty"`
+	Identity        map[string]any `json:"identity,omitempty"`
+	IdentityVersion *int64         `json:"identity_version,omitempty"`
+	SaveAdditional  bool           `json:"save_additional,omitempty"`
 	DestinationID   string         `json:"destination_id"`
 	Provider        string         `json:"provider"`
 	ResourceType    string         `json:"resource_type"`
 	ProviderVersion string         `json:"provider_version"`
 	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
 }
 
 func (args importArgs) GetProvider() *types.ProviderConfig {
 	return &types.ProviderConfig{
 		Name:    args.Provider,
 		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
 		Config:  args.ProviderConfig,
 	}
 }
@@ -39,6 +46,10 @@ func Import(storage types.Storage, providerManager types.ProviderManager) i.Tool
 			"\n\nMEDIUM risk operation for bringing existing infrastructure under MCP management. " +
 			"Use this to adopt pre-existing resources that were created outside of the MCP " +
 			"workflow. Validates resource exists and reads current state through provider APIs. " +
+			"\n\nIdentify the resource with either import_id or, for resource types whose provider supports it, identity. " +
+			"Some providers import several resources at once (e.g. a security group and its rules): the resource of " +
+			"resource_type is stored under destination_id, the others are returned as additional_resources and only " +
+			"stored when save_additional is set, under derived IDs and depending on the main resource. " +
 			"\n\nArgument Handling: If schema mismatches occur, set unknown/optional arguments " +
 			"to appropriate defaults: strings to null or '', booleans to null or false, numbers " +
 			"to null or 0, arrays to null or [], objects to null or {}. " +
@@ -52,7 +63,20 @@ func Import(storage types.Storage, providerManager types.ProviderManager) i.Tool
 			Properties: map[string]any{
 				"import_id": map[string]any{
 					"type":        "string",
-					"description": "The provider-specific identifier for the infrastructure you want to import",
+					"description": "The provider-specific identifier for the infrastructure you want to import. Mutually exclusive with identity",
+				},
+				"identity": map[string]any{
+					"type": "object",
+					"description": "The resource identity of the infrastructure you want to import (e.g., {\"id\": \"sg-123\", \"region\": \"eu-west-1\"}), " +
+						"for providers supporting import by identity. Mutually exclusive with import_id",
+				},
+				"identity_version": map[string]any{
+					"type":        "integer",
+					"description": "Optional identity schema version the identity was written for, e.g. when copied from an older state file. The provider upgrades it first",
+				},
+				"save_additional": map[string]any{
+					"type":        "boolean",
+					"description": "Also store the other resources returned by the import, as '<destination_id>-<resource_type>-<n>'. Defaults to false",
 				},
 				"destination_id": map[string]any{
 					"type":        "string",
@@ -70,14 +94,33 @@ func Import(storage types.Storage, providerManager types.ProviderManager) i.Tool
 					"type":        "string",
 					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
 				},
+				"provider_config": map[string]any{
+					"type": "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). " +
+						"Stored with the resource and reused for later updates, refreshes and deletes. " +
+						"Do not put credentials here - use environment variables instead.",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set (e.g., 'aws.eu'). Mutually exclusive with provider_config",
+				},
 			},
-			Required: []string{"import_id", "destination_id", "provider", "resource_type", "provider_version"},
+			Required: []string{"destination_id", "provider", "resource_type", "provider_version"},
 		},
 	}, Handler: _import(storage, providerManager)}
 }
 
 func _import(storage types.Storage, providerManager types.ProviderManager) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args importArgs) (*mcp.CallToolResult, error) {
+		if (args.ImportID == "") == (args.Identity == nil) {
+			return i.NewToolResultError("Exactly one of import_id and identity must be set"), nil
+		}
+
+		providerConfig := args.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
 		// Check if ID already exists
 		existingState, err := storage.GetState(ctx, args.DestinationID)
 		if err != nil {
@@ -102,28 +145,44 @@ func _import(storage types.Storage, providerManager types.ProviderManager) i.Too
 			return i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), nil
 		}
 
+		var diagnostics types.Diagnostics
 		defer func() {
 			if err != nil {
 				errMessage := err.Error()
 				operation.Failed = &errMessage
 			}
+			operation.Diagnostics = diagnostics
 			storage.SaveResourceOperation(ctx, operation)
 		}()
 
+		target := types.ImportTarget{
+			ID:              args.ImportID,
+			Identity:        args.Identity,
+			IdentityVersion: args.IdentityVersion,
+		}
+
 		// Import resource using provider manager
-		state, err := providerManager.ImportResource(ctx, args.GetProvider(), args.ResourceType, args.ImportID)
+		imported, diagnostics, err := providerManager.ImportResource(ctx, providerConfig, args.ResourceType, target)
 		if err != nil {
 			err = fmt.Errorf("failed to import resource: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		primary, additional := splitImportedResources(imported, args.ResourceType)
+		if primary == nil {
+			err = fmt.Errorf("the provider did not return a %s resource", args.ResourceType)
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
 		// Handle empty state case - for import, this indicates the resource doesn't exist
 		// TODO: Figure out if we want to handle empty state case for import here or in the providerManager
-		if len(state) == 0 {
-			err = fmt.Errorf("resource with ID '%s' does not exist or returned empty state", args.ImportID)
-			return i.NewToolResultError(err.Error()), nil
+		if primary.State.IsEmpty() {
+			err = fmt.Errorf("resource '%s' does not exist or returned empty state", importTargetName(args))
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
+		state := primary.State.State
+
 		// Persist state to database
 		record := types.StateRecord{
 			ResourceID:      args.DestinationID,
@@ -131,6 +190,11 @@ func _import(storage types.Storage, providerManager types.ProviderManager) i.Too
 			ProviderVersion: args.ProviderVersion,
 			ResourceType:    args.ResourceType,
 			State:           state,
+			ProviderConfig:  args.ProviderConfig,
+			ProviderAlias:   args.ProviderAlias,
+			Private:         primary.State.Private,
+			SchemaVersion:   i.PtrTo(primary.State.SchemaVersion),
+			DevOverride:     providerManager.IsDevOverride(args.Provider),
 		}
 
 		// Add operation context for automatic history tracking
@@ -139,19 +203,144 @@ func _import(storage types.Storage, providerManager types.ProviderManager) i.Too
 
 		if err = storage.SaveState(ctx, record); err != nil {
 			err = fmt.Errorf("failed to save state: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
 
 		operation.ProposedState = state
 
-		return i.RespondJSON(map[string]any{
-			"provider":         args.GetProvider().Name,
-			"provider_version": args.GetProvider().Version,
-			"import_id":        args.ImportID,
-			"destination_id":   args.DestinationID,
-			"result":           state,
-			"status":           "imported",
-			"message":          "resource successfully imported",
+		response := map[string]any{
+			"provider":             args.GetProvider().Name,
+			"provider_version":     args.GetProvider().Version,
+			"import_id":            args.ImportID,
+			"identity":             primary.Identity,
+			"destination_id":       args.DestinationID,
+			"result":               state,
+			"additional_resources": importAdditionalResources(ctx, storage, record, additional, args.SaveAdditional),
+			"status":               "imported",
+			"message":              "resource successfully imported",
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, args.GetProvider().Name))
+	})
+}
+
+// additionalImport is a resource returned by an import besides the one that was asked for
+type additionalImport struct {
+	ResourceID   string         `json:"resource_id"`
+	ResourceType string         `json:"resource_type"`
+	Identity     map[string]any `json:"identity,omitempty"`
+	State        map[string]any `json:"state"`
+	Saved        bool           `json:"saved"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// splitImportedResources returns the first imported resource of the requested type, and all the others
+func splitImportedResources(imported []types.ImportedResource, resourceType string) (*types.ImportedResource, []types.ImportedResource) {
+	for n, resource := range imported {
+		if resource.ResourceType == resourceType {
+			return &imported[n], slices.Delete(slices.Clone(imported), n, n+1)
+		}
+	}
+	return nil, imported
+}
+
+// importAdditionalResources describes the other resources returned by an import, and stores them when save
+// is set. Their IDs are derived from the main resource, which they depend on. Resources the provider returned
+// without state and resources that fail to save are reported, but do not fail the import.
+func importAdditionalResources(ctx context.Context, storage types.Storage, primary types.StateRecord, resources []types.ImportedResource, save bool) []additionalImport {
+	result := make([]additionalImport, 0, len(resources))
+	counts := make(map[string]int)
+
+	for _, resource := range resources {
+		if resource.State.IsEmpty() {
+			continue
+		}
+
+		counts[resource.ResourceType]++
+		additional := additionalImport{
+			ResourceID:   fmt.Sprintf("%s-%s-%d", primary.ResourceID, resource.ResourceType, counts[resource.ResourceType]),
+			ResourceType: resource.ResourceType,
+			Identity:     resource.Identity,
+			State:        resource.State.State,
+		}
+
+		if save {
+			if err := saveAdditionalResource(ctx, storage, primary, additional.ResourceID, resource); err != nil {
+				additional.Error = err.Error()
+			} else {
+				additional.Saved = true
+			}
+		}
+
+		result = append(result, additional)
+	}
+
+	return result
+}
+
+// saveAdditionalResource stores a resource imported together with the main resource and makes it depend on it
+func saveAdditionalResource(ctx context.Context, storage types.Storage, primary types.StateRecord, resourceID string, resource types.ImportedResource) error {
+	existing, err := storage.GetState(ctx, resourceID)
+	if err != nil {
+		return fmt.Errorf("failed to check existing state: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("resource with ID '%s' already exists", resourceID)
+	}
+
+	input := types.ResourceOperationInput{
+		ResourceID:      resourceID,
+		ResourceType:    resource.ResourceType,
+		Provider:        primary.Provider,
+		ProviderVersion: primary.ProviderVersion,
+		Operation:       "import",
+		ProposedState:   resource.State.State,
+	}
+
+	// Additional resources come with the import of the primary one, saving them reports no diagnostics
+	_, err = recordOperation(ctx, storage, input, func(_ *types.ResourceOperation) (types.Diagnostics, error) {
+		record := types.StateRecord{
+			ResourceID:      resourceID,
+			Provider:        primary.Provider,
+			ProviderVersion: primary.ProviderVersion,
+			ResourceType:    resource.ResourceType,
+			State:           resource.State.State,
+			ProviderConfig:  primary.ProviderConfig,
+			ProviderAlias:   primary.ProviderAlias,
+			Private:         resource.State.Private,
+			SchemaVersion:   i.PtrTo(resource.State.SchemaVersion),
+			DevOverride:     primary.DevOverride,
+		}
+
+		// Add operation context for automatic history tracking
+		saveCtx := context.WithValue(ctx, types.OperationContextKey, "import")
+		saveCtx = context.WithValue(saveCtx, types.ChangedByContextKey, "mcp-user")
+		saveCtx = context.WithValue(saveCtx, types.DetailsContextKey, map[string]any{
+			"imported_with": primary.ResourceID,
+		})
+
+		if err := storage.SaveState(saveCtx, record); err != nil {
+			return nil, fmt.Errorf("failed to save state: %w", err)
+		}
+
+		return nil, storage.AddDependency(ctx, types.DependencyEdge{
+			FromResourceID: resourceID,
+			ToResourceID:   primary.ResourceID,
+			DependencyType: "implicit",
+			Explanation:    fmt.Sprintf("Imported together with %s", primary.ResourceID),
 		})
 	})
+
+	return err
+}
+
+// importTargetName describes the resource to import in error messages
+func importTargetName(args importArgs) string {
+	if args.ImportID != "" {
+		return args.ImportID
+	}
+	return fmt.Sprintf("%v", args.Identity)
 }
@@ -0,0 +1,93 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestImportAdditionalResources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	imported := []types.ImportedResource{
+		{ResourceType: "aws_security_group_rule", State: &types.ResourceState{State: map[string]any{"id": "sgrule-1"}}, Identity: map[string]any{"id": "sgrule-1"}},
+		{ResourceType: "aws_security_group", State: &types.ResourceState{State: map[string]any{"id": "sg-123"}}},
+		{ResourceType: "aws_security_group_rule", State: &types.ResourceState{State: map[string]any{"id": "sgrule-2"}}},
+		{ResourceType: "aws_security_group_rule", State: &types.ResourceState{}},
+	}
+
+	primary, additional := splitImportedResources(imported, "aws_security_group")
+	require.Equal(t, "sg-123", primary.State.State["id"])
+	require.Len(t, additional, 3)
+	require.Len(t, imported, 4)
+
+	saveRecord := func(t *testing.T, id string) types.StateRecord {
+		record := types.StateRecord{
+			ResourceID:      id,
+			Provider:        "hashicorp/aws",
+			ProviderVersion: "5.0.0",
+			ResourceType:    "aws_security_group",
+			State:           primary.State.State,
+			ProviderAlias:   "aws.eu",
+		}
+		require.NoError(t, store.SaveState(ctx, record))
+		return record
+	}
+
+	t.Run("only describes the resources unless asked to save them", func(t *testing.T) {
+		record := saveRecord(t, "web")
+
+		result := importAdditionalResources(ctx, store, record, additional, false)
+		require.Len(t, result, 2)
+		require.Equal(t, "web-aws_security_group_rule-1", result[0].ResourceID)
+		require.Equal(t, map[string]any{"id": "sgrule-1"}, result[0].Identity)
+		require.False(t, result[0].Saved)
+
+		stored, err := store.GetState(ctx, "web-aws_security_group_rule-1")
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+
+	t.Run("saves the resources depending on the main resource", func(t *testing.T) {
+		record := saveRecord(t, "api")
+
+		result := importAdditionalResources(ctx, store, record, additional, true)
+		require.Len(t, result, 2)
+		require.Equal(t, "api-aws_security_group_rule-2", result[1].ResourceID)
+		require.True(t, result[1].Saved)
+
+		stored, err := store.GetState(ctx, "api-aws_security_group_rule-2")
+		require.NoError(t, err)
+		require.Equal(t, "aws_security_group_rule", stored.ResourceType)
+		require.Equal(t, map[string]any{"id": "sgrule-2"}, stored.State)
+		require.Equal(t, "aws.eu", stored.ProviderAlias)
+
+		dependents, err := store.GetDependents(ctx, "api")
+		require.NoError(t, err)
+		require.Len(t, dependents, 2)
+
+		operation, err := store.GetResourceOperation(ctx, "api-aws_security_group_rule-1")
+		require.NoError(t, err)
+		require.Equal(t, "import", operation.Operation)
+		require.Nil(t, operation.Failed)
+	})
+
+	t.Run("reports resources that already exist", func(t *testing.T) {
+		record := saveRecord(t, "db")
+		require.NoError(t, store.SaveState(ctx, types.StateRecord{ResourceID: "db-aws_security_group_rule-1", ResourceType: "aws_security_group_rule", Provider: "hashicorp/aws"}))
+
+		result := importAdditionalResources(ctx, store, record, additional, true)
+		require.False(t, result[0].Saved)
+		require.Contains(t, result[0].Error, "already exists")
+		require.True(t, result[1].Saved)
+	})
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type moveArgs struct {
+	ResourceID      string         `json:"resource_id"`
+	ResourceType    string         `json:"resource_type"`
+	Provider        string         `json:"provider,omitempty"`
+	ProviderVersion string         `json:"provider_version,omitempty"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
+}
+
+func Move(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("lifecycle-resources-move"),
+		Description: "Change the resource type of a managed resource, and optionally its provider, without ejecting and " +
+			"re-importing it - e.g. after a provider renamed a type (aws_s3_bucket_object to aws_s3_object). The target " +
+			"provider converts the stored state to the new type, the resource is refreshed and its state is saved " +
+			"under the same resource_id, keeping its dependencies and timeline history. " +
+			"\n\nOnly works for moves the target provider supports - check the provider's upgrade guide. " +
+			"\n\nMEDIUM risk operation: does not change infrastructure, but later updates and deletes will use the new " +
+			"resource type. Always confirm with the user first, showing the old and new type and provider. " +
+			"\n\nPresentation: Report the old and new type and any attribute changes detected after the refresh.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Move a resource to another resource type", i.OpenWorld)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"resource_id": map[string]any{
+					"type":        "string",
+					"description": "Unique identifier of the resource to move",
+				},
+				"resource_type": map[string]any{
+					"type":        "string",
+					"description": "The new resource type (e.g., 'aws_s3_object')",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "The new provider (e.g., 'hashicorp/aws'). Defaults to the current provider of the resource",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "The provider version to manage the resource with as valid semver. Defaults to the current version, required with provider",
+				},
+				"provider_config": map[string]any{
+					"type":        "object",
+					"description": "Optional configuration of the new provider (e.g., {\"region\": \"eu-west-1\"}). Only with provider",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration for the new provider defined with provider-configs-set. Only with provider",
+				},
+			},
+			Required: []string{"resource_id", "resource_type"},
+		},
+	}, Handler: move(storage, providerManager, registryClient)}
+}
+
+func move(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args moveArgs) (*mcp.CallToolResult, error) {
+		record, err := storage.GetState(ctx, args.ResourceID)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to get state: %v", err)), nil
+		}
+		if record == nil {
+			return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found", args.ResourceID)), nil
+		}
+
+		targetProvider := cmp.Or(args.Provider, record.Provider)
+		sameProvider := targetProvider == record.Provider
+		if sameProvider {
+			if args.ResourceType == record.ResourceType {
+				return i.NewToolResultError(fmt.Sprintf("Resource '%s' already is a %s, nothing to move", args.ResourceID, args.ResourceType)), nil
+			}
+			if len(args.ProviderConfig) > 0 || args.ProviderAlias != "" {
+				return i.NewToolResultError("provider_config and provider_alias can only be set when moving to another provider"), nil
+			}
+		} else if args.ProviderVersion == "" {
+			return i.NewToolResultError("provider_version is required when moving to another provider"), nil
+		}
+
+		// If provider version is missing, search for it
+		if record.ProviderVersion == "" {
+			providerResult, err := provider.SearchForProvider(ctx, registryClient, record.Provider)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to retrieve provider version: %v", err)), nil
+			}
+			record.ProviderVersion = providerResult.Provider.Version
+		}
+
+		sourceConfig := record.GetProvider()
+		if err := configs.Resolve(ctx, storage, sourceConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		targetConfig := &types.ProviderConfig{
+			Name:    targetProvider,
+			Version: cmp.Or(args.ProviderVersion, record.ProviderVersion),
+			Alias:   args.ProviderAlias,
+			Config:  args.ProviderConfig,
+		}
+		if sameProvider {
+			targetConfig.Alias, targetConfig.Config = record.ProviderAlias, record.ProviderConfig
+		}
+		if err := configs.Resolve(ctx, storage, targetConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		movedRecord, diagnostics, err := moveResource(ctx, storage, providerManager, *record, sourceConfig, targetConfig, args.ResourceType)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to move resource: %v", err)), diagnostics), nil
+		}
+
+		response := map[string]any{
+			"resource_id":            args.ResourceID,
+			"previous_resource_type": record.ResourceType,
+			"previous_provider":      record.Provider,
+			"resource_type":          movedRecord.ResourceType,
+			"provider":               movedRecord.Provider,
+			"provider_version":       movedRecord.ProviderVersion,
+			"changes":                diffAttributes(record.State, movedRecord.State),
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, movedRecord.Provider))
+	})
+}
+
+// moveResource converts the state of a resource to another resource type, refreshes it and saves it under
+// the same resource ID. The move is recorded as a single operation, with the diagnostics of both provider calls.
+func moveResource(ctx context.Context, storage types.Storage, providerManager types.ProviderManager, record types.StateRecord, sourceConfig, targetConfig *types.ProviderConfig, targetType string) (_ *types.StateRecord, diagnostics types.Diagnostics, err error) {
+	input := types.ResourceOperationInput{
+		ResourceID:           record.ResourceID,
+		ResourceType:         targetType,
+		Provider:             targetConfig.Name,
+		ProviderVersion:      targetConfig.Version,
+		Operation:            "move",
+		CurrentState:         record.State,
+		PreviousResourceType: record.ResourceType,
+	}
+	if record.Provider != targetConfig.Name {
+		input.PreviousProvider = record.Provider
+	}
+	if record.ProviderVersion != targetConfig.Version {
+		input.PreviousProviderVersion = record.ProviderVersion
+	}
+
+	operation, err := newResourceOperation(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			errMessage := err.Error()
+			operation.Failed = &errMessage
+		}
+		operation.Diagnostics = diagnostics
+		storage.SaveResourceOperation(ctx, operation)
+	}()
+
+	currentState := record.GetResourceState()
+	if record.SchemaVersion == nil {
+		// State saved before schema versions were tracked - assume it matches the current source schema
+		description, err := providerManager.DescribeResource(ctx, sourceConfig, record.ResourceType)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get resource schema version: %w", err)
+		}
+		currentState.SchemaVersion = description.SchemaVersion
+	}
+
+	movedState, diagnostics, err := providerManager.MoveResourceState(ctx, targetConfig, record.Provider, record.ResourceType, currentState, targetType)
+	if err != nil {
+		return nil, diagnostics, err
+	}
+
+	refreshedState, refreshDiagnostics, err := providerManager.RefreshResource(ctx, targetConfig, targetType, movedState)
+	diagnostics = append(diagnostics, refreshDiagnostics...)
+	if err != nil {
+		return nil, diagnostics, fmt.Errorf("failed to refresh moved resource: %w", err)
+	}
+
+	if refreshedState.IsEmpty() {
+		return nil, diagnostics, fmt.Errorf("resource appears to have been deleted externally, refresh it first")
+	}
+
+	operation.ProposedState = refreshedState.State
+
+	movedRecord := record
+	movedRecord.ResourceType = targetType
+	movedRecord.Provider = targetConfig.Name
+	movedRecord.ProviderVersion = targetConfig.Version
+	movedRecord.State = refreshedState.State
+	movedRecord.Private = refreshedState.Private
+	movedRecord.SchemaVersion = i.PtrTo(refreshedState.SchemaVersion)
+	movedRecord.DevOverride = providerManager.IsDevOverride(targetConfig.Name)
+	if record.Provider != targetConfig.Name {
+		movedRecord.ProviderAlias = targetConfig.Alias
+		movedRecord.ProviderConfig = nil
+		if targetConfig.Alias == "" {
+			movedRecord.ProviderConfig = targetConfig.Config
+		}
+	}
+
+	// Add operation context for automatic history tracking
+	ctx = context.WithValue(ctx, types.OperationContextKey, "move")
+	ctx = context.WithValue(ctx, types.ChangedByContextKey, "mcp-user")
+	ctx = context.WithValue(ctx, types.DetailsContextKey, map[string]any{
+		"from_type":     record.ResourceType,
+		"to_type":       targetType,
+		"from_provider": record.Provider,
+		"to_provider":   targetConfig.Name,
+	})
+
+	if err = storage.SaveState(ctx, movedRecord); err != nil {
+		return nil, diagnostics, fmt.Errorf("failed to save moved state: %w", err)
+	}
+
+	return &movedRecord, diagnostics, nil
+}
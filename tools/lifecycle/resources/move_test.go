@@ -0,0 +1,107 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// moveProviderManager converts states by renaming their type, failing with moveErr when set, and
+// refreshes them unchanged
+func moveProviderManager(moveErr error, devOverride bool) *fakeProviderManager {
+	return &fakeProviderManager{
+		move: func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+			if moveErr != nil {
+				return nil, nil, moveErr
+			}
+			return &types.ResourceState{State: map[string]any{"key": state.State["key"], "id": "moved"}, SchemaVersion: 1}, nil, nil
+		},
+		refresh: func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+			return state, nil, nil
+		},
+		devOverride: devOverride,
+	}
+}
+
+func TestMoveResource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	saveRecord := func(t *testing.T, id string) types.StateRecord {
+		record := types.StateRecord{
+			ResourceID:      id,
+			Provider:        "hashicorp/aws",
+			ProviderVersion: "5.0.0",
+			ResourceType:    "aws_s3_bucket_object",
+			State:           map[string]any{"key": "index.html", "id": "old"},
+			SchemaVersion:   new(int64),
+		}
+		require.NoError(t, store.SaveState(ctx, record))
+		return record
+	}
+
+	providerConfig := &types.ProviderConfig{Name: "hashicorp/aws", Version: "5.0.0"}
+
+	t.Run("keeps the resource ID and dependencies", func(t *testing.T) {
+		record := saveRecord(t, "object")
+		saveRecord(t, "website")
+		require.NoError(t, store.AddDependency(ctx, types.DependencyEdge{FromResourceID: "website", ToResourceID: "object", DependencyType: "explicit"}))
+
+		moved, _, err := moveResource(ctx, store, moveProviderManager(nil, false), record, providerConfig, providerConfig, "aws_s3_object")
+		require.NoError(t, err)
+		require.Equal(t, "aws_s3_object", moved.ResourceType)
+
+		stored, err := store.GetState(ctx, "object")
+		require.NoError(t, err)
+		require.Equal(t, "aws_s3_object", stored.ResourceType)
+		require.Equal(t, map[string]any{"key": "index.html", "id": "moved"}, stored.State)
+		require.Equal(t, int64(1), *stored.SchemaVersion)
+
+		dependents, err := store.GetDependents(ctx, "object")
+		require.NoError(t, err)
+		require.Len(t, dependents, 1)
+
+		operation, err := store.GetResourceOperation(ctx, "object")
+		require.NoError(t, err)
+		require.Equal(t, "move", operation.Operation)
+		require.Equal(t, "aws_s3_object", operation.ResourceType)
+		require.Equal(t, "aws_s3_bucket_object", operation.PreviousResourceType)
+		require.Empty(t, operation.PreviousProvider)
+		require.Nil(t, operation.Failed)
+	})
+
+	t.Run("leaves the state unchanged when the provider refuses", func(t *testing.T) {
+		record := saveRecord(t, "refused")
+
+		_, _, err := moveResource(ctx, store, moveProviderManager(errors.New("unsupported move"), false), record, providerConfig, providerConfig, "aws_s3_object")
+		require.ErrorContains(t, err, "unsupported move")
+
+		stored, err := store.GetState(ctx, "refused")
+		require.NoError(t, err)
+		require.Equal(t, "aws_s3_bucket_object", stored.ResourceType)
+
+		operation, err := store.GetResourceOperation(ctx, "refused")
+		require.NoError(t, err)
+		require.NotNil(t, operation.Failed)
+	})
+
+	t.Run("marks states written by a development override", func(t *testing.T) {
+		record := saveRecord(t, "overridden")
+
+		_, _, err := moveResource(ctx, store, moveProviderManager(nil, true), record, providerConfig, providerConfig, "aws_s3_object")
+		require.NoError(t, err)
+
+		stored, err := store.GetState(ctx, "overridden")
+		require.NoError(t, err)
+		require.True(t, stored.DevOverride)
+	})
+}
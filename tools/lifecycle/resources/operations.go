@@ -36,7 +36,11 @@ func Operations(storage types.Storage) i.Tool {
 			"infrastructure operation history and understand what actions have been performed " +
 			"on managed resources. LOW risk read-only operation for auditing and analysis. " +
 			"\n\nPresentation: Present operation history with clear status indicators " +
-			"(SUCCEEDED/FAILED), timestamps, and policy evaluation results (allow/deny). " +
+			"(SUCCEEDED/FAILED/INTERRUPTED/TIMED OUT), timestamps, and policy evaluation results (allow/deny). " +
+			"Interrupted operations were stopped before the provider finished, e.g. on shutdown; the state " +
+			"saved for the resource is what the provider reported when it stopped and should be refreshed. " +
+			"Timed out operations ran out of the server operation timeout and were stopped the same way. " +
+			"Show the errors and warnings in diagnostics with the attribute they refer to. " +
 			"Include both human-readable summary and JSON format for programmatic access. " +
 			"\n\nCritical for tracking resource lifecycle events and maintaining operational " +
 			"visibility of infrastructure changes.",
@@ -0,0 +1,276 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type planArgs struct {
+	ResourceID      string         `json:"resource_id"`
+	Config          map[string]any `json:"config"`
+	Provider        string         `json:"provider,omitempty"`
+	ResourceType    string         `json:"resource_type,omitempty"`
+	ProviderVersion string         `json:"provider_version,omitempty"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
+	Destroy         bool           `json:"destroy,omitempty"`
+	// Timeouts are part of the planned configuration, the create or update applying the plan must pass the same
+	Timeouts map[string]string `json:"timeouts,omitempty"`
+}
+
+func (args planArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.Provider,
+		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
+		Config:  args.ProviderConfig,
+	}
+}
+
+func (args planArgs) hasCreateArgs() bool {
+	return args.Provider != "" || args.ResourceType != "" || args.ProviderVersion != "" ||
+		len(args.ProviderConfig) > 0 || args.ProviderAlias != ""
+}
+
+func Plan(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("lifecycle-resources-plan"),
+		Description: "Preview what lifecycle-resources-create, lifecycle-resources-update or lifecycle-resources-delete " +
+			"would do without changing any infrastructure. If resource_id exists in the state the update is planned " +
+			"(or the delete with destroy=true), otherwise the create is planned and provider, resource_type and " +
+			"provider_version are required. " +
+			"\n\nReturns a plan_id to pass to the matching create, update or delete call, which then applies exactly " +
+			"this plan. The plan expires after a while and is refused if the resource state or the arguments change " +
+			"in the meantime - plan again in that case. " +
+			"\n\nReturns the overall action (create, update, replace, delete, no-op) and an attribute-level diff: " +
+			"added, updated and removed attributes with before/after values, attributes only known after apply " +
+			"(computed) and attributes whose change forces the resource to be destroyed and recreated. " +
+			"\n\nUse this in the Planning Phase before any create, update or delete and get user confirmation of " +
+			"the plan before applying it. A 'replace' action means the resource will be destroyed and recreated, which is HIGH risk: " +
+			"apply it with lifecycle-resources-update and a replace_strategy. " +
+			"\n\nPresentation: Present the plan using Infrastructure Configuration Analysis format with a CREATE or " +
+			"MODIFY section listing each attribute change. Mark computed values as '(known after apply)', never show " +
+			"sensitive values, and prominently flag attributes that force replacement.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Plan a resource change", i.OpenWorld)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"resource_id": map[string]any{
+					"type":        "string",
+					"description": "Unique identifier of the resource to plan. An existing resource plans an update, a new one plans a create",
+				},
+				"config": map[string]any{
+					"type":        "object",
+					"description": "Configuration parameters for the resource, as they will be passed to create or update. Omit when destroy is true",
+				},
+				"destroy": map[string]any{
+					"type":        "boolean",
+					"description": "Plan deleting the existing resource instead of updating it",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws', 'hashicorp/random'). Only for planning a create",
+				},
+				"resource_type": map[string]any{
+					"type":        "string",
+					"description": "The resource type to create (e.g., 'random_string', 'aws_instance'). Only for planning a create",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3'). Only for planning a create",
+				},
+				"provider_config": map[string]any{
+					"type":        "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). Only for planning a create",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set. Only for planning a create",
+				},
+				"timeouts": map[string]any{
+					"type": "object",
+					"description": "Optional provider timeouts, as they will be passed to create or update (e.g., {\"create\": \"60m\"}). " +
+						"Ignored when destroy is true - pass delete timeouts to lifecycle-resources-delete",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+			},
+			Required: []string{"resource_id"},
+		},
+	}, Handler: plan(storage, providerManager, registryClient, policy)}
+}
+
+func plan(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args planArgs) (*mcp.CallToolResult, error) {
+		record, err := storage.GetState(ctx, args.ResourceID)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to get current state: %v", err)), nil
+		}
+
+		var providerConfig *types.ProviderConfig
+		var currentState *types.ResourceState
+		var diagnostics types.Diagnostics
+		planRecord := types.PlanRecord{
+			ResourceID: args.ResourceID,
+			Config:     args.Config,
+		}
+
+		if record != nil {
+			if args.hasCreateArgs() {
+				return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' already exists - omit provider arguments to plan an update", args.ResourceID)), nil
+			}
+
+			// If provider version is missing, search for it
+			if record.ProviderVersion == "" {
+				providerResult, err := provider.SearchForProvider(ctx, registryClient, record.Provider)
+				if err != nil {
+					return i.NewToolResultError(fmt.Sprintf("Failed to retrieve provider version: %v", err)), nil
+				}
+				record.ProviderVersion = providerResult.Provider.Version
+			}
+
+			providerConfig = record.GetProvider()
+			if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+				return i.NewToolResultError(err.Error()), nil
+			}
+
+			// Plan against state upgraded to the current resource schema version, applying the plan saves it
+			if _, diagnostics, err = upgradeRecord(ctx, providerManager, providerConfig, record); err != nil {
+				return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+			}
+
+			currentState = record.GetResourceState()
+			planRecord.Operation = "update"
+			planRecord.Provider = record.Provider
+			planRecord.ProviderVersion = record.ProviderVersion
+			planRecord.ResourceType = record.ResourceType
+			planRecord.ProviderConfig = record.ProviderConfig
+			planRecord.ProviderAlias = record.ProviderAlias
+
+			if planRecord.PriorStateHash, err = stateHash(record); err != nil {
+				return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+			}
+		} else {
+			if args.Destroy {
+				return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found", args.ResourceID)), nil
+			}
+			if args.Provider == "" || args.ResourceType == "" || args.ProviderVersion == "" {
+				return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found - provider, resource_type and provider_version are required to plan a create", args.ResourceID)), nil
+			}
+
+			providerConfig = args.GetProvider()
+			if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+				return i.NewToolResultError(err.Error()), nil
+			}
+
+			planRecord.Operation = "create"
+			planRecord.Provider = args.Provider
+			planRecord.ProviderVersion = args.ProviderVersion
+			planRecord.ResourceType = args.ResourceType
+			planRecord.ProviderConfig = args.ProviderConfig
+			planRecord.ProviderAlias = args.ProviderAlias
+		}
+
+		var resourcePlan *types.ResourcePlan
+		var warnings types.Diagnostics
+		if args.Destroy {
+			planRecord.Operation = "delete"
+			planRecord.Config = nil
+			resourcePlan = destroyPlan(record.State)
+		} else {
+			if args.Config == nil {
+				return i.WithDiagnostics(i.NewToolResultError("'config' is required unless destroy is true"), diagnostics), nil
+			}
+
+			if args.Config, err = withTimeouts(ctx, providerManager, providerConfig, planRecord.ResourceType, planRecord.Operation, args.Config, args.Timeouts, policy); err != nil {
+				return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+			}
+			planRecord.Config = args.Config
+
+			var invalid *mcp.CallToolResult
+			if warnings, invalid = validateConfig(ctx, providerManager, providerConfig, planRecord.ResourceType, currentState, args.Config); invalid != nil {
+				return i.WithDiagnostics(invalid, diagnostics), nil
+			}
+
+			var planDiagnostics types.Diagnostics
+			resourcePlan, planDiagnostics, err = providerManager.PlanResource(ctx, providerConfig, planRecord.ResourceType, currentState, args.Config)
+			diagnostics = append(diagnostics, planDiagnostics...)
+			if err != nil {
+				return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to plan resource: %v", err)), diagnostics), nil
+			}
+
+			planRecord.PlannedState = resourcePlan.PlannedState.State
+			planRecord.PlannedPrivate = resourcePlan.PlannedState.Private
+			if resourcePlan.Action == "replace" {
+				planRecord.RequiresReplace = resourcePlan.RequiresReplace
+			}
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to generate plan ID: %v", err)), diagnostics), nil
+		}
+		planRecord.ID = id.String()
+		planRecord.ExpiresAt = time.Now().Add(policy.TTL).UTC().Truncate(time.Second)
+
+		planID, err := signPlanID(policy.SigningKey, planRecord)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		if err := storage.SavePlan(ctx, planRecord); err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to save plan: %v", err)), diagnostics), nil
+		}
+
+		response := map[string]any{
+			"plan_id":          planID,
+			"expires_at":       planRecord.ExpiresAt.Format(time.RFC3339),
+			"resource_id":      args.ResourceID,
+			"resource_type":    planRecord.ResourceType,
+			"provider":         planRecord.Provider,
+			"provider_version": planRecord.ProviderVersion,
+			"action":           resourcePlan.Action,
+			"changes":          resourcePlan.Changes,
+			"requires_replace": resourcePlan.RequiresReplace,
+		}
+		if len(warnings) > 0 {
+			response["warnings"] = warnings
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, planRecord.Provider))
+	})
+}
+
+// destroyPlan describes deleting a resource: every attribute in the state is removed
+func destroyPlan(state map[string]any) *types.ResourcePlan {
+	resourcePlan := &types.ResourcePlan{Action: "delete", Changes: []types.AttributeChange{}}
+
+	for _, name := range slices.Sorted(maps.Keys(state)) {
+		if state[name] == nil {
+			continue
+		}
+		resourcePlan.Changes = append(resourcePlan.Changes, types.AttributeChange{
+			Attribute: name,
+			Action:    "remove",
+			Before:    state[name],
+		})
+	}
+
+	return resourcePlan
+}
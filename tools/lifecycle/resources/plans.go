@@ -0,0 +1,137 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// planFingerprint hashes everything a plan was made for, apart from its ID and expiry
+func planFingerprint(plan types.PlanRecord) (string, error) {
+	data, err := json.Marshal(map[string]any{
+		"resource_id":      plan.ResourceID,
+		"operation":        plan.Operation,
+		"provider":         plan.Provider,
+		"provider_version": plan.ProviderVersion,
+		"resource_type":    plan.ResourceType,
+		"provider_config":  plan.ProviderConfig,
+		"provider_alias":   plan.ProviderAlias,
+		"config":           plan.Config,
+		"prior_state_hash": plan.PriorStateHash,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize plan: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// planSignature computes the HMAC that binds a plan ID to the plan contents and expiry
+func planSignature(key []byte, plan types.PlanRecord) (string, error) {
+	fingerprint, err := planFingerprint(plan)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%d", plan.ID, fingerprint, plan.ExpiresAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signPlanID returns the plan_id handed to clients: the stored plan ID followed by its signature
+func signPlanID(key []byte, plan types.PlanRecord) (string, error) {
+	signature, err := planSignature(key, plan)
+	if err != nil {
+		return "", err
+	}
+
+	return plan.ID + "." + signature, nil
+}
+
+// stateHash identifies the stored state of a resource, empty when the resource does not exist
+func stateHash(record *types.StateRecord) (string, error) {
+	if record == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"provider_version": record.ProviderVersion,
+		"state":            record.State,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize state: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// loadPlan returns the plan a mutating tool must apply, or nil when none was given and plans are optional.
+// want describes the requested change and the current state; any difference from the plan is an error.
+func loadPlan(ctx context.Context, storage types.Storage, policy types.ApplyPolicy, planID string, want types.PlanRecord) (*types.PlanRecord, error) {
+	if planID == "" {
+		if policy.Required {
+			return nil, fmt.Errorf("plan_id is required: call lifecycle-resources-plan first and pass the returned plan_id")
+		}
+		return nil, nil
+	}
+
+	id, signature, ok := strings.Cut(planID, ".")
+	if !ok {
+		return nil, fmt.Errorf("invalid plan_id '%s'", planID)
+	}
+
+	plan, err := storage.GetPlan(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("plan '%s' not found, it may have expired or already been applied", planID)
+	}
+
+	expected, err := planSignature(policy.SigningKey, *plan)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, fmt.Errorf("invalid plan_id '%s'", planID)
+	}
+
+	if time.Now().After(plan.ExpiresAt) {
+		return nil, fmt.Errorf("plan '%s' expired at %s, plan again", planID, plan.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if plan.ResourceID != want.ResourceID || plan.Operation != want.Operation {
+		return nil, fmt.Errorf("plan '%s' was made to %s resource '%s', not to %s resource '%s'",
+			planID, plan.Operation, plan.ResourceID, want.Operation, want.ResourceID)
+	}
+
+	if plan.PriorStateHash != want.PriorStateHash {
+		return nil, fmt.Errorf("state of resource '%s' changed since plan '%s' was made, plan again", want.ResourceID, planID)
+	}
+
+	planned, err := planFingerprint(*plan)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := planFingerprint(want)
+	if err != nil {
+		return nil, err
+	}
+	if planned != requested {
+		return nil, fmt.Errorf("arguments differ from plan '%s', plan again with the new arguments", planID)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+func TestLoadPlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	policy := types.ApplyPolicy{TTL: time.Hour, SigningKey: []byte("key")}
+
+	record := &types.StateRecord{
+		ResourceID:      "web",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_instance",
+		State:           map[string]any{"id": "i-123", "instance_type": "t3.micro"},
+	}
+	priorStateHash, err := stateHash(record)
+	require.NoError(t, err)
+
+	want := types.PlanRecord{
+		ResourceID:      "web",
+		Operation:       "update",
+		Provider:        "hashicorp/aws",
+		ProviderVersion: "6.2.0",
+		ResourceType:    "aws_instance",
+		Config:          map[string]any{"instance_type": "t3.small"},
+		PriorStateHash:  priorStateHash,
+	}
+
+	savePlan := func(t *testing.T, id string, expiresAt time.Time) string {
+		plan := want
+		plan.ID = id
+		plan.ExpiresAt = expiresAt.UTC().Truncate(time.Second)
+		require.NoError(t, store.SavePlan(ctx, plan))
+
+		planID, err := signPlanID(policy.SigningKey, plan)
+		require.NoError(t, err)
+		return planID
+	}
+
+	t.Run("optional plan", func(t *testing.T) {
+		plan, err := loadPlan(ctx, store, policy, "", want)
+		require.NoError(t, err)
+		require.Nil(t, plan)
+	})
+
+	t.Run("required plan", func(t *testing.T) {
+		required := policy
+		required.Required = true
+
+		_, err := loadPlan(ctx, store, required, "", want)
+		require.ErrorContains(t, err, "plan_id is required")
+	})
+
+	t.Run("valid plan", func(t *testing.T) {
+		planID := savePlan(t, "valid", time.Now().Add(time.Hour))
+
+		plan, err := loadPlan(ctx, store, policy, planID, want)
+		require.NoError(t, err)
+		require.NotNil(t, plan)
+		require.Equal(t, "valid", plan.ID)
+	})
+
+	t.Run("forged signature", func(t *testing.T) {
+		planID := savePlan(t, "forged", time.Now().Add(time.Hour))
+		id, _, _ := strings.Cut(planID, ".")
+
+		_, err := loadPlan(ctx, store, policy, id+".deadbeef", want)
+		require.ErrorContains(t, err, "invalid plan_id")
+
+		otherKey := types.ApplyPolicy{SigningKey: []byte("other")}
+		_, err = loadPlan(ctx, store, otherKey, planID, want)
+		require.ErrorContains(t, err, "invalid plan_id")
+	})
+
+	t.Run("unknown plan", func(t *testing.T) {
+		_, err := loadPlan(ctx, store, policy, "missing.signature", want)
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("expired plan", func(t *testing.T) {
+		planID := savePlan(t, "expired", time.Now().Add(-time.Minute))
+
+		_, err := loadPlan(ctx, store, policy, planID, want)
+		require.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("state changed", func(t *testing.T) {
+		planID := savePlan(t, "state-changed", time.Now().Add(time.Hour))
+
+		changed := *record
+		changed.State = map[string]any{"id": "i-123", "instance_type": "t3.large"}
+		changedHash, err := stateHash(&changed)
+		require.NoError(t, err)
+
+		current := want
+		current.PriorStateHash = changedHash
+
+		_, err = loadPlan(ctx, store, policy, planID, current)
+		require.ErrorContains(t, err, "changed since plan")
+	})
+
+	t.Run("different operation", func(t *testing.T) {
+		planID := savePlan(t, "different-operation", time.Now().Add(time.Hour))
+
+		current := want
+		current.Operation = "delete"
+		current.Config = nil
+
+		_, err := loadPlan(ctx, store, policy, planID, current)
+		require.ErrorContains(t, err, "was made to update resource 'web'")
+	})
+
+	t.Run("different config", func(t *testing.T) {
+		planID := savePlan(t, "different-config", time.Now().Add(time.Hour))
+
+		current := want
+		current.Config = map[string]any{"instance_type": "t3.large"}
+
+		_, err := loadPlan(ctx, store, policy, planID, current)
+		require.ErrorContains(t, err, "arguments differ")
+	})
+}
+
+func TestStateHash(t *testing.T) {
+	t.Parallel()
+
+	hash, err := stateHash(nil)
+	require.NoError(t, err)
+	require.Empty(t, hash)
+
+	record := &types.StateRecord{ProviderVersion: "1.0.0", State: map[string]any{"b": 1, "a": "x"}}
+	first, err := stateHash(record)
+	require.NoError(t, err)
+
+	second, err := stateHash(&types.StateRecord{ProviderVersion: "1.0.0", State: map[string]any{"a": "x", "b": 1}})
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	record.ProviderVersion = "1.1.0"
+	third, err := stateHash(record)
+	require.NoError(t, err)
+	require.NotEqual(t, first, third)
+}
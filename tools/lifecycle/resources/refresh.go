@@ -12,6 +12,7 @@ import (
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
 	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
@@ -65,6 +66,17 @@ func refresh(storage types.Storage, providerManager types.ProviderManager, regis
 			record.ProviderVersion = providerResult.Provider.Version
 		}
 
+		providerConfig := record.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		// Upgrade state written with an older resource schema version
+		diagnostics, err := upgradeState(ctx, storage, providerManager, providerConfig, record)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
 		// Parse the stored state
 		input := types.ResourceOperationInput{
 			ResourceID:      args.ResourceID,
@@ -77,22 +89,30 @@ func refresh(storage types.Storage, providerManager types.ProviderManager, regis
 
 		operation, err := newResourceOperation(input)
 		if err != nil {
-			return i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), nil
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), diagnostics), nil
 		}
 
+		var refreshDiagnostics types.Diagnostics
 		defer func() {
 			if err != nil {
 				errMessage := err.Error()
 				operation.Failed = &errMessage
 			}
+			operation.Diagnostics = refreshDiagnostics
 			storage.SaveResourceOperation(ctx, operation)
 		}()
 
 		// Refresh the resource using the provider manager
-		stateResult, err := providerManager.RefreshResource(ctx, record.GetProvider(), record.ResourceType, record.State)
+		refreshedState, refreshDiagnostics, err := providerManager.RefreshResource(ctx, providerConfig, record.ResourceType, record.GetResourceState())
+		diagnostics = append(diagnostics, refreshDiagnostics...)
 		if err != nil {
 			err = fmt.Errorf("failed to refresh resource: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		var stateResult map[string]any
+		if !refreshedState.IsEmpty() {
+			stateResult = refreshedState.State
 		}
 
 		// Determine status and message based on state changes
@@ -115,6 +135,11 @@ func refresh(storage types.Storage, providerManager types.ProviderManager, regis
 				ProviderVersion: record.ProviderVersion,
 				ResourceType:    record.ResourceType,
 				State:           stateResult,
+				ProviderConfig:  record.ProviderConfig,
+				ProviderAlias:   record.ProviderAlias,
+				Private:         refreshedState.Private,
+				SchemaVersion:   i.PtrTo(refreshedState.SchemaVersion),
+				DevOverride:     providerManager.IsDevOverride(record.Provider),
 			}
 
 			// Add operation context for automatic history tracking
@@ -125,19 +150,24 @@ func refresh(storage types.Storage, providerManager types.ProviderManager, regis
 
 			if err = storage.SaveState(ctx, updatedRecord); err != nil {
 				err = fmt.Errorf("failed to save refreshed state: %w", err)
-				return i.NewToolResultError(err.Error()), nil
+				return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 			}
 
 		}
 
-		return i.RespondJSON(map[string]any{
+		response := map[string]any{
 			"provider":         record.GetProvider().Name,
 			"provider_version": record.GetProvider().Version,
 			"resource_id":      args.ResourceID,
 			"status":           status,
 			"message":          message,
 			"result":           responseState,
-		})
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, record.GetProvider().Name))
 	},
 	)
 }
@@ -0,0 +1,209 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+const (
+	destroyBeforeCreate = "destroy-before-create"
+	createBeforeDestroy = "create-before-destroy"
+)
+
+// replaceResource replaces a resource whose change cannot be applied in place.
+// The destroy and the create are recorded as separate operations, each of them gets the server operation
+// timeout. The new state is returned together with the error when the replacement exists but the
+// replace failed: with create-before-destroy when destroying the old resource failed, with
+// destroy-before-create when the provider returned the partial state of a failed create. When the old
+// resource was destroyed and nothing replaced it, its stored state is removed. The diagnostics the
+// provider reported for both are returned.
+func replaceResource(ctx context.Context, storage types.Storage, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, record *types.StateRecord, config map[string]any, strategy string, policy types.ApplyPolicy) (*types.ResourceState, types.Diagnostics, error) {
+	description, err := providerManager.DescribeResource(ctx, providerConfig, record.ResourceType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe resource: %w", err)
+	}
+
+	var diagnostics types.Diagnostics
+	destroy := func() error {
+		diags, err := recordOperation(ctx, storage, types.ResourceOperationInput{
+			ResourceID:      record.ResourceID,
+			ResourceType:    record.ResourceType,
+			Provider:        record.Provider,
+			ProviderVersion: record.ProviderVersion,
+			Operation:       "replace-delete",
+			CurrentState:    record.State,
+		}, func(_ *types.ResourceOperation) (diags types.Diagnostics, err error) {
+			err = withOperationTimeout(ctx, policy, func(ctx context.Context) (err error) {
+				diags, err = providerManager.DeleteResource(ctx, providerConfig, record.ResourceType, record.GetResourceState())
+				return err
+			})
+			return diags, err
+		})
+		diagnostics = append(diagnostics, diags...)
+		return err
+	}
+
+	var newState *types.ResourceState
+	create := func() error {
+		applied, err := lastAppliedConfig(ctx, storage, record)
+		if err != nil {
+			return err
+		}
+		newConfig := replacementConfig(description.Properties, applied, record.State, config)
+
+		diags, err := recordOperation(ctx, storage, types.ResourceOperationInput{
+			ResourceID:      record.ResourceID,
+			ResourceType:    record.ResourceType,
+			Provider:        record.Provider,
+			ProviderVersion: record.ProviderVersion,
+			Operation:       "replace-create",
+			ProposedState:   newConfig,
+		}, func(operation *types.ResourceOperation) (diags types.Diagnostics, err error) {
+			err = withOperationTimeout(ctx, policy, func(ctx context.Context) (err error) {
+				newState, diags, err = providerManager.CreateResource(ctx, providerConfig, record.ResourceType, newConfig)
+				return err
+			})
+			if err == nil && newState.IsEmpty() {
+				err = fmt.Errorf("provider returned empty state")
+			}
+			// An interrupted or timed out create may have created the resource partway
+			var interrupted *types.InterruptedError
+			if errors.As(err, &interrupted) && newState.IsEmpty() {
+				newState = interrupted.State
+			}
+			// A failed create may have created the resource anyway, its partial state is kept with the operation
+			if err != nil && !newState.IsEmpty() {
+				operation.ProposedState = newState.State
+			}
+			return diags, err
+		})
+		diagnostics = append(diagnostics, diags...)
+		return err
+	}
+
+	switch strategy {
+	case destroyBeforeCreate:
+		if err := destroy(); err != nil {
+			return nil, diagnostics, fmt.Errorf("failed to destroy resource, nothing was replaced: %w", err)
+		}
+		if err := create(); err != nil {
+			// The partially created replacement takes the place of the destroyed resource
+			if !newState.IsEmpty() {
+				return newState, diagnostics, fmt.Errorf("resource was destroyed and its replacement was only partially created: %w", err)
+			}
+			if deleteErr := deleteDestroyedState(ctx, storage, record.ResourceID); deleteErr != nil {
+				return nil, diagnostics, fmt.Errorf("resource was destroyed but creating its replacement failed, and removing its stored state failed: %w: %w", err, deleteErr)
+			}
+			return nil, diagnostics, fmt.Errorf("resource was destroyed but creating its replacement failed, its stored state was removed: %w", err)
+		}
+	case createBeforeDestroy:
+		if err := create(); err != nil {
+			if !newState.IsEmpty() {
+				return nil, diagnostics, fmt.Errorf("the replacement resource was only partially created and the previous one was kept, "+
+					"the state of the replacement is kept in the failed replace-create operation: %w", err)
+			}
+			return nil, diagnostics, fmt.Errorf("failed to create replacement resource, nothing was replaced: %w", err)
+		}
+		if err := destroy(); err != nil {
+			return newState, diagnostics, fmt.Errorf("replacement resource was created but destroying the previous one failed, "+
+				"its state is kept in the failed replace-delete operation: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown replace strategy '%s'", strategy)
+	}
+
+	return newState, diagnostics, nil
+}
+
+// replacementConfig builds the configuration of a replacement resource, overridden by the new config: the
+// last applied configuration if there is one, otherwise the attributes the user can set taken from the
+// current state. Computed values belong to the old resource.
+func replacementConfig(properties map[string]any, applied, currentState, newConfig map[string]any) map[string]any {
+	config := maps.Clone(applied)
+	if config == nil {
+		config = map[string]any{}
+		for name, value := range currentState {
+			info, _ := properties[name].(map[string]any)
+			// Optional computed values may have been set by the user and are kept. The ID is optional
+			// and computed in SDKv2 schemas, but always identifies the old resource.
+			if usage, _ := info["usage"].(string); usage == "computed" || name == "id" {
+				continue
+			}
+			config[name] = value
+		}
+	}
+	maps.Copy(config, newConfig)
+
+	return config
+}
+
+// lastAppliedConfig rebuilds the configuration a resource was last applied with from its successful
+// operations: the config of its create, with the configs of later updates merged over it like the provider
+// manager merges them over the state. It is nil when the history does not have it, e.g. for imported or
+// moved resources.
+func lastAppliedConfig(ctx context.Context, storage types.Storage, record *types.StateRecord) (map[string]any, error) {
+	ops, err := storage.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &record.ResourceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource operations: %w", err)
+	}
+
+	// Operation IDs are UUIDv7, ordered by the time the operations started
+	slices.SortFunc(ops, func(a, b types.ResourceOperation) int { return strings.Compare(a.ID, b.ID) })
+
+	var config map[string]any
+	for _, op := range ops {
+		if op.Failed != nil {
+			continue
+		}
+		switch op.Operation {
+		case "create", "replace-create":
+			config = maps.Clone(op.ProposedState)
+		case "update":
+			if config != nil {
+				maps.Copy(config, op.ProposedState)
+			}
+		case "import", "move":
+			config = nil
+		}
+	}
+
+	return config, nil
+}
+
+// deleteDestroyedState removes the stored state of a resource that was destroyed and not replaced, so that
+// it is not taken for a live resource. The tool call may have been cancelled, the removal still goes through.
+func deleteDestroyedState(ctx context.Context, storage types.Storage, resourceID string) error {
+	ctx = context.WithValue(context.WithoutCancel(ctx), types.OperationContextKey, "replace-delete")
+	ctx = context.WithValue(ctx, types.ChangedByContextKey, "mcp-user")
+
+	return storage.DeleteState(ctx, resourceID)
+}
+
+// recordOperation runs a single provider call and records it as a resource operation, together with
+// the diagnostics the call returns. run may add its outcome to the operation.
+func recordOperation(ctx context.Context, storage types.Storage, input types.ResourceOperationInput, run func(operation *types.ResourceOperation) (types.Diagnostics, error)) (types.Diagnostics, error) {
+	operation, err := newResourceOperation(input)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics, err := run(&operation)
+	if err != nil {
+		errMessage := err.Error()
+		operation.Failed = &errMessage
+	}
+	ctx, _ = markInterrupted(ctx, err, &operation)
+	operation.Diagnostics = diagnostics
+	storage.SaveResourceOperation(ctx, operation)
+
+	return diagnostics, err
+}
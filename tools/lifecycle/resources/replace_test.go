@@ -0,0 +1,282 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// replaceProviderManager creates resources from their config, failing with createErr when set, and
+// deletes them, failing with deleteErr when set. With partial, a failed create still returns the state
+// of what it created.
+func replaceProviderManager(createErr error, partial bool, deleteErr error) *fakeProviderManager {
+	return &fakeProviderManager{
+		describe: func(*types.ProviderConfig, string) (*types.TypeDescription, error) {
+			return &types.TypeDescription{Properties: map[string]any{
+				"id":   map[string]any{"usage": "computed"},
+				"name": map[string]any{"usage": "required"},
+				"zone": map[string]any{"usage": "optional"},
+				"arn":  map[string]any{"usage": "optional_computed"},
+			}}, nil
+		},
+		create: func(config map[string]any) (*types.ResourceState, types.Diagnostics, error) {
+			if createErr != nil && !partial {
+				return nil, nil, createErr
+			}
+			if createErr != nil {
+				return &types.ResourceState{State: map[string]any{"id": "new", "name": config["name"], "status": "tainted"}}, nil, createErr
+			}
+			return &types.ResourceState{State: map[string]any{"id": "new", "name": config["name"], "zone": config["zone"]}}, nil, nil
+		},
+		delete: func(context.Context) (types.Diagnostics, error) {
+			return nil, deleteErr
+		},
+	}
+}
+
+func TestReplaceResource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	newRecord := func(id string) *types.StateRecord {
+		return &types.StateRecord{
+			ResourceID:   id,
+			Provider:     "hashicorp/example",
+			ResourceType: "example_server",
+			State:        map[string]any{"id": "old", "name": "web", "zone": "a", "arn": "arn:old"},
+		}
+	}
+
+	operations := func(t *testing.T, id string) map[string]bool {
+		ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &id})
+		require.NoError(t, err)
+
+		failed := map[string]bool{}
+		for _, op := range ops {
+			failed[op.Operation] = op.Failed != nil
+		}
+		return failed
+	}
+
+	t.Run("destroy before create", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, nil)
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("dbc"), map[string]any{"zone": "b"}, destroyBeforeCreate, types.ApplyPolicy{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"delete", "create"}, manager.calls)
+		require.Equal(t, map[string]any{"id": "new", "name": "web", "zone": "b"}, newState.State)
+		require.Equal(t, map[string]any{"name": "web", "zone": "b", "arn": "arn:old"}, manager.config, "optional computed values may be set by the user")
+		require.Equal(t, map[string]bool{"replace-delete": false, "replace-create": false}, operations(t, "dbc"))
+	})
+
+	t.Run("replacement config is the last applied config", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, nil)
+		record := newRecord("applied")
+		operation, err := newResourceOperation(types.ResourceOperationInput{
+			ResourceID:    record.ResourceID,
+			ResourceType:  record.ResourceType,
+			Operation:     "create",
+			ProposedState: map[string]any{"name": "web"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.SaveResourceOperation(ctx, operation))
+
+		_, _, err = replaceResource(ctx, store, manager, &types.ProviderConfig{}, record, map[string]any{"zone": "b"}, destroyBeforeCreate, types.ApplyPolicy{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"name": "web", "zone": "b"}, manager.config)
+	})
+
+	t.Run("create before destroy", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, nil)
+
+		_, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("cbd"), map[string]any{"zone": "b"}, createBeforeDestroy, types.ApplyPolicy{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"create", "delete"}, manager.calls)
+	})
+
+	t.Run("create before destroy keeps new state when destroy fails", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, errors.New("in use"))
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("cbd-fail"), nil, createBeforeDestroy, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "destroying the previous one failed")
+		require.False(t, newState.IsEmpty())
+		require.Equal(t, map[string]bool{"replace-create": false, "replace-delete": true}, operations(t, "cbd-fail"))
+	})
+
+	t.Run("destroy before create stops when destroy fails", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, errors.New("in use"))
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("dbc-fail"), nil, destroyBeforeCreate, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "nothing was replaced")
+		require.True(t, newState.IsEmpty())
+		require.Equal(t, []string{"delete"}, manager.calls)
+	})
+
+	t.Run("destroy before create removes the state of the destroyed resource when nothing replaced it", func(t *testing.T) {
+		manager := replaceProviderManager(errors.New("quota exceeded"), false, nil)
+		record := newRecord("dbc-create-fail")
+		require.NoError(t, store.SaveState(ctx, *record))
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, record, nil, destroyBeforeCreate, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "its stored state was removed")
+		require.True(t, newState.IsEmpty())
+
+		stored, err := store.GetState(ctx, record.ResourceID)
+		require.NoError(t, err)
+		require.Nil(t, stored, "the destroyed resource is not kept as live state")
+	})
+
+	t.Run("destroy before create returns the partial state of a failed create", func(t *testing.T) {
+		manager := replaceProviderManager(errors.New("instance failed to start"), true, nil)
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("dbc-partial"), nil, destroyBeforeCreate, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "only partially created")
+		require.Equal(t, "tainted", newState.State["status"], "the caller saves what was created")
+	})
+
+	t.Run("create before destroy keeps the partial state of a failed create with the operation", func(t *testing.T) {
+		manager := replaceProviderManager(errors.New("instance failed to start"), true, nil)
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("cbd-partial"), nil, createBeforeDestroy, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "kept in the failed replace-create operation")
+		require.True(t, newState.IsEmpty(), "the previous resource is still the stored one")
+		require.Equal(t, []string{"create"}, manager.calls)
+
+		id := "cbd-partial"
+		ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &id})
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "tainted", ops[0].ProposedState["status"])
+	})
+
+	t.Run("interrupted create returns the partial state of the replacement", func(t *testing.T) {
+		manager := replaceProviderManager(&types.InterruptedError{
+			State: &types.ResourceState{State: map[string]any{"id": "new", "status": "pending"}},
+			Err:   context.Canceled,
+		}, false, nil)
+
+		newState, _, err := replaceResource(ctx, store, manager, &types.ProviderConfig{}, newRecord("dbc-create-interrupted"), nil, destroyBeforeCreate, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "only partially created")
+		require.Equal(t, map[string]any{"id": "new", "status": "pending"}, newState.State)
+
+		id := "dbc-create-interrupted"
+		ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &id})
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		for _, op := range ops {
+			if op.Operation == "replace-create" {
+				require.True(t, op.Interrupted)
+				require.Equal(t, "pending", op.ProposedState["status"])
+			}
+		}
+	})
+
+	t.Run("interrupted destroy is recorded even though the call was cancelled", func(t *testing.T) {
+		manager := replaceProviderManager(nil, false, &types.InterruptedError{Err: context.Canceled})
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, _, err := replaceResource(cancelled, store, manager, &types.ProviderConfig{}, newRecord("dbc-interrupted"), nil, destroyBeforeCreate, types.ApplyPolicy{})
+		require.ErrorContains(t, err, "nothing was replaced")
+
+		id := "dbc-interrupted"
+		ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &id})
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.True(t, ops[0].Interrupted)
+		require.NotNil(t, ops[0].Failed)
+	})
+}
+
+func TestReplacementConfig(t *testing.T) {
+	t.Parallel()
+
+	properties := map[string]any{
+		"id":   map[string]any{"usage": "computed"},
+		"arn":  map[string]any{"usage": "optional_computed"},
+		"name": map[string]any{"usage": "required"},
+		"zone": map[string]any{"usage": "optional"},
+		"rule": map[string]any{"nesting_mode": "list"},
+	}
+	state := map[string]any{
+		"id":   "i-1",
+		"arn":  "arn:1",
+		"name": "web",
+		"zone": "a",
+		"rule": []any{map[string]any{"port": 80}},
+	}
+
+	config := replacementConfig(properties, nil, state, map[string]any{"zone": "b"})
+
+	require.Equal(t, map[string]any{
+		"arn":  "arn:1",
+		"name": "web",
+		"zone": "b",
+		"rule": []any{map[string]any{"port": 80}},
+	}, config)
+
+	applied := map[string]any{"name": "web", "zone": "a"}
+	config = replacementConfig(properties, applied, state, map[string]any{"zone": "b"})
+
+	require.Equal(t, map[string]any{"name": "web", "zone": "b"}, config)
+	require.Equal(t, "a", applied["zone"])
+}
+
+func TestLastAppliedConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	record := &types.StateRecord{ResourceID: "applied", ResourceType: "example_server"}
+
+	config, err := lastAppliedConfig(ctx, store, record)
+	require.NoError(t, err)
+	require.Nil(t, config)
+
+	failed := "boom"
+	for _, op := range []struct {
+		operation string
+		config    map[string]any
+		failed    *string
+	}{
+		{operation: "create", config: map[string]any{"name": "web", "zone": "a"}},
+		{operation: "update", config: map[string]any{"zone": "b"}},
+		{operation: "update", config: map[string]any{"zone": "c"}, failed: &failed},
+	} {
+		operation, err := newResourceOperation(types.ResourceOperationInput{
+			ResourceID:    record.ResourceID,
+			ResourceType:  record.ResourceType,
+			Operation:     op.operation,
+			ProposedState: op.config,
+		})
+		require.NoError(t, err)
+		operation.Failed = op.failed
+		require.NoError(t, store.SaveResourceOperation(ctx, operation))
+	}
+
+	config, err = lastAppliedConfig(ctx, store, record)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "web", "zone": "b"}, config)
+
+	operation, err := newResourceOperation(types.ResourceOperationInput{
+		ResourceID:   record.ResourceID,
+		ResourceType: record.ResourceType,
+		Operation:    "import",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveResourceOperation(ctx, operation))
+
+	config, err = lastAppliedConfig(ctx, store, record)
+	require.NoError(t, err)
+	require.Nil(t, config)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// errOperationTimeout is the cause of cancelling provider calls that ran out of the server operation timeout
+var errOperationTimeout = errors.New("the server operation timeout was exceeded")
+
+// timeoutsSchema describes the timeouts argument of the tools changing resources
+var timeoutsSchema = map[string]any{
+	"type": "object",
+	"description": "Optional provider timeouts for resources that take long to change (e.g., {\"create\": \"60m\", \"delete\": \"2h\"}). " +
+		"Sets the timeouts block of the resource, only for resource types whose schema has one. " +
+		"Values are durations like '30s', '45m' or '2h'",
+	"additionalProperties": map[string]any{"type": "string"},
+}
+
+// withTimeouts sets the timeouts block of a resource configuration. The resource type must have one
+// supporting the given timeouts, and the timeout of the operation must fit in the server operation timeout.
+func withTimeouts(ctx context.Context, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, resourceType, operation string, config map[string]any, timeouts map[string]string, policy types.ApplyPolicy) (map[string]any, error) {
+	if len(timeouts) == 0 {
+		return config, nil
+	}
+
+	description, err := providerManager.DescribeResource(ctx, providerConfig, resourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe resource: %w", err)
+	}
+	info, _ := description.Properties["timeouts"].(map[string]any)
+	supported, _ := info["properties"].(map[string]any)
+	if len(supported) == 0 {
+		return nil, fmt.Errorf("resource type %s does not support timeouts", resourceType)
+	}
+
+	block := map[string]any{}
+	if current, ok := config["timeouts"].(map[string]any); ok {
+		maps.Copy(block, current)
+	}
+	for name, value := range timeouts {
+		if _, ok := supported[name]; !ok {
+			return nil, fmt.Errorf("resource type %s does not support a %s timeout, supported timeouts: %s",
+				resourceType, name, strings.Join(slices.Sorted(maps.Keys(supported)), ", "))
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s timeout %q: %w", name, value, err)
+		}
+		if name == operation && policy.OperationTimeout > 0 && timeout > policy.OperationTimeout {
+			return nil, fmt.Errorf("the %s timeout %s exceeds the server operation timeout of %s", name, value, policy.OperationTimeout)
+		}
+		block[name] = value
+	}
+
+	config = maps.Clone(config)
+	if config == nil {
+		config = map[string]any{}
+	}
+	config["timeouts"] = block
+
+	return config, nil
+}
+
+// withOperationTimeout runs the provider calls of an operation within the server operation timeout.
+// Running out of it is reported with errOperationTimeout, so that the operation is recorded as timed out.
+func withOperationTimeout(ctx context.Context, policy types.ApplyPolicy, run func(ctx context.Context) error) error {
+	if policy.OperationTimeout <= 0 {
+		return run(ctx)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, policy.OperationTimeout, errOperationTimeout)
+	defer cancel()
+
+	err := run(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), errOperationTimeout) && !errors.Is(err, errOperationTimeout) {
+		err = fmt.Errorf("%w: %w", errOperationTimeout, err)
+	}
+
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// timeoutsProviderManager describes resources with a timeouts block and lets deletes run until cancelled
+func timeoutsProviderManager() *fakeProviderManager {
+	return &fakeProviderManager{
+		describe: func(_ *types.ProviderConfig, resourceType string) (*types.TypeDescription, error) {
+			properties := map[string]any{"name": map[string]any{"usage": "required"}}
+			if resourceType == "example_database" {
+				properties["timeouts"] = map[string]any{
+					"is_block": true,
+					"nesting":  "single",
+					"properties": map[string]any{
+						"create": map[string]any{"usage": "optional"},
+						"delete": map[string]any{"usage": "optional"},
+					},
+				}
+			}
+			return &types.TypeDescription{Properties: properties}, nil
+		},
+		delete: func(ctx context.Context) (types.Diagnostics, error) {
+			<-ctx.Done()
+			return types.Diagnostics{{Severity: "warning", Summary: "Deletion protection is deprecated"}}, &types.InterruptedError{
+				State: &types.ResourceState{State: map[string]any{"name": "db", "status": "deleting"}},
+				Err:   context.Cause(ctx),
+			}
+		},
+	}
+}
+
+func TestWithTimeouts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager := timeoutsProviderManager()
+	policy := types.ApplyPolicy{OperationTimeout: time.Hour}
+
+	t.Run("sets the timeouts block", func(t *testing.T) {
+		config := map[string]any{"name": "db", "timeouts": map[string]any{"delete": "20m"}}
+
+		withBlock, err := withTimeouts(ctx, manager, &types.ProviderConfig{}, "example_database", "create", config, map[string]string{"create": "45m"}, policy)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"create": "45m", "delete": "20m"}, withBlock["timeouts"])
+		require.Equal(t, map[string]any{"delete": "20m"}, config["timeouts"], "the given config is left unchanged")
+	})
+
+	t.Run("no timeouts leave the config alone", func(t *testing.T) {
+		config := map[string]any{"name": "web"}
+
+		unchanged, err := withTimeouts(ctx, manager, &types.ProviderConfig{}, "example_server", "create", config, nil, policy)
+		require.NoError(t, err)
+		require.Equal(t, config, unchanged)
+	})
+
+	for name, tc := range map[string]struct {
+		resourceType string
+		timeouts     map[string]string
+		err          string
+	}{
+		"resource type without timeouts": {"example_server", map[string]string{"create": "10m"}, "resource type example_server does not support timeouts"},
+		"unsupported timeout":            {"example_database", map[string]string{"update": "10m"}, "does not support a update timeout, supported timeouts: create, delete"},
+		"invalid duration":               {"example_database", map[string]string{"create": "ten minutes"}, `invalid create timeout "ten minutes"`},
+		"longer than the server timeout": {"example_database", map[string]string{"create": "2h"}, "the create timeout 2h exceeds the server operation timeout of 1h0m0s"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := withTimeouts(ctx, manager, &types.ProviderConfig{}, tc.resourceType, "create", map[string]any{}, tc.timeouts, policy)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestOperationTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	record := &types.StateRecord{
+		ResourceID:   "db",
+		Provider:     "hashicorp/example",
+		ResourceType: "example_database",
+		State:        map[string]any{"name": "db"},
+	}
+	policy := types.ApplyPolicy{OperationTimeout: 10 * time.Millisecond}
+
+	_, diagnostics, err := replaceResource(ctx, store, timeoutsProviderManager(), &types.ProviderConfig{}, record, nil, destroyBeforeCreate, policy)
+	require.ErrorIs(t, err, errOperationTimeout)
+	require.Equal(t, types.Diagnostics{{Severity: "warning", Summary: "Deletion protection is deprecated"}}, diagnostics)
+
+	ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: &record.ResourceID})
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, "replace-delete", ops[0].Operation)
+	require.True(t, ops[0].TimedOut)
+	require.True(t, ops[0].Interrupted)
+	require.Contains(t, *ops[0].Failed, "the server operation timeout was exceeded")
+	require.Equal(t, types.Diagnostics{{Severity: "warning", Summary: "Deletion protection is deprecated"}}, ops[0].Diagnostics)
+}
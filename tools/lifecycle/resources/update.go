@@ -5,34 +5,46 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	i "github.com/spacelift-io/spacelift-intent/tools/internal"
 	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
 	"github.com/spacelift-io/spacelift-intent/types"
 )
 
 type updateArgs struct {
 	ResourceID string         `json:"resource_id"`
 	Config     map[string]any `json:"config"`
+	PlanID     string         `json:"plan_id,omitempty"`
+	// Timeouts sets the timeouts block of the resource, for provider operations that take long
+	Timeouts map[string]string `json:"timeouts,omitempty"`
+	// ReplaceStrategy opts into replacing the resource when the change cannot be applied in place
+	ReplaceStrategy string `json:"replace_strategy,omitempty"`
 }
 
-func Update(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.Tool {
+func Update(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.Tool {
 	return i.Tool{Tool: mcp.Tool{
 		Name: string("lifecycle-resources-update"),
 		Description: "Update an existing OpenTofu resource by ID with a new configuration. " +
 			"MEDIUM risk operation that requires policy evaluation and user approval for " +
 			"configuration changes. Handles internal planning and application automatically " +
-			"through the MCP abstraction layer. Validates configuration against existing state, " +
+			"through the MCP abstraction layer. Validates the configuration with the provider first (invalid configurations are rejected with diagnostics pointing to the attribute, without changing anything), " +
 			"enforces policies, and manages state persistence. " +
-			"\n\nArgument Handling: Set unknown/optional arguments to appropriate defaults: " +
-			"strings to null or '', booleans to null or false, numbers to null or 0, arrays " +
-			"to null or [], objects to null or {}. Ensure ALL required arguments are provided. " +
+			"\n\nArgument Handling: Only send the arguments you want to change - config is merged over the " +
+			"current state and anything still missing is filled from the schema with nulls and empty blocks. " +
 			"\n\nPresentation: Present results using Infrastructure Configuration Analysis " +
 			"format with MODIFY section and risk assessment. On errors, use OpenTofu MCP Server " +
-			"error format with root cause analysis and recommended fixes.",
+			"error format with root cause analysis and recommended fixes. " +
+			"\n\nPlan first: pass the plan_id returned by lifecycle-resources-plan with the same config to apply " +
+			"exactly the reviewed plan. The server may be configured to require it. " +
+			"\n\nReplacement: when the provider cannot apply a change in place the update is refused and the " +
+			"attributes forcing replacement are returned. Replacing is HIGH risk - explain that the resource will be " +
+			"destroyed and recreated, get explicit 'CONFIRM', then retry with replace_strategy. The resource_id and " +
+			"its dependencies are kept.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Update resource with a new configuration", i.Idempotent|i.OpenWorld)),
 		InputSchema: i.ToolInputSchema{
 			Type: "object",
@@ -45,13 +57,25 @@ func Update(storage types.Storage, providerManager types.ProviderManager, regist
 					"type":        "object",
 					"description": "New configuration parameters for the resource",
 				},
+				"plan_id": map[string]any{
+					"type":        "string",
+					"description": "Plan returned by lifecycle-resources-plan for this update. The config must match the plan",
+				},
+				"replace_strategy": map[string]any{
+					"type": "string",
+					"enum": []string{destroyBeforeCreate, createBeforeDestroy},
+					"description": "How to replace the resource if the change cannot be applied in place: " +
+						"'destroy-before-create' (downtime, no duplicates) or 'create-before-destroy' (no downtime, " +
+						"needs unique attributes like names to change). Without it such updates are refused",
+				},
+				"timeouts": timeoutsSchema,
 			},
 			Required: []string{"resource_id", "config"},
 		},
-	}, Handler: update(storage, providerManager, registryClient)}
+	}, Handler: update(storage, providerManager, registryClient, policy)}
 }
 
-func update(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.ToolHandler {
+func update(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient, policy types.ApplyPolicy) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args updateArgs) (*mcp.CallToolResult, error) {
 		// Get the current state from database
 		record, err := storage.GetState(ctx, args.ResourceID)
@@ -63,6 +87,12 @@ func update(storage types.Storage, providerManager types.ProviderManager, regist
 			return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found", args.ResourceID)), nil
 		}
 
+		switch args.ReplaceStrategy {
+		case "", destroyBeforeCreate, createBeforeDestroy:
+		default:
+			return i.NewToolResultError(fmt.Sprintf("Invalid replace_strategy '%s', use '%s' or '%s'", args.ReplaceStrategy, destroyBeforeCreate, createBeforeDestroy)), nil
+		}
+
 		// If provider version is missing, search for it
 		if record.ProviderVersion == "" {
 			providerResult, err := provider.SearchForProvider(ctx, registryClient, record.Provider)
@@ -72,6 +102,49 @@ func update(storage types.Storage, providerManager types.ProviderManager, regist
 			record.ProviderVersion = providerResult.Provider.Version
 		}
 
+		providerConfig := record.GetProvider()
+		if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		// Upgrade state written with an older resource schema version
+		diagnostics, err := upgradeState(ctx, storage, providerManager, providerConfig, record)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		config, err := withTimeouts(ctx, providerManager, providerConfig, record.ResourceType, "update", args.Config, args.Timeouts, policy)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+		args.Config = config
+
+		// Validate the configuration before anything is recorded or changed
+		warnings, invalid := validateConfig(ctx, providerManager, providerConfig, record.ResourceType, record.GetResourceState(), args.Config)
+		if invalid != nil {
+			return i.WithDiagnostics(invalid, diagnostics), nil
+		}
+
+		priorStateHash, err := stateHash(record)
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		plan, err := loadPlan(ctx, storage, policy, args.PlanID, types.PlanRecord{
+			ResourceID:      args.ResourceID,
+			Operation:       "update",
+			Provider:        record.Provider,
+			ProviderVersion: record.ProviderVersion,
+			ResourceType:    record.ResourceType,
+			ProviderConfig:  record.ProviderConfig,
+			ProviderAlias:   record.ProviderAlias,
+			Config:          args.Config,
+			PriorStateHash:  priorStateHash,
+		})
+		if err != nil {
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
 		// Parse the stored state
 		input := types.ResourceOperationInput{
 			ResourceID:      args.ResourceID,
@@ -85,29 +158,84 @@ func update(storage types.Storage, providerManager types.ProviderManager, regist
 
 		operation, err := newResourceOperation(input)
 		if err != nil {
-			return i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), nil
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to create operation resource: %v", err)), diagnostics), nil
 		}
 
+		var updateDiagnostics types.Diagnostics
+		var replacing bool
 		defer func() {
+			// A replacement records its destroy and create halves instead
+			if replacing {
+				return
+			}
 			if err != nil {
 				errMessage := err.Error()
 				operation.Failed = &errMessage
 			}
+			operation.Diagnostics = updateDiagnostics
 			storage.SaveResourceOperation(ctx, operation)
 		}()
 
-		// Update the resource using the provider manager
-		state, err := providerManager.UpdateResource(ctx, record.GetProvider(), record.ResourceType, record.State, args.Config)
-		if err != nil {
+		// Update the resource using the provider manager, applying the saved plan if there is one
+		var newState *types.ResourceState
+		err = withOperationTimeout(ctx, policy, func(ctx context.Context) (err error) {
+			switch {
+			case plan != nil && len(plan.RequiresReplace) > 0:
+				err = &types.ReplaceRequiredError{Attributes: plan.RequiresReplace}
+			case plan != nil:
+				newState, updateDiagnostics, err = providerManager.ApplyResource(ctx, providerConfig, record.ResourceType, record.GetResourceState(), args.Config, &types.ResourceState{
+					State:   plan.PlannedState,
+					Private: plan.PlannedPrivate,
+				})
+			default:
+				newState, updateDiagnostics, err = providerManager.UpdateResource(ctx, providerConfig, record.ResourceType, record.GetResourceState(), args.Config)
+			}
+			return err
+		})
+		diagnostics = append(diagnostics, updateDiagnostics...)
+
+		var replaceErr *types.ReplaceRequiredError
+		if errors.As(err, &replaceErr) && args.ReplaceStrategy != "" {
+			replacing = true
+			return replace(ctx, storage, providerManager, providerConfig, record, args, plan, policy, replaceErr.Attributes, diagnostics)
+		}
+
+		if errors.As(err, &replaceErr) {
+			response := map[string]any{
+				"provider":         record.GetProvider().Name,
+				"provider_version": record.GetProvider().Version,
+				"resource_id":      args.ResourceID,
+				"status":           "replace-required",
+				"requires_replace": replaceErr.Attributes,
+				"message": "The change cannot be applied in place, nothing was changed. Retry with replace_strategy " +
+					"'destroy-before-create' or 'create-before-destroy' after the user confirms replacing the resource",
+			}
+			if len(diagnostics) > 0 {
+				response["diagnostics"] = diagnostics
+			}
+
+			return i.RespondJSON(i.MarkDevOverride(response, providerManager, record.GetProvider().Name))
+		}
+
+		// An interrupted or timed out apply may have changed the resource partway, its partial state is saved
+		ctx, interrupted := markInterrupted(ctx, err, &operation)
+		if interrupted != nil {
+			newState = interrupted.State
+		}
+
+		if err != nil && newState.IsEmpty() {
 			err = fmt.Errorf("failed to update resource: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
 		}
+		updateErr := err
 
 		// Handle empty state case
-		if len(state) == 0 {
-			return i.NewToolResultText("{}"), nil
+		if newState.IsEmpty() {
+			return i.WithDiagnostics(i.NewToolResultText("{}"), diagnostics), nil
 		}
 
+		state := newState.State
+
 		// Update state in database
 		updatedRecord := types.StateRecord{
 			ResourceID:      args.ResourceID,
@@ -115,6 +243,11 @@ func update(storage types.Storage, providerManager types.ProviderManager, regist
 			ProviderVersion: record.ProviderVersion,
 			ResourceType:    record.ResourceType,
 			State:           state,
+			ProviderConfig:  record.ProviderConfig,
+			ProviderAlias:   record.ProviderAlias,
+			Private:         newState.Private,
+			SchemaVersion:   i.PtrTo(newState.SchemaVersion),
+			DevOverride:     providerManager.IsDevOverride(record.Provider),
 			CreatedAt:       record.CreatedAt, // Keep original creation time
 		}
 
@@ -124,15 +257,88 @@ func update(storage types.Storage, providerManager types.ProviderManager, regist
 
 		if err = storage.SaveState(ctx, updatedRecord); err != nil {
 			err = fmt.Errorf("failed to save updated state: %w", err)
-			return i.NewToolResultError(err.Error()), nil
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		if updateErr != nil {
+			err = fmt.Errorf("failed to update resource: %w", updateErr)
+			return i.WithDiagnostics(i.NewToolResultError(err.Error()), diagnostics), nil
+		}
+
+		if plan != nil {
+			storage.DeletePlan(ctx, plan.ID)
 		}
 
-		return i.RespondJSON(map[string]any{
+		response := map[string]any{
 			"provider":         record.GetProvider().Name,
 			"provider_version": record.GetProvider().Version,
 			"resource_id":      args.ResourceID,
 			"result":           state,
 			"status":           "updated",
-		})
+		}
+		if len(warnings) > 0 {
+			response["warnings"] = warnings
+		}
+		if len(diagnostics) > 0 {
+			response["diagnostics"] = diagnostics
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(response, providerManager, record.GetProvider().Name))
+	})
+}
+
+// replace replaces a resource that cannot be updated in place and saves the new state under the same resource ID.
+// The diagnostics of the replacement are reported after those of the update that required it.
+func replace(ctx context.Context, storage types.Storage, providerManager types.ProviderManager, providerConfig *types.ProviderConfig, record *types.StateRecord, args updateArgs, plan *types.PlanRecord, policy types.ApplyPolicy, requiresReplace []string, diagnostics types.Diagnostics) (*mcp.CallToolResult, error) {
+	newState, replaceDiagnostics, replaceErr := replaceResource(ctx, storage, providerManager, providerConfig, record, args.Config, args.ReplaceStrategy, policy)
+	diagnostics = append(diagnostics, replaceDiagnostics...)
+	if newState.IsEmpty() {
+		return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to replace resource: %v", replaceErr)), diagnostics), nil
+	}
+
+	// A replacement interrupted by a cancelled tool call may have created the resource partway, its state is still saved
+	if replaceErr != nil {
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	updatedRecord := *record
+	updatedRecord.State = newState.State
+	updatedRecord.Private = newState.Private
+	updatedRecord.SchemaVersion = i.PtrTo(newState.SchemaVersion)
+	updatedRecord.DevOverride = providerManager.IsDevOverride(record.Provider)
+
+	// Add operation context for automatic history tracking
+	ctx = context.WithValue(ctx, types.OperationContextKey, "replace")
+	ctx = context.WithValue(ctx, types.ChangedByContextKey, "mcp-user")
+	ctx = context.WithValue(ctx, types.DetailsContextKey, map[string]any{
+		"strategy":         args.ReplaceStrategy,
+		"requires_replace": requiresReplace,
 	})
+
+	if err := storage.SaveState(ctx, updatedRecord); err != nil {
+		return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Resource was replaced but saving its state failed: %v", err)), diagnostics), nil
+	}
+
+	if replaceErr != nil {
+		return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to replace resource: %v", replaceErr)), diagnostics), nil
+	}
+
+	if plan != nil {
+		storage.DeletePlan(ctx, plan.ID)
+	}
+
+	response := map[string]any{
+		"provider":         record.GetProvider().Name,
+		"provider_version": record.GetProvider().Version,
+		"resource_id":      args.ResourceID,
+		"result":           newState.State,
+		"requires_replace": requiresReplace,
+		"replace_strategy": args.ReplaceStrategy,
+		"status":           "replaced",
+	}
+	if len(diagnostics) > 0 {
+		response["diagnostics"] = diagnostics
+	}
+
+	return i.RespondJSON(i.MarkDevOverride(response, providerManager, record.GetProvider().Name))
 }
@@ -0,0 +1,321 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type upgradeProviderArgs struct {
+	ResourceID      string `json:"resource_id,omitempty"`
+	Provider        string `json:"provider,omitempty"`
+	FromVersion     string `json:"from_version,omitempty"`
+	ProviderVersion string `json:"provider_version"`
+}
+
+// providerUpgradeResult is the per-resource outcome of a provider version migration
+type providerUpgradeResult struct {
+	ResourceID      string                  `json:"resource_id"`
+	ResourceType    string                  `json:"resource_type"`
+	FromVersion     string                  `json:"from_version"`
+	ToVersion       string                  `json:"to_version"`
+	Status          string                  `json:"status"` // "upgraded", "failed"
+	Error           string                  `json:"error,omitempty"`
+	Changes         []types.AttributeChange `json:"changes,omitempty"`
+	NewDeprecations []string                `json:"new_deprecations,omitempty"`
+	Diagnostics     types.Diagnostics       `json:"diagnostics,omitempty"`
+}
+
+func UpgradeProvider(storage types.Storage, providerManager types.ProviderManager) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("lifecycle-resources-upgrade-provider"),
+		Description: "Move a managed resource, or every managed resource of a provider, to another provider version " +
+			"without ejecting and re-importing. For each resource the stored state is upgraded through the " +
+			"provider's state upgrade, refreshed with the new version and saved with the new provider_version. " +
+			"\n\nMANDATORY PREREQUISITE: call provider-search or provider-describe first to confirm the target version exists. " +
+			"\n\nMEDIUM risk operation: does not change infrastructure, but later updates and deletes will use the new " +
+			"provider version. Always confirm with the user first, listing affected resources and both versions. " +
+			"\n\nPresentation: Report per-resource status, attribute changes detected after the refresh and any attributes " +
+			"that became deprecated in the new version. Suggest config changes for deprecated attributes in use.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Upgrade resources to another provider version", i.OpenWorld)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"resource_id": map[string]any{
+					"type":        "string",
+					"description": "Unique identifier of a single resource to upgrade. Mutually exclusive with provider",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Upgrade every managed resource of this provider (e.g., 'hashicorp/aws'). Mutually exclusive with resource_id",
+				},
+				"from_version": map[string]any{
+					"type":        "string",
+					"description": "Only upgrade resources currently managed with this provider version (used together with provider)",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Target provider version as valid semver (e.g., '6.2.0')",
+				},
+			},
+			Required: []string{"provider_version"},
+		},
+	}, Handler: upgradeProvider(storage, providerManager)}
+}
+
+func upgradeProvider(storage types.Storage, providerManager types.ProviderManager) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args upgradeProviderArgs) (*mcp.CallToolResult, error) {
+		if (args.ResourceID == "") == (args.Provider == "") {
+			return i.NewToolResultError("Exactly one of 'resource_id' or 'provider' must be provided"), nil
+		}
+
+		var records []types.StateRecord
+		if args.ResourceID != "" {
+			record, err := storage.GetState(ctx, args.ResourceID)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to get state: %v", err)), nil
+			}
+			if record == nil {
+				return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found", args.ResourceID)), nil
+			}
+			records = append(records, *record)
+		} else {
+			all, err := storage.ListStates(ctx)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to list states: %v", err)), nil
+			}
+			for _, record := range all {
+				if record.Provider != args.Provider {
+					continue
+				}
+				if args.FromVersion != "" && record.ProviderVersion != args.FromVersion {
+					continue
+				}
+				records = append(records, record)
+			}
+		}
+
+		results := []providerUpgradeResult{}
+		var upgraded, failed, skipped int
+		for _, record := range records {
+			if record.ProviderVersion == args.ProviderVersion {
+				skipped++
+				continue
+			}
+
+			result := upgradeResourceProvider(ctx, storage, providerManager, record, args.ProviderVersion)
+			if result.Status == "upgraded" {
+				upgraded++
+			} else {
+				failed++
+			}
+			results = append(results, result)
+		}
+
+		if args.ResourceID != "" && failed > 0 {
+			return i.WithDiagnostics(i.NewToolResultError(fmt.Sprintf("Failed to upgrade resource '%s': %s", args.ResourceID, results[0].Error)), results[0].Diagnostics), nil
+		}
+
+		return i.RespondJSON(map[string]any{
+			"provider_version": args.ProviderVersion,
+			"results":          results,
+			"upgraded":         upgraded,
+			"failed":           failed,
+			"skipped":          skipped,
+		})
+	})
+}
+
+// upgradeResourceProvider moves a single resource to the target provider version and records the operation
+func upgradeResourceProvider(ctx context.Context, storage types.Storage, providerManager types.ProviderManager, record types.StateRecord, targetVersion string) (result providerUpgradeResult) {
+	result = providerUpgradeResult{
+		ResourceID:   record.ResourceID,
+		ResourceType: record.ResourceType,
+		FromVersion:  record.ProviderVersion,
+		ToVersion:    targetVersion,
+		Status:       "upgraded",
+	}
+
+	fromConfig := record.GetProvider()
+	if err := configs.Resolve(ctx, storage, fromConfig); err != nil {
+		result.Status, result.Error = "failed", err.Error()
+		return result
+	}
+
+	toConfig := *fromConfig
+	toConfig.Version = targetVersion
+
+	operation, err := newResourceOperation(types.ResourceOperationInput{
+		ResourceID:              record.ResourceID,
+		ResourceType:            record.ResourceType,
+		Provider:                record.Provider,
+		ProviderVersion:         targetVersion,
+		PreviousProviderVersion: record.ProviderVersion,
+		Operation:               "upgrade-provider",
+		CurrentState:            record.State,
+	})
+	if err != nil {
+		result.Status, result.Error = "failed", err.Error()
+		return result
+	}
+
+	defer func() {
+		if err != nil {
+			errMessage := err.Error()
+			operation.Failed = &errMessage
+			result.Status, result.Error = "failed", errMessage
+		}
+		operation.Diagnostics = result.Diagnostics
+		storage.SaveResourceOperation(ctx, operation)
+	}()
+
+	toDescription, err := providerManager.DescribeResource(ctx, &toConfig, record.ResourceType)
+	if err != nil {
+		err = fmt.Errorf("resource type not available in %s@%s: %w", record.Provider, targetVersion, err)
+		return result
+	}
+
+	// The previous version is only needed to compare deprecations and, for older records, the schema version
+	var fromDescription *types.TypeDescription
+	if record.ProviderVersion != "" {
+		fromDescription, err = providerManager.DescribeResource(ctx, fromConfig, record.ResourceType)
+		if err != nil {
+			err = fmt.Errorf("failed to describe resource with %s@%s: %w", record.Provider, record.ProviderVersion, err)
+			return result
+		}
+	}
+
+	currentState := record.GetResourceState()
+	if record.SchemaVersion == nil && fromDescription != nil {
+		currentState.SchemaVersion = fromDescription.SchemaVersion
+	}
+
+	upgradedState, diagnostics, err := providerManager.UpgradeResourceState(ctx, &toConfig, record.ResourceType, currentState)
+	result.Diagnostics = diagnostics
+	if err != nil {
+		err = fmt.Errorf("failed to upgrade state: %w", err)
+		return result
+	}
+
+	refreshedState, diagnostics, err := providerManager.RefreshResource(ctx, &toConfig, record.ResourceType, upgradedState)
+	result.Diagnostics = append(result.Diagnostics, diagnostics...)
+	if err != nil {
+		err = fmt.Errorf("failed to refresh resource: %w", err)
+		return result
+	}
+
+	if refreshedState.IsEmpty() {
+		err = fmt.Errorf("resource appears to have been deleted externally, refresh it first")
+		return result
+	}
+
+	result.Changes = diffAttributes(record.State, refreshedState.State)
+	result.NewDeprecations = newDeprecations(fromDescription, toDescription)
+
+	operation.ProposedState = refreshedState.State
+
+	updatedRecord := record
+	updatedRecord.ProviderVersion = targetVersion
+	updatedRecord.State = refreshedState.State
+	updatedRecord.Private = refreshedState.Private
+	updatedRecord.SchemaVersion = i.PtrTo(refreshedState.SchemaVersion)
+	updatedRecord.DevOverride = providerManager.IsDevOverride(record.Provider)
+
+	// Add operation context for automatic history tracking
+	ctx = context.WithValue(ctx, types.OperationContextKey, "upgrade-provider")
+	ctx = context.WithValue(ctx, types.ChangedByContextKey, "mcp-user")
+	ctx = context.WithValue(ctx, types.DetailsContextKey, map[string]any{
+		"from_version": record.ProviderVersion,
+		"to_version":   targetVersion,
+	})
+
+	if err = storage.SaveState(ctx, updatedRecord); err != nil {
+		err = fmt.Errorf("failed to save upgraded state: %w", err)
+		return result
+	}
+
+	return result
+}
+
+// diffAttributes compares top-level attributes of two states, in the vocabulary of planned changes
+func diffAttributes(before, after map[string]any) []types.AttributeChange {
+	var changes []types.AttributeChange
+
+	keys := slices.Sorted(maps.Keys(before))
+	for _, key := range slices.Sorted(maps.Keys(after)) {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		beforeValue, inBefore := before[key]
+		afterValue, inAfter := after[key]
+
+		switch {
+		case !inBefore && afterValue != nil:
+			changes = append(changes, types.AttributeChange{Attribute: key, Action: "add", After: afterValue})
+		case !inAfter && beforeValue != nil:
+			changes = append(changes, types.AttributeChange{Attribute: key, Action: "remove", Before: beforeValue})
+		case inBefore && inAfter && !reflect.DeepEqual(beforeValue, afterValue):
+			changes = append(changes, types.AttributeChange{Attribute: key, Action: "update", Before: beforeValue, After: afterValue})
+		}
+	}
+
+	return changes
+}
+
+// newDeprecations returns attribute paths deprecated in the new description but not in the old one
+func newDeprecations(before, after *types.TypeDescription) []string {
+	var previous map[string]bool
+	if before != nil {
+		previous = deprecatedAttributes("", before.Properties)
+	}
+
+	var deprecations []string
+	for path := range deprecatedAttributes("", after.Properties) {
+		if !previous[path] {
+			deprecations = append(deprecations, path)
+		}
+	}
+	slices.Sort(deprecations)
+
+	return deprecations
+}
+
+// deprecatedAttributes collects dotted paths of deprecated attributes, including those in nested blocks
+func deprecatedAttributes(prefix string, properties map[string]any) map[string]bool {
+	deprecated := map[string]bool{}
+
+	for name, property := range properties {
+		info, ok := property.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		path := prefix + name
+		if isDeprecated, _ := info["deprecated"].(bool); isDeprecated {
+			deprecated[path] = true
+		}
+
+		if nested, ok := info["properties"].(map[string]any); ok {
+			maps.Copy(deprecated, deprecatedAttributes(path+".", nested))
+		}
+		if nested, ok := info["nested_blocks"].(map[string]any); ok {
+			maps.Copy(deprecated, deprecatedAttributes(path+".", nested))
+		}
+	}
+
+	return deprecated
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// upgradeVersionProviderManager serves two versions of a provider, the newer one with schema version 1.
+// States with a broken attribute cannot be upgraded, the others gain a region when refreshed.
+func upgradeVersionProviderManager() *fakeProviderManager {
+	return &fakeProviderManager{
+		describe: func(providerConfig *types.ProviderConfig, _ string) (*types.TypeDescription, error) {
+			if providerConfig.Version == "6.0.0" {
+				return &types.TypeDescription{SchemaVersion: 1, Properties: map[string]any{
+					"acl": map[string]any{"type": "string", "deprecated": true},
+				}}, nil
+			}
+			return &types.TypeDescription{Properties: map[string]any{
+				"acl": map[string]any{"type": "string"},
+			}}, nil
+		},
+		upgrade: func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+			if _, ok := state.State["broken"]; ok {
+				return nil, nil, errors.New("unsupported legacy state")
+			}
+			return &types.ResourceState{State: state.State, SchemaVersion: 1}, nil, nil
+		},
+		refresh: func(state *types.ResourceState) (*types.ResourceState, types.Diagnostics, error) {
+			refreshed := map[string]any{"region": "eu-west-1"}
+			for name, value := range state.State {
+				refreshed[name] = value
+			}
+			return &types.ResourceState{State: refreshed, SchemaVersion: state.SchemaVersion}, nil, nil
+		},
+	}
+}
+
+func TestUpgradeProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newTestStorage(t)
+
+	for id, record := range map[string]types.StateRecord{
+		"logs":    {ProviderVersion: "5.0.0", State: map[string]any{"acl": "private"}},
+		"assets":  {ProviderVersion: "6.0.0", State: map[string]any{"acl": "public-read"}, SchemaVersion: new(int64)},
+		"archive": {ProviderVersion: "5.0.0", State: map[string]any{"acl": "private", "broken": true}},
+	} {
+		record.ResourceID, record.Provider, record.ResourceType = id, "hashicorp/aws", "aws_s3_bucket"
+		require.NoError(t, store.SaveState(ctx, record))
+	}
+
+	call := func(t *testing.T, args map[string]any) *mcp.CallToolResult {
+		arguments, err := json.Marshal(args)
+		require.NoError(t, err)
+		result, err := upgradeProvider(store, upgradeVersionProviderManager())(ctx, &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Arguments: arguments}})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("upgrades, refreshes and saves every resource of the provider", func(t *testing.T) {
+		result := call(t, map[string]any{"provider": "hashicorp/aws", "provider_version": "6.0.0"})
+		require.False(t, result.IsError)
+
+		var response struct {
+			Results                   []providerUpgradeResult
+			Upgraded, Failed, Skipped int
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+		require.Equal(t, 1, response.Upgraded)
+		require.Equal(t, 1, response.Failed)
+		require.Equal(t, 1, response.Skipped, "resources already on the target version are skipped")
+
+		results := map[string]providerUpgradeResult{}
+		for _, result := range response.Results {
+			results[result.ResourceID] = result
+		}
+		require.Equal(t, []types.AttributeChange{{Attribute: "region", Action: "add", After: "eu-west-1"}}, results["logs"].Changes)
+		require.Equal(t, []string{"acl"}, results["logs"].NewDeprecations)
+		require.Equal(t, "failed", results["archive"].Status)
+		require.Contains(t, results["archive"].Error, "unsupported legacy state")
+
+		logs, err := store.GetState(ctx, "logs")
+		require.NoError(t, err)
+		require.Equal(t, "6.0.0", logs.ProviderVersion)
+		require.Equal(t, map[string]any{"acl": "private", "region": "eu-west-1"}, logs.State)
+		require.Equal(t, int64(1), *logs.SchemaVersion)
+
+		archive, err := store.GetState(ctx, "archive")
+		require.NoError(t, err)
+		require.Equal(t, "5.0.0", archive.ProviderVersion, "a failed upgrade leaves the resource alone")
+
+		ops, err := store.ListResourceOperations(ctx, types.ResourceOperationsArgs{ResourceID: i.PtrTo("archive")})
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "upgrade-provider", ops[0].Operation)
+		require.NotNil(t, ops[0].Failed)
+	})
+
+	t.Run("fails the call for a single resource that cannot be upgraded", func(t *testing.T) {
+		result := call(t, map[string]any{"resource_id": "archive", "provider_version": "6.0.0"})
+		require.True(t, result.IsError)
+		require.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Failed to upgrade resource 'archive': failed to upgrade state: unsupported legacy state")
+	})
+}
+
+func TestDiffAttributes(t *testing.T) {
+	t.Parallel()
+
+	before := map[string]any{
+		"id":        "bucket",
+		"acl":       "private",
+		"tags":      map[string]any{"env": "prod"},
+		"legacy":    "value",
+		"untouched": nil,
+	}
+	after := map[string]any{
+		"id":         "bucket",
+		"acl":        "public-read",
+		"tags":       map[string]any{"env": "prod"},
+		"region":     "eu-west-1",
+		"untouched":  nil,
+		"still_null": nil,
+	}
+
+	require.Equal(t, []types.AttributeChange{
+		{Attribute: "acl", Action: "update", Before: "private", After: "public-read"},
+		{Attribute: "legacy", Action: "remove", Before: "value"},
+		{Attribute: "region", Action: "add", After: "eu-west-1"},
+	}, diffAttributes(before, after))
+}
+
+func TestNewDeprecations(t *testing.T) {
+	t.Parallel()
+
+	before := &types.TypeDescription{Properties: map[string]any{
+		"acl":    map[string]any{"type": "string", "deprecated": true},
+		"bucket": map[string]any{"type": "string", "deprecated": false},
+	}}
+	after := &types.TypeDescription{Properties: map[string]any{
+		"acl":    map[string]any{"type": "string", "deprecated": true},
+		"bucket": map[string]any{"type": "string", "deprecated": true},
+		"versioning": map[string]any{
+			"is_block": true,
+			"properties": map[string]any{
+				"enabled": map[string]any{"type": "boolean", "deprecated": true},
+			},
+		},
+	}}
+
+	require.Equal(t, []string{"bucket", "versioning.enabled"}, newDeprecations(before, after))
+	require.Equal(t, []string{"acl", "bucket", "versioning.enabled"}, newDeprecations(nil, after))
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/tools/provider"
+	"github.com/spacelift-io/spacelift-intent/tools/provider/configs"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type validateArgs struct {
+	ResourceID      string         `json:"resource_id,omitempty"`
+	Config          map[string]any `json:"config"`
+	Provider        string         `json:"provider,omitempty"`
+	ResourceType    string         `json:"resource_type,omitempty"`
+	DataSourceType  string         `json:"data_source_type,omitempty"`
+	ProviderVersion string         `json:"provider_version,omitempty"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
+}
+
+func (args validateArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.Provider,
+		Version: args.ProviderVersion,
+		Alias:   args.ProviderAlias,
+		Config:  args.ProviderConfig,
+	}
+}
+
+func Validate(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("lifecycle-resources-validate"),
+		Description: "Validate a resource or data source configuration with the provider without calling any cloud API. " +
+			"Pass resource_id of an existing resource to validate an update (config is merged over its current state), " +
+			"or provider, provider_version and either resource_type or data_source_type to validate a new configuration. " +
+			"\n\nThe configuration is first checked against the schema for unknown attributes (with 'did you mean' " +
+			"suggestions), type mismatches, missing required attributes, computed-only attributes and block nesting " +
+			"mistakes, then validated by the provider itself. " +
+			"\n\nReturns whether the configuration is valid and the diagnostics, each with its severity, summary, " +
+			"detail and the attribute path it refers to (e.g., 'tags[\"Name\"]', 'rule[0].port'). " +
+			"\n\nLOW risk read-only operation. lifecycle-resources-create and lifecycle-resources-update validate " +
+			"automatically, use this to check a configuration while still drafting it. " +
+			"\n\nPresentation: List errors first, then warnings, each with the attribute it refers to and a suggested fix.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Validate a configuration", i.Readonly|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"config": map[string]any{
+					"type":        "object",
+					"description": "Configuration parameters to validate",
+				},
+				"resource_id": map[string]any{
+					"type":        "string",
+					"description": "Identifier of an existing resource to validate an update for",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws', 'hashicorp/random'). Not used with resource_id",
+				},
+				"resource_type": map[string]any{
+					"type":        "string",
+					"description": "The resource type to validate (e.g., 'random_string', 'aws_instance'). Not used with resource_id",
+				},
+				"data_source_type": map[string]any{
+					"type":        "string",
+					"description": "The data source type to validate (e.g., 'aws_ami'). Mutually exclusive with resource_type",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3'). Not used with resource_id",
+				},
+				"provider_config": map[string]any{
+					"type":        "object",
+					"description": "Optional provider configuration (e.g., {\"region\": \"eu-west-1\"}). Not used with resource_id",
+				},
+				"provider_alias": map[string]any{
+					"type":        "string",
+					"description": "Optional name of a provider configuration defined with provider-configs-set. Not used with resource_id",
+				},
+			},
+			Required: []string{"config"},
+		},
+	}, Handler: validate(storage, providerManager, registryClient)}
+}
+
+func validate(storage types.Storage, providerManager types.ProviderManager, registryClient types.RegistryClient) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args validateArgs) (*mcp.CallToolResult, error) {
+		var diagnostics types.Diagnostics
+		var err error
+
+		if args.ResourceID != "" {
+			if args.Provider != "" || args.ResourceType != "" || args.DataSourceType != "" || args.ProviderVersion != "" {
+				return i.NewToolResultError("resource_id cannot be combined with provider, provider_version, resource_type or data_source_type"), nil
+			}
+
+			record, err := storage.GetState(ctx, args.ResourceID)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to get current state: %v", err)), nil
+			}
+			if record == nil {
+				return i.NewToolResultError(fmt.Sprintf("Resource with ID '%s' not found", args.ResourceID)), nil
+			}
+
+			// If provider version is missing, search for it
+			if record.ProviderVersion == "" {
+				providerResult, err := provider.SearchForProvider(ctx, registryClient, record.Provider)
+				if err != nil {
+					return i.NewToolResultError(fmt.Sprintf("Failed to retrieve provider version: %v", err)), nil
+				}
+				record.ProviderVersion = providerResult.Provider.Version
+			}
+
+			providerConfig := record.GetProvider()
+			if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+				return i.NewToolResultError(err.Error()), nil
+			}
+
+			diagnostics, err = providerManager.ValidateResource(ctx, providerConfig, record.ResourceType, record.GetResourceState(), args.Config)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to validate configuration: %v", err)), nil
+			}
+		} else {
+			if args.Provider == "" || args.ProviderVersion == "" {
+				return i.NewToolResultError("provider and provider_version are required unless resource_id is given"), nil
+			}
+			if (args.ResourceType == "") == (args.DataSourceType == "") {
+				return i.NewToolResultError("exactly one of resource_type and data_source_type is required"), nil
+			}
+
+			providerConfig := args.GetProvider()
+			if err := configs.Resolve(ctx, storage, providerConfig); err != nil {
+				return i.NewToolResultError(err.Error()), nil
+			}
+
+			if args.ResourceType != "" {
+				diagnostics, err = providerManager.ValidateResource(ctx, providerConfig, args.ResourceType, nil, args.Config)
+			} else {
+				diagnostics, err = providerManager.ValidateDataSource(ctx, providerConfig, args.DataSourceType, args.Config)
+			}
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to validate configuration: %v", err)), nil
+			}
+		}
+
+		return i.RespondJSON(map[string]any{
+			"valid":       !diagnostics.HasErrors(),
+			"diagnostics": diagnostics,
+		})
+	})
+}
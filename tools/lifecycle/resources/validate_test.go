@@ -0,0 +1,48 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// validateProviderManager returns fixed diagnostics from validation
+func validateProviderManager(diagnostics types.Diagnostics) *fakeProviderManager {
+	return &fakeProviderManager{
+		validate: func(map[string]any) (types.Diagnostics, error) {
+			return diagnostics, nil
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	warning := types.Diagnostic{Severity: "warning", Summary: "Deprecated attribute", Attribute: "acl"}
+	invalidPort := types.Diagnostic{Severity: "error", Summary: "Invalid port", Detail: "must be below 65536", Attribute: "rule[0].port"}
+
+	t.Run("valid config passes warnings on", func(t *testing.T) {
+		manager := validateProviderManager(types.Diagnostics{warning})
+
+		warnings, invalid := validateConfig(context.Background(), manager, &types.ProviderConfig{}, "example_server", nil, map[string]any{})
+		require.Nil(t, invalid)
+		require.Equal(t, types.Diagnostics{warning}, warnings)
+	})
+
+	t.Run("errors reject the config", func(t *testing.T) {
+		manager := validateProviderManager(types.Diagnostics{warning, invalidPort})
+
+		warnings, invalid := validateConfig(context.Background(), manager, &types.ProviderConfig{}, "example_server", nil, map[string]any{})
+		require.Nil(t, warnings)
+		require.NotNil(t, invalid)
+		require.True(t, invalid.IsError)
+		require.Contains(t, invalid.Content[0].(*mcp.TextContent).Text, `"attribute":"rule[0].port"`)
+	})
+}
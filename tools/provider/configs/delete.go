@@ -0,0 +1,71 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package configs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type deleteArgs struct {
+	Alias string `json:"alias"`
+}
+
+// Delete creates a tool for removing a named provider configuration.
+func Delete(storage types.Storage) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-configs-delete"),
+		Description: "Delete a named provider configuration (alias). Refuses to delete an alias " +
+			"that still manages resources - delete, eject or recreate those resources first. " +
+			"Does not affect any actual infrastructure.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Delete a named provider configuration", i.Destructive|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"alias": map[string]any{
+					"type":        "string",
+					"description": "Name of the provider configuration to delete",
+				},
+			},
+			Required: []string{"alias"},
+		},
+	}, Handler: _delete(storage)}
+}
+
+func _delete(storage types.Storage) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args deleteArgs) (*mcp.CallToolResult, error) {
+		existing, err := storage.GetProviderConfig(ctx, args.Alias)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to get provider configuration: %v", err)), nil
+		}
+
+		if existing == nil {
+			return i.NewToolResultError(fmt.Sprintf("Provider configuration '%s' not found", args.Alias)), nil
+		}
+
+		inUse, err := recordsUsingAlias(ctx, storage, args.Alias)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to check resources using alias: %v", err)), nil
+		}
+
+		if len(inUse) > 0 {
+			return i.NewToolResultError(fmt.Sprintf("Provider configuration '%s' is still used by resources %v", args.Alias, inUse)), nil
+		}
+
+		if err := storage.DeleteProviderConfig(ctx, args.Alias); err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to delete provider configuration: %v", err)), nil
+		}
+
+		return i.RespondJSON(map[string]any{
+			"alias":    args.Alias,
+			"provider": existing.Provider,
+			"status":   "deleted",
+		})
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package configs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// List creates a tool for listing named provider configurations.
+func List(storage types.Storage) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-configs-list"),
+		Description: "List all named provider configurations (aliases) together with the resources " +
+			"managed through each of them. Use this before creating resources to pick the right " +
+			"'provider_alias', e.g. when the same provider is used in several regions or accounts. " +
+			"LOW risk read-only operation.",
+		Annotations: i.PtrTo(i.ToolAnnotations("List named provider configurations", i.Readonly|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}, Handler: list(storage)}
+}
+
+func list(storage types.Storage) i.ToolHandler {
+	return func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		configs, err := storage.ListProviderConfigs(ctx)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to list provider configurations: %v", err)), nil
+		}
+
+		records, err := storage.ListStates(ctx)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to list states: %v", err)), nil
+		}
+
+		usedBy := map[string][]string{}
+		for _, record := range records {
+			if record.ProviderAlias != "" {
+				usedBy[record.ProviderAlias] = append(usedBy[record.ProviderAlias], record.ResourceID)
+			}
+		}
+
+		result := make([]map[string]any, 0, len(configs))
+		for _, config := range configs {
+			resources := usedBy[config.Alias]
+			if resources == nil {
+				resources = []string{}
+			}
+
+			result = append(result, map[string]any{
+				"alias":      config.Alias,
+				"provider":   config.Provider,
+				"config":     config.Config,
+				"created_at": config.CreatedAt,
+				"updated_at": config.UpdatedAt,
+				"used_by":    resources,
+			})
+		}
+
+		return i.RespondJSON(map[string]any{
+			"provider_configs": result,
+			"count":            len(result),
+		})
+	}
+}
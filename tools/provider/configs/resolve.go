@@ -0,0 +1,57 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package configs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+// Resolve fills in the configuration of an aliased provider from storage.
+// Provider configs without an alias are left untouched.
+func Resolve(ctx context.Context, storage types.Storage, providerConfig *types.ProviderConfig) error {
+	if providerConfig.Alias == "" {
+		return nil
+	}
+
+	if len(providerConfig.Config) > 0 {
+		return fmt.Errorf("provider_config and provider_alias are mutually exclusive")
+	}
+
+	named, err := storage.GetProviderConfig(ctx, providerConfig.Alias)
+	if err != nil {
+		return fmt.Errorf("failed to get provider configuration '%s': %w", providerConfig.Alias, err)
+	}
+
+	if named == nil {
+		return fmt.Errorf("provider configuration '%s' not found, define it with provider-configs-set", providerConfig.Alias)
+	}
+
+	if named.Provider != providerConfig.Name {
+		return fmt.Errorf("provider configuration '%s' is for provider '%s', not '%s'", providerConfig.Alias, named.Provider, providerConfig.Name)
+	}
+
+	providerConfig.Config = named.Config
+
+	return nil
+}
+
+// recordsUsingAlias returns IDs of resources managed through the given alias
+func recordsUsingAlias(ctx context.Context, storage types.Storage, alias string) ([]string, error) {
+	records, err := storage.ListStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, record := range records {
+		if record.ProviderAlias == alias {
+			ids = append(ids, record.ResourceID)
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package configs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type setArgs struct {
+	Alias    string         `json:"alias"`
+	Provider string         `json:"provider"`
+	Config   map[string]any `json:"config"`
+}
+
+// Set creates a tool for defining a named provider configuration.
+func Set(storage types.Storage) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-configs-set"),
+		Description: "Define or replace a named provider configuration (alias), e.g. 'aws.eu' for " +
+			"hashicorp/aws in eu-west-1 and 'aws.us' for us-east-1. Resource and data source tools " +
+			"accept the alias as 'provider_alias' instead of repeating 'provider_config'. " +
+			"\n\nResources created with an alias always use its current configuration, so replacing " +
+			"an alias affects later updates, refreshes and deletes of every resource that references it. " +
+			"MEDIUM risk when the alias is already in use - confirm with the user first. " +
+			"\n\nDo not put credentials in the configuration - use environment variables instead.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Define a named provider configuration", i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"alias": map[string]any{
+					"type":        "string",
+					"description": "Unique name for this provider configuration (e.g., 'aws.eu', 'aws.us')",
+				},
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws', 'hashicorp/google')",
+				},
+				"config": map[string]any{
+					"type":        "object",
+					"description": "Provider configuration (e.g., {\"region\": \"eu-west-1\"})",
+				},
+			},
+			Required: []string{"alias", "provider", "config"},
+		},
+	}, Handler: set(storage)}
+}
+
+func set(storage types.Storage) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args setArgs) (*mcp.CallToolResult, error) {
+		if strings.TrimSpace(args.Alias) == "" || strings.ContainsAny(args.Alias, " \t\n") {
+			return i.NewToolResultError(fmt.Sprintf("Invalid alias '%s': must be non-empty and contain no whitespace", args.Alias)), nil
+		}
+
+		if _, _, err := (&types.ProviderConfig{Name: args.Provider}).NamespacedName(); err != nil {
+			return i.NewToolResultError(err.Error()), nil
+		}
+
+		existing, err := storage.GetProviderConfig(ctx, args.Alias)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to get provider configuration: %v", err)), nil
+		}
+
+		if existing != nil && existing.Provider != args.Provider {
+			inUse, err := recordsUsingAlias(ctx, storage, args.Alias)
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to check resources using alias: %v", err)), nil
+			}
+			if len(inUse) > 0 {
+				return i.NewToolResultError(fmt.Sprintf("Alias '%s' configures provider '%s' and is used by resources %v; it cannot be switched to provider '%s'",
+					args.Alias, existing.Provider, inUse, args.Provider)), nil
+			}
+		}
+
+		config := types.NamedProviderConfig{
+			Alias:    args.Alias,
+			Provider: args.Provider,
+			Config:   args.Config,
+		}
+
+		if err := storage.SaveProviderConfig(ctx, config); err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to save provider configuration: %v", err)), nil
+		}
+
+		status := "created"
+		if existing != nil {
+			status = "updated"
+		}
+
+		return i.RespondJSON(map[string]any{
+			"alias":    args.Alias,
+			"provider": args.Provider,
+			"config":   args.Config,
+			"status":   status,
+		})
+	})
+}
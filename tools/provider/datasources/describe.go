@@ -36,9 +36,8 @@ func Describe(providerManager types.ProviderManager) i.Tool {
 			"required arguments, and available outputs before querying external data. Critical " +
 			"for Configuration Phase to validate data source definitions and ensure proper " +
 			"argument handling. " +
-			"\n\nArgument Completion: Use schema to set missing arguments to appropriate " +
-			"defaults: strings to null or '', booleans to null or false, numbers to null or 0, " +
-			"arrays to null or [], objects to null or {}. " +
+			"\n\nArgument Completion: Only required arguments and the optional ones you want to set " +
+			"need to be sent - the server fills the rest from this schema with nulls and empty blocks. " +
 			"\n\nLOW risk read-only operation for schema analysis.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Get schema for a specific data source type", i.Readonly|i.Idempotent)),
 		InputSchema: i.ToolInputSchema{
@@ -70,10 +69,10 @@ func describe(providerManager types.ProviderManager) i.ToolHandler {
 			return i.NewToolResultError(fmt.Sprintf("Failed to describe data source: %v", err)), nil
 		}
 
-		return i.RespondJSON(map[string]any{
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
 			"provider":         args.GetProvider().Name,
 			"provider_version": args.GetProvider().Version,
 			"result":           description,
-		})
+		}, providerManager, args.GetProvider().Name))
 	})
 }
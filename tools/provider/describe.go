@@ -28,7 +28,7 @@ func (args describeArgs) GetProvider() *types.ProviderConfig {
 func Describe(providerManager types.ProviderManager) i.Tool {
 	return i.Tool{Tool: mcp.Tool{
 		Name: string("provider-describe"),
-		Description: "Show the provider configuration, supported resources and supported data sources. " +
+		Description: "Show the provider configuration, supported resources, data sources and ephemeral resources. " +
 			"\n\nMANDATORY PREREQUISITE: You MUST call provider-search first to discover the provider and its available versions before using this tool. " +
 			"Do not assume provider names or versions - always search first. " +
 			"\n\nUse this tool after finding a provider to understand its capabilities before resource creation - essential for discovering available resource types, data sources, and configuration requirements. Critical for the Configuration Phase workflow to validate resource definitions and ensure proper provider argument handling.",
@@ -52,25 +52,24 @@ func Describe(providerManager types.ProviderManager) i.Tool {
 
 func describe(providerManager types.ProviderManager) i.ToolHandler {
 	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args describeArgs) (*mcp.CallToolResult, error) {
-		versions, err := providerManager.GetProviderVersions(ctx, types.ProviderConfig{Name: args.Provider})
-		if err != nil {
-			return i.NewToolResultError(fmt.Sprintf("Failed to get provider versions: %v", err)), nil
-		}
-		versionsStrings := make([]string, len(versions))
-		for i, v := range versions {
-			versionsStrings[i] = v.Version
-		}
+		// Locally built providers are not released, whatever version is requested they are used as built
+		if !providerManager.IsDevOverride(args.Provider) {
+			versions, err := providerManager.GetProviderVersions(ctx, types.ProviderConfig{Name: args.Provider})
+			if err != nil {
+				return i.NewToolResultError(fmt.Sprintf("Failed to get provider versions: %v", err)), nil
+			}
 
-		found := false
-		availableVersions := make([]string, 0, len(versions))
-		for _, v := range versions {
-			availableVersions = append(availableVersions, v.Version)
-			if v.Version == args.Version {
-				found = true
+			found := false
+			availableVersions := make([]string, 0, len(versions))
+			for _, v := range versions {
+				availableVersions = append(availableVersions, v.Version)
+				if v.Version == args.Version {
+					found = true
+				}
+			}
+			if !found {
+				return i.NewToolResultError(fmt.Sprintf("Provider version '%s' not found for provider '%s'. Available versions: %v", args.Version, args.Provider, availableVersions)), nil
 			}
-		}
-		if !found {
-			return i.NewToolResultError(fmt.Sprintf("Provider version '%s' not found for provider '%s'. Available versions: %v", args.Version, args.Provider, availableVersions)), nil
 		}
 
 		schema, confErr, err := providerManager.DescribeProvider(ctx, args.GetProvider())
@@ -88,15 +87,22 @@ func describe(providerManager types.ProviderManager) i.ToolHandler {
 			resourceTypes = append(resourceTypes, resourceType)
 		}
 
-		return i.RespondJSON(map[string]any{
+		ephemeralTypes := make([]string, 0, len(schema.EphemeralResources))
+		for ephemeralType := range schema.EphemeralResources {
+			ephemeralTypes = append(ephemeralTypes, ephemeralType)
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
 			"provider": map[string]any{
 				"provider": args.Provider,
 				"required": schema.Provider.Required,
 				"version":  schema.Version,
+				"protocol": schema.ProtocolVersion,
 			},
-			"data_source_types": dataSourceTypes,
-			"resource_types":    resourceTypes,
-			"config_error":      confErr,
-		})
+			"data_source_types":        dataSourceTypes,
+			"resource_types":           resourceTypes,
+			"ephemeral_resource_types": ephemeralTypes,
+			"config_error":             confErr,
+		}, providerManager, args.Provider))
 	})
 }
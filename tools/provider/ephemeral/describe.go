@@ -0,0 +1,73 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ephemeral
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type describeArgs struct {
+	Provider        string `json:"provider"`
+	EphemeralType   string `json:"ephemeral_resource_type"`
+	ProviderVersion string `json:"provider_version"`
+}
+
+func (args describeArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.Provider,
+		Version: args.ProviderVersion,
+	}
+}
+
+func Describe(providerManager types.ProviderManager) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-ephemeral-resources-describe"),
+		Description: "Get the schema and documentation for a specific ephemeral resource type, such as short-lived " +
+			"credentials or secrets (e.g., 'aws_secretsmanager_secret_version', 'random_password'). " +
+			"\n\nMANDATORY PREREQUISITE: You MUST call provider-search first to discover the provider and its available versions before using this tool. " +
+			"provider-describe lists the ephemeral resource types of a provider. " +
+			"\n\nUse this to find the arguments ephemeral-resources-open needs and the values it returns. " +
+			"\n\nLOW risk read-only operation for schema analysis.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Get schema for a specific ephemeral resource type", i.Readonly|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws', 'hashicorp/random')",
+				},
+				"ephemeral_resource_type": map[string]any{
+					"type":        "string",
+					"description": "The type of the ephemeral resource (e.g., 'random_password', 'aws_secretsmanager_secret_version')",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
+				},
+			},
+			Required: []string{"provider", "ephemeral_resource_type", "provider_version"},
+		},
+	}, Handler: describe(providerManager)}
+}
+
+func describe(providerManager types.ProviderManager) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args describeArgs) (*mcp.CallToolResult, error) {
+		description, err := providerManager.DescribeEphemeralResource(ctx, args.GetProvider(), args.EphemeralType)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to describe ephemeral resource: %v", err)), nil
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
+			"provider":         args.GetProvider().Name,
+			"provider_version": args.GetProvider().Version,
+			"result":           description,
+		}, providerManager, args.GetProvider().Name))
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package functions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type callArgs struct {
+	Provider        string `json:"provider"`
+	ProviderVersion string `json:"provider_version"`
+	Function        string `json:"function"`
+	Arguments       []any  `json:"arguments"`
+}
+
+func (args callArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.Provider,
+		Version: args.ProviderVersion,
+	}
+}
+
+// Call creates a tool for running a provider-defined function.
+func Call(providerManager types.ProviderManager) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-functions-call"),
+		Description: "Run a provider-defined function with the given arguments and return its result, " +
+			"e.g. parse an ARN into its parts with hashicorp/aws 'arn_parse' or build one with 'arn_build'. " +
+			"\n\nMANDATORY PREREQUISITE: Call provider-functions-list first to get the function signature. " +
+			"Pass arguments positionally in the order of its parameters, followed by any number of values for " +
+			"a variadic parameter. Each argument is converted to its parameter type, for dynamic parameters the " +
+			"JSON value is passed as is. " +
+			"\n\nFunctions are deterministic and never call any cloud API: prefer them over constructing or " +
+			"parsing provider-specific formats yourself. " +
+			"\n\nLOW risk read-only operation.",
+		Annotations: i.PtrTo(i.ToolAnnotations("Call a provider function", i.Readonly|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws')",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
+				},
+				"function": map[string]any{
+					"type":        "string",
+					"description": "Function name (e.g., 'arn_parse' or 'provider::aws::arn_parse')",
+				},
+				"arguments": map[string]any{
+					"type":        "array",
+					"description": "Function arguments in parameter order (e.g., [\"arn:aws:iam::123456789012:role/admin\"])",
+				},
+			},
+			Required: []string{"provider", "provider_version", "function"},
+		},
+	}, Handler: call(providerManager)}
+}
+
+func call(providerManager types.ProviderManager) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args callArgs) (*mcp.CallToolResult, error) {
+		result, err := providerManager.CallFunction(ctx, args.GetProvider(), args.Function, args.Arguments)
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to call provider function: %v", err)), nil
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
+			"provider":         args.Provider,
+			"provider_version": args.ProviderVersion,
+			"function":         args.Function,
+			"result":           result,
+		}, providerManager, args.Provider))
+	})
+}
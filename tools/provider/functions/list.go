@@ -0,0 +1,72 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package functions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	i "github.com/spacelift-io/spacelift-intent/tools/internal"
+	"github.com/spacelift-io/spacelift-intent/types"
+)
+
+type listArgs struct {
+	Provider        string `json:"provider"`
+	ProviderVersion string `json:"provider_version"`
+}
+
+func (args listArgs) GetProvider() *types.ProviderConfig {
+	return &types.ProviderConfig{
+		Name:    args.Provider,
+		Version: args.ProviderVersion,
+	}
+}
+
+// List creates a tool for listing the functions a provider defines.
+func List(providerManager types.ProviderManager) i.Tool {
+	return i.Tool{Tool: mcp.Tool{
+		Name: string("provider-functions-list"),
+		Description: "List the functions a provider defines (e.g., 'arn_parse' and 'arn_build' in hashicorp/aws, " +
+			"used as provider::aws::arn_parse in OpenTofu) with their signatures: parameter names, types and " +
+			"descriptions, an optional variadic parameter and the return type. " +
+			"\n\nMANDATORY PREREQUISITE: You MUST call provider-search first to discover the provider and its available versions before using this tool. " +
+			"\n\nUse provider-functions-call to run a function instead of composing or parsing provider-specific " +
+			"string formats (ARNs, IDs, encoded values) yourself. Only newer provider versions define functions, " +
+			"an empty list means the provider has none. " +
+			"\n\nLOW risk read-only operation.",
+		Annotations: i.PtrTo(i.ToolAnnotations("List provider functions", i.Readonly|i.Idempotent)),
+		InputSchema: i.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"provider": map[string]any{
+					"type":        "string",
+					"description": "Provider name (e.g., 'hashicorp/aws')",
+				},
+				"provider_version": map[string]any{
+					"type":        "string",
+					"description": "Provider version as valid semver (e.g., '5.0.0', '1.2.3')",
+				},
+			},
+			Required: []string{"provider", "provider_version"},
+		},
+	}, Handler: list(providerManager)}
+}
+
+func list(providerManager types.ProviderManager) i.ToolHandler {
+	return i.NewTypedToolHandler(func(ctx context.Context, _ *mcp.CallToolRequest, args listArgs) (*mcp.CallToolResult, error) {
+		functions, err := providerManager.ListFunctions(ctx, args.GetProvider())
+		if err != nil {
+			return i.NewToolResultError(fmt.Sprintf("Failed to list provider functions: %v", err)), nil
+		}
+
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
+			"provider":         args.Provider,
+			"provider_version": args.ProviderVersion,
+			"functions":        functions,
+			"count":            len(functions),
+		}, providerManager, args.Provider))
+	})
+}
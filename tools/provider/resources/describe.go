@@ -33,12 +33,9 @@ func Describe(providerManager types.ProviderManager) i.Tool {
 			"\n\nMANDATORY PREREQUISITE: You MUST call provider-search first to discover the provider and its available versions before using this tool. " +
 			"Do not assume provider names or versions - always search first. " +
 			"\n\nEssential for Configuration Completion Strategy - use this to identify ALL required " +
-			"arguments, their types, and validation rules before resource creation. Critical for " +
-			"auto-handling provider argument requirements and preventing 'expected X arguments, " +
-			"got Y' errors through proper schema analysis. " +
-			"\n\nArgument Completion: Use schema to determine appropriate defaults for missing " +
-			"arguments: strings to null or '', booleans to null or false, numbers to null or 0, " +
-			"arrays to null or [], objects to null or {}. " +
+			"arguments, their types, and validation rules before resource creation. " +
+			"\n\nArgument Completion: Only required arguments and the optional ones you want to set " +
+			"need to be sent - the server fills the rest from this schema with nulls and empty blocks. " +
 			"\n\nError Handling: When encountering argument mismatches, use Provider Argument " +
 			"Count Mismatch format showing expected vs received counts with auto-resolution strategy.",
 		Annotations: i.PtrTo(i.ToolAnnotations("Get schema for a specific resource type", i.Readonly|i.Idempotent)),
@@ -71,10 +68,10 @@ func describe(providerManager types.ProviderManager) i.ToolHandler {
 			return i.NewToolResultError(fmt.Sprintf("Failed to describe resource: %v", err)), nil
 		}
 
-		return i.RespondJSON(map[string]any{
+		return i.RespondJSON(i.MarkDevOverride(map[string]any{
 			"provider":         args.GetProvider().Name,
 			"provider_version": args.GetProvider().Version,
 			"result":           description,
-		})
+		}, providerManager, args.GetProvider().Name))
 	})
 }
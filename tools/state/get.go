@@ -69,7 +69,7 @@ func get(storage types.Storage) i.ToolHandler {
 			}
 		}
 
-		return i.RespondJSON(map[string]any{
+		response := map[string]any{
 			"resource_id":      record.ResourceID,
 			"provider":         record.Provider,
 			"provider_version": record.ProviderVersion,
@@ -78,6 +78,11 @@ func get(storage types.Storage) i.ToolHandler {
 			"created_at":       record.CreatedAt,
 			"dependencies":     dependencyIDs,
 			"dependents":       dependentIDs,
-		})
+		}
+		if record.DevOverride {
+			response["dev_override"] = true
+		}
+
+		return i.RespondJSON(response)
 	})
 }
@@ -0,0 +1,60 @@
+// Copyright 2025 Spacelift, Inc. and contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReplaceRequiredError is returned when a change cannot be applied in place because the provider
+// requires replacing the resource
+type ReplaceRequiredError struct {
+	Attributes []string // Attribute paths that force replacement
+}
+
+func (e *ReplaceRequiredError) Error() string {
+	return fmt.Sprintf("change requires replacing the resource, attributes forcing replacement: %s", strings.Join(e.Attributes, ", "))
+}
+
+// InterruptedError is returned when a change was interrupted before the provider finished applying it,
+// because the call was cancelled or the server is shutting down. The provider is asked to stop, and
+// State is the partial state it returned, nil if it returned none in time.
+type InterruptedError struct {
+	State *ResourceState
+	Err   error
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted before the provider finished: %v", e.Err)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// DiagnosticsError is returned when a provider reports error diagnostics. It keeps all of them,
+// warnings included, for tool results and operations to report them structured.
+type DiagnosticsError struct {
+	Diagnostics Diagnostics
+}
+
+func (e *DiagnosticsError) Error() string {
+	var messages []string
+	for _, diag := range e.Diagnostics {
+		if diag.Severity != "error" {
+			continue
+		}
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg += ": " + diag.Detail
+		}
+		if diag.Attribute != "" {
+			msg += " (at " + diag.Attribute + ")"
+		}
+		messages = append(messages, msg)
+	}
+
+	return strings.Join(messages, "; ")
+}
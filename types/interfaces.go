@@ -8,22 +8,35 @@ import (
 	"io"
 )
 
-// ProviderManager interface defines provider management operations
+// ProviderManager interface defines provider management operations. Operations returning Diagnostics
+// return those the provider reported, warnings included, also when they fail.
 type ProviderManager interface {
 	// Provider lifecycle
-	LoadProvider(ctx context.Context, provider *ProviderConfig) error
+	LoadProvider(ctx context.Context, provider *ProviderConfig) (Diagnostics, error)
 	Cleanup(ctx context.Context)
 
 	// Resource operations
-	PlanResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *map[string]any, newConfig map[string]any) (map[string]any, error)
-	CreateResource(ctx context.Context, provider *ProviderConfig, resourceType string, config map[string]any) (map[string]any, error)
-	UpdateResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState, newConfig map[string]any) (map[string]any, error)
-	DeleteResource(ctx context.Context, provider *ProviderConfig, resourceType string, state map[string]any) error
-	RefreshResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState map[string]any) (map[string]any, error)
-	ImportResource(ctx context.Context, provider *ProviderConfig, resourceType, importID string) (map[string]any, error)
+	ValidateResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *ResourceState, config map[string]any) (Diagnostics, error)
+	PlanResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *ResourceState, newConfig map[string]any) (*ResourcePlan, Diagnostics, error)
+	ApplyResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *ResourceState, newConfig map[string]any, plannedState *ResourceState) (*ResourceState, Diagnostics, error)
+	CreateResource(ctx context.Context, provider *ProviderConfig, resourceType string, config map[string]any) (*ResourceState, Diagnostics, error)
+	UpdateResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *ResourceState, newConfig map[string]any) (*ResourceState, Diagnostics, error)
+	DeleteResource(ctx context.Context, provider *ProviderConfig, resourceType string, state *ResourceState) (Diagnostics, error)
+	RefreshResource(ctx context.Context, provider *ProviderConfig, resourceType string, currentState *ResourceState) (*ResourceState, Diagnostics, error)
+	ImportResource(ctx context.Context, provider *ProviderConfig, resourceType string, target ImportTarget) ([]ImportedResource, Diagnostics, error)
+	UpgradeResourceState(ctx context.Context, provider *ProviderConfig, resourceType string, state *ResourceState) (*ResourceState, Diagnostics, error)
+	MoveResourceState(ctx context.Context, provider *ProviderConfig, sourceProvider, sourceType string, state *ResourceState, targetType string) (*ResourceState, Diagnostics, error)
 
 	// Data source operations
-	ReadDataSource(ctx context.Context, provider *ProviderConfig, dataSourceType string, config map[string]any) (map[string]any, error)
+	ValidateDataSource(ctx context.Context, provider *ProviderConfig, dataSourceType string, config map[string]any) (Diagnostics, error)
+	ReadDataSource(ctx context.Context, provider *ProviderConfig, dataSourceType string, config map[string]any) (map[string]any, Diagnostics, error)
+
+	// Ephemeral resource operations
+	OpenEphemeralResource(ctx context.Context, provider *ProviderConfig, ephemeralType string, config map[string]any) (*EphemeralResource, Diagnostics, error)
+
+	// Provider-defined functions
+	ListFunctions(ctx context.Context, provider *ProviderConfig) ([]FunctionDescription, error)
+	CallFunction(ctx context.Context, provider *ProviderConfig, name string, args []any) (any, error)
 
 	// Configuration
 	GetProviderVersion(ctx context.Context, providerName string) (string, error)
@@ -31,6 +44,8 @@ type ProviderManager interface {
 	DescribeProvider(ctx context.Context, providerConfig *ProviderConfig) (*ProviderSchema, *string, error)
 	DescribeResource(ctx context.Context, provider *ProviderConfig, resourceType string) (*TypeDescription, error)
 	DescribeDataSource(ctx context.Context, provider *ProviderConfig, dataSourceType string) (*TypeDescription, error)
+	DescribeEphemeralResource(ctx context.Context, provider *ProviderConfig, ephemeralType string) (*TypeDescription, error)
+	IsDevOverride(providerName string) bool
 
 	// deprecated
 	ListResources(ctx context.Context, provider *ProviderConfig) ([]string, error)
@@ -64,6 +79,17 @@ type Storage interface {
 	// Timeline operations
 	GetTimeline(ctx context.Context, query TimelineQuery) (*TimelineResponse, error)
 
+	// Provider configuration operations
+	SaveProviderConfig(ctx context.Context, config NamedProviderConfig) error
+	GetProviderConfig(ctx context.Context, alias string) (*NamedProviderConfig, error)
+	ListProviderConfigs(ctx context.Context) ([]NamedProviderConfig, error)
+	DeleteProviderConfig(ctx context.Context, alias string) error
+
+	// Plan operations
+	SavePlan(ctx context.Context, plan PlanRecord) error
+	GetPlan(ctx context.Context, id string) (*PlanRecord, error)
+	DeletePlan(ctx context.Context, id string) error
+
 	SaveResourceOperation(ctx context.Context, operation ResourceOperation) error
 	ListResourceOperations(ctx context.Context, args ResourceOperationsArgs) ([]ResourceOperation, error)
 	GetResourceOperation(ctx context.Context, resourceID string) (*ResourceOperation, error)
@@ -4,8 +4,12 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type contextKey string
@@ -13,13 +17,29 @@ type contextKey string
 const (
 	OperationContextKey contextKey = "operation"
 	ChangedByContextKey contextKey = "changed_by"
+	DetailsContextKey   contextKey = "details" // map[string]any stored with the timeline event
 )
 
 // DownloadInfo contains provider download information
 type DownloadInfo struct {
-	DownloadURL string
-	Shasum      string
-	Version     string
+	DownloadURL         string
+	Filename            string
+	Shasum              string
+	ShasumsURL          string
+	ShasumsSignatureURL string
+	SigningKeys         []SigningKey
+	Version             string
+
+	// Set when the provider is installed from a mirror instead of the registry
+	Mirror     string   // Description of the mirror, e.g. "filesystem mirror /usr/share/opentofu/providers"
+	Hashes     []string // Package hashes published by the mirror, in the h1: or zh: format of OpenTofu lock files
+	BinaryPath string   // Provider binary of an unpacked package, nothing has to be downloaded
+}
+
+// SigningKey is a GPG public key the registry publishes for verifying a provider's checksums
+type SigningKey struct {
+	KeyID      string
+	ASCIIArmor string
 }
 
 // TypeDescription contains provider type information
@@ -56,6 +76,11 @@ type StateRecord struct {
 	ProviderVersion string         `json:"provider_version"`
 	ResourceType    string         `json:"resource_type"`
 	State           map[string]any `json:"state"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"` // Provider configuration used to manage this resource
+	ProviderAlias   string         `json:"provider_alias,omitempty"`  // Named provider configuration used to manage this resource
+	Private         []byte         `json:"-"`                         // Opaque provider private data, never shown to clients
+	SchemaVersion   *int64         `json:"schema_version,omitempty"`  // Resource schema version the state was written with, nil if unknown
+	DevOverride     bool           `json:"dev_override,omitempty"`    // Written by a locally built provider binary rather than a released one
 	CreatedAt       string         `json:"created_at"`
 }
 
@@ -63,7 +88,151 @@ func (r StateRecord) GetProvider() *ProviderConfig {
 	return &ProviderConfig{
 		Name:    r.Provider,
 		Version: r.ProviderVersion,
+		Alias:   r.ProviderAlias,
+		Config:  r.ProviderConfig,
+	}
+}
+
+// GetResourceState returns the stored state in the form expected by the provider manager
+func (r StateRecord) GetResourceState() *ResourceState {
+	state := &ResourceState{
+		State:   r.State,
+		Private: r.Private,
+	}
+
+	if r.SchemaVersion != nil {
+		state.SchemaVersion = *r.SchemaVersion
+	}
+
+	return state
+}
+
+// ResourceState is the state of a managed resource as exchanged with a provider
+type ResourceState struct {
+	State         map[string]any
+	Private       []byte // Provider private data (ProviderInternal), must be passed back on subsequent calls
+	SchemaVersion int64  // Resource schema version the state conforms to
+}
+
+// IsEmpty reports whether the provider returned no state, e.g. for a resource deleted outside of management
+func (s *ResourceState) IsEmpty() bool {
+	return s == nil || len(s.State) == 0
+}
+
+// ImportTarget identifies an existing resource to import, either by its import ID or by its resource identity
+type ImportTarget struct {
+	ID              string
+	Identity        map[string]any
+	IdentityVersion *int64 // Identity schema version the identity conforms to, defaults to the current one
+}
+
+// ImportedResource is a resource returned by an import. Some providers return several resources for a
+// single import, e.g. a security group together with its rules.
+type ImportedResource struct {
+	ResourceType string
+	State        *ResourceState
+	Identity     map[string]any // Resource identity, if the provider supports identities for the resource type
+}
+
+// ResourcePlan is the outcome of planning a resource change with a provider
+type ResourcePlan struct {
+	Action          string            `json:"action"` // "create", "update", "replace", "delete", "no-op"
+	Changes         []AttributeChange `json:"changes"`
+	RequiresReplace []string          `json:"requires_replace,omitempty"` // Attribute paths that force replacement
+	PlannedState    *ResourceState    `json:"-"`
+}
+
+// AttributeChange describes a planned or observed change of a single top-level attribute
+type AttributeChange struct {
+	Attribute       string `json:"attribute"`
+	Action          string `json:"action"` // "add", "update", "remove"
+	Before          any    `json:"before,omitempty"`
+	After           any    `json:"after,omitempty"`
+	Computed        bool   `json:"computed,omitempty"`  // Value is only known after apply
+	Sensitive       bool   `json:"sensitive,omitempty"` // The value is or holds sensitive values, which are masked
+	RequiresReplace bool   `json:"requires_replace,omitempty"`
+}
+
+// Diagnostic is a single error or warning reported by a provider
+type Diagnostic struct {
+	Severity  string `json:"severity"` // "error", "warning"
+	Summary   string `json:"summary"`
+	Detail    string `json:"detail,omitempty"`
+	Attribute string `json:"attribute,omitempty"` // Attribute path the diagnostic refers to, e.g. tags["Name"]
+}
+
+// Diagnostics is a list of provider diagnostics
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any of the diagnostics is an error
+func (d Diagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns only the warning diagnostics
+func (d Diagnostics) Warnings() Diagnostics {
+	var warnings Diagnostics
+	for _, diag := range d {
+		if diag.Severity == "warning" {
+			warnings = append(warnings, diag)
+		}
 	}
+	return warnings
+}
+
+// PlanRecord is a saved plan that a later create, update or delete applies by plan ID
+type PlanRecord struct {
+	ID              string         `json:"id"`
+	ResourceID      string         `json:"resource_id"`
+	Operation       string         `json:"operation"` // "create", "update", "delete"
+	Provider        string         `json:"provider"`
+	ProviderVersion string         `json:"provider_version"`
+	ResourceType    string         `json:"resource_type"`
+	ProviderConfig  map[string]any `json:"provider_config,omitempty"`
+	ProviderAlias   string         `json:"provider_alias,omitempty"`
+	Config          map[string]any `json:"config,omitempty"`
+	PriorStateHash  string         `json:"prior_state_hash"` // Hash of the stored state the plan was made against, empty for create
+	PlannedState    map[string]any `json:"planned_state,omitempty"`
+	PlannedPrivate  []byte         `json:"-"`
+	RequiresReplace []string       `json:"requires_replace,omitempty"` // Attribute paths that force replacement
+	CreatedAt       string         `json:"created_at"`
+	ExpiresAt       time.Time      `json:"expires_at"`
+}
+
+// ApplyPolicy controls how mutating resource tools apply changes: the two-phase plan/apply flow and the
+// deadline of the provider calls
+type ApplyPolicy struct {
+	Required   bool          // Create, update and delete refuse to run without a plan_id
+	TTL        time.Duration // How long a plan can be applied after it was made
+	SigningKey []byte        // Key for signing plan IDs, must not be empty
+
+	OperationTimeout time.Duration // Deadline for the provider calls of a create, update or delete, 0 for none
+}
+
+// PluginPolicy limits the provider plugin processes kept running between tool calls
+type PluginPolicy struct {
+	IdleTimeout time.Duration // Stop plugins unused for this long, 0 keeps them running
+	MaxRunning  int           // Stop the least recently used plugins above this many, 0 means no limit
+	StopTimeout time.Duration // How long an interrupted apply waits for the provider to stop and return its partial state
+}
+
+// DevOverrides maps provider names (namespace/type) to locally built provider binaries, or directories
+// containing them, used instead of downloading the provider, like the dev_overrides block of the
+// OpenTofu CLI configuration. Overridden providers are used whatever version is requested.
+type DevOverrides map[string]string
+
+// NamedProviderConfig represents a stored provider configuration that resources reference by alias
+type NamedProviderConfig struct {
+	Alias     string         `json:"alias"`    // e.g. "aws.eu"
+	Provider  string         `json:"provider"` // e.g. "hashicorp/aws"
+	Config    map[string]any `json:"config"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
 }
 
 // FieldMapping represents which fields impact which in a dependency
@@ -85,11 +254,12 @@ type DependencyEdge struct {
 
 // TimelineEvent represents a single event in the system timeline
 type TimelineEvent struct {
-	ID         string `json:"id"`
-	ResourceID string `json:"resource_id,omitempty"` // Empty for global events
-	Operation  string `json:"operation"`             // "create", "update", "delete", "import", "eject", "refresh"
-	ChangedBy  string `json:"changed_by"`            // Who/what triggered the change
-	CreatedAt  string `json:"created_at"`
+	ID         string         `json:"id"`
+	ResourceID string         `json:"resource_id,omitempty"` // Empty for global events
+	Operation  string         `json:"operation"`             // "create", "update", "delete", "import", "eject", "refresh", "upgrade", "upgrade-provider", "replace"
+	ChangedBy  string         `json:"changed_by"`            // Who/what triggered the change
+	Details    map[string]any `json:"details,omitempty"`     // Operation specific details, e.g. provider versions
+	CreatedAt  string         `json:"created_at"`
 }
 
 // TimelineQuery represents query parameters for timeline requests
@@ -123,13 +293,21 @@ type ResourceOperationInput struct {
 	ResourceType    string         `json:"resource_type"`
 	Provider        string         `json:"provider"`
 	ProviderVersion string         `json:"provider_version"`
-	Operation       string         `json:"operation"` // "create", "update", "delete", "import", "refresh"
+	Operation       string         `json:"operation"` // "create", "update", "delete", "import", "refresh", "upgrade", "upgrade-provider", "move", "replace-delete", "replace-create"
 	CurrentState    map[string]any `json:"current_state,omitempty"`
 	ProposedState   map[string]any `json:"proposed_state,omitempty"`
+
+	PreviousProviderVersion string `json:"previous_provider_version,omitempty"` // Set when the operation moves the resource to another provider version
+	PreviousResourceType    string `json:"previous_resource_type,omitempty"`    // Set when the operation moves the resource to another resource type
+	PreviousProvider        string `json:"previous_provider,omitempty"`         // Set when the operation moves the resource to another provider
 }
 
 type ResourceOperationResult struct {
-	Failed *string `json:"failed,omitempty"` // Error message if operation failed
+	Failed      *string `json:"failed,omitempty"`      // Error message if operation failed
+	Interrupted bool    `json:"interrupted,omitempty"` // The provider was stopped before it finished, e.g. on shutdown
+	TimedOut    bool    `json:"timed_out,omitempty"`   // The operation ran out of the server operation timeout
+
+	Diagnostics Diagnostics `json:"diagnostics,omitempty"` // Errors and warnings the provider reported
 }
 
 type ResourceOperationsArgs struct {
@@ -143,15 +321,48 @@ type ResourceOperationsArgs struct {
 
 // ProviderSchema represents the schema information for a provider
 type ProviderSchema struct {
-	Provider    *TypeDescription
-	Resources   map[string]*TypeDescription
-	DataSources map[string]*TypeDescription
-	Version     string
+	Provider           *TypeDescription
+	Resources          map[string]*TypeDescription
+	DataSources        map[string]*TypeDescription
+	EphemeralResources map[string]*TypeDescription
+	Version            string
+	ProtocolVersion    int // Plugin protocol major version negotiated with the provider, 0 if unknown
+}
+
+// EphemeralResource is an open ephemeral resource. Its values are kept in memory only, are never
+// stored and can be used in write-only attributes through the references
+type EphemeralResource struct {
+	ID           string            `json:"id"`
+	ResourceType string            `json:"resource_type"`
+	Provider     string            `json:"provider"`
+	Values       map[string]any    `json:"values"`     // Sensitive values are redacted
+	References   map[string]string `json:"references"` // Attribute -> reference to use in a resource config
+	RenewAt      *time.Time        `json:"renew_at,omitempty"`
+}
+
+// FunctionDescription describes the signature of a provider-defined function
+type FunctionDescription struct {
+	Name              string              `json:"name"`
+	Summary           string              `json:"summary,omitempty"`
+	Description       string              `json:"description,omitempty"`
+	Parameters        []FunctionParameter `json:"parameters"`
+	VariadicParameter *FunctionParameter  `json:"variadic_parameter,omitempty"` // Accepts any number of further arguments
+	ReturnType        string              `json:"return_type"`
+	Deprecation       string              `json:"deprecation,omitempty"`
+}
+
+// FunctionParameter describes a single parameter of a provider-defined function
+type FunctionParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // e.g. "string", "list of string", "dynamic"
+	Description string `json:"description,omitempty"`
+	AllowNull   bool   `json:"allow_null,omitempty"`
 }
 
 type ProviderConfig struct {
 	Name    string         `json:"name"`
 	Version string         `json:"version"`
+	Alias   string         `json:"alias,omitempty"`
 	Config  map[string]any `json:"config,omitempty"`
 }
 
@@ -169,6 +380,33 @@ func (p *ProviderConfig) FullName() (string, error) {
 	return p.Name + "@" + p.Version, nil
 }
 
+// CacheKey returns a unique key for a configured provider instance.
+// Providers with the same name and version but a different alias or configuration
+// must not share a running plugin, so both are part of the key.
+func (p *ProviderConfig) CacheKey() (string, error) {
+	fullName, err := p.FullName()
+	if err != nil {
+		return "", err
+	}
+
+	if p.Alias != "" {
+		fullName += "." + p.Alias
+	}
+
+	if len(p.Config) == 0 {
+		return fullName, nil
+	}
+
+	configJSON, err := json.Marshal(p.Config)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize provider config: %w", err)
+	}
+
+	sum := sha256.Sum256(configJSON)
+
+	return fullName + "#" + hex.EncodeToString(sum[:8]), nil
+}
+
 // NamespacedName parses and validates that the provider name is properly namespaced
 // Expected format: "namespace/type" (e.g., "hashicorp/aws")
 // Returns namespace and type, or an error if the format is invalid